@@ -0,0 +1,89 @@
+package backend
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultAlertLevel = "error"
+	// Minimum time between two alerts of the same rule, so that a crash loop or a chatty
+	// error does not flood the UI with events
+	alertCooldownMs = 1000
+)
+
+// alertRule is a compiled alerts entry of a process.
+type alertRule struct {
+	pattern   *regexp.Regexp
+	level     string
+	notify    bool
+	mu        sync.Mutex
+	lastFired time.Time
+}
+
+// newAlertRules compiles the alerts of a process. Returns nil when none are set.
+func newAlertRules(cfgs []AlertConfig) ([]*alertRule, error) {
+	rules := make([]*alertRule, 0, len(cfgs))
+	for i, cfg := range cfgs {
+		pattern, err := regexp.Compile(cfg.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("compiling alerts[%d].pattern: %w", i, err)
+		}
+		rule := &alertRule{pattern: pattern, level: defaultAlertLevel}
+		if cfg.Level != nil {
+			rule.level = *cfg.Level
+		}
+		if cfg.Notify != nil {
+			rule.notify = *cfg.Notify
+		}
+		rules = append(rules, rule)
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	return rules, nil
+}
+
+// fire reports whether the rule should fire for a matching entry at now, given its cooldown.
+func (r *alertRule) fire(now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.lastFired.IsZero() && now.Sub(r.lastFired) < alertCooldownMs*time.Millisecond {
+		return false
+	}
+	r.lastFired = now
+	return true
+}
+
+// matchAlerts emits a process-alert event for each rule matching a log entry.
+// Grouped entries (multiline) are matched as a whole and report their first matching line.
+func (s *ProcessService) matchAlerts(processID string, name string, rules []*alertRule, entry ProcessLogData) {
+	for _, rule := range rules {
+		loc := rule.pattern.FindStringIndex(entry.Output)
+		if loc == nil || !rule.fire(time.Now()) {
+			continue
+		}
+		s.emitter.Emit("process-alert", ProcessAlertData{
+			ProcessID: processID,
+			Name:      name,
+			Pattern:   rule.pattern.String(),
+			Level:     rule.level,
+			Notify:    rule.notify,
+			Line:      matchedLine(entry.Output, loc[0]),
+			Timestamp: entry.Timestamp,
+		})
+	}
+}
+
+// matchedLine returns the line of output containing the byte at offset.
+func matchedLine(output string, offset int) string {
+	start := strings.LastIndex(output[:offset], "\n") + 1
+	end := strings.Index(output[offset:], "\n")
+	if end < 0 {
+		return output[start:]
+	}
+	return output[start : offset+end]
+}
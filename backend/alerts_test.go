@@ -0,0 +1,67 @@
+package backend
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewAlertRules(t *testing.T) {
+	t.Parallel()
+
+	rules, err := newAlertRules(nil)
+	if err != nil || rules != nil {
+		t.Errorf("expected no rules without alerts, got %v, %v", rules, err)
+	}
+
+	level := "warn"
+	notify := true
+	rules, err = newAlertRules([]AlertConfig{{Pattern: "ERROR"}, {Pattern: "deprecated", Level: &level, Notify: &notify}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rules) != 2 || rules[0].level != defaultAlertLevel || rules[0].notify || rules[1].level != "warn" || !rules[1].notify {
+		t.Errorf("unexpected rules: %+v", rules)
+	}
+
+	if _, err := newAlertRules([]AlertConfig{{Pattern: "(unclosed"}}); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+}
+
+func TestAlertRuleFire(t *testing.T) {
+	t.Parallel()
+	rule := &alertRule{}
+	now := time.Now()
+
+	if !rule.fire(now) {
+		t.Error("expected the first match to fire")
+	}
+	if rule.fire(now.Add(alertCooldownMs / 2 * time.Millisecond)) {
+		t.Error("expected a match within the cooldown not to fire")
+	}
+	if !rule.fire(now.Add(alertCooldownMs * time.Millisecond)) {
+		t.Error("expected a match after the cooldown to fire")
+	}
+}
+
+func TestMatchedLine(t *testing.T) {
+	t.Parallel()
+	output := "Traceback:\n  File \"app.py\"\nValueError: boom\n"
+
+	cases := []struct {
+		offset   int
+		expected string
+	}{
+		{offset: 0, expected: "Traceback:"},
+		{offset: 15, expected: "  File \"app.py\""},
+		{offset: 29, expected: "ValueError: boom"},
+	}
+	for _, tc := range cases {
+		if got := matchedLine(output, tc.offset); got != tc.expected {
+			t.Errorf("matchedLine(%d) = %q, expected %q", tc.offset, got, tc.expected)
+		}
+	}
+	if got := matchedLine("no newline", 3); got != "no newline" {
+		t.Errorf("expected the whole output without newline, got %q", got)
+	}
+}
@@ -0,0 +1,428 @@
+package backend
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	cliPollIntervalMs = 200
+	// Extra time allowed on top of the stop timeouts of the processes
+	cliShutdownMarginMs   = 1000
+	cliUsage              = "usage: click-launch <up|down|status|logs> <config> [options] | click-launch schema"
+	cliFollowPollInterval = 200 * time.Millisecond
+)
+
+// IsCLICommand reports whether arg is a headless subcommand, in which case main should call RunCLI
+// instead of opening a window.
+func IsCLICommand(arg string) bool {
+	switch arg {
+	case "up", "down", "status", "logs", "schema", "help", "-h", "--help":
+		return true
+	default:
+		return false
+	}
+}
+
+// RunCLI runs a headless subcommand against a config file and returns the process exit code.
+func RunCLI(args []string) int {
+	c := &cli{
+		stdout:   os.Stdout,
+		stderr:   os.Stderr,
+		stateDir: defaultRunStateDir(),
+		color:    os.Getenv("NO_COLOR") == "" && isTerminal(os.Stdout),
+	}
+	return c.run(args)
+}
+
+// cli holds the I/O and state locations used by headless subcommands.
+type cli struct {
+	stdout   io.Writer
+	stderr   io.Writer
+	stateDir string
+	color    bool
+	// interrupt is used instead of OS signals when set (tests)
+	interrupt <-chan os.Signal
+}
+
+func (c *cli) run(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(c.stderr, cliUsage)
+		return 2
+	}
+	switch args[0] {
+	case "up":
+		return c.up(args[1:])
+	case "down":
+		return c.down(args[1:])
+	case "status":
+		return c.status(args[1:])
+	case "logs":
+		return c.logs(args[1:])
+	case "schema":
+		return c.schema(args[1:])
+	case "help", "-h", "--help":
+		c.printHelp()
+		return 0
+	default:
+		fmt.Fprintf(c.stderr, "unknown command: %s\n%s\n", args[0], cliUsage)
+		return 2
+	}
+}
+
+func (c *cli) printHelp() {
+	fmt.Fprintln(c.stdout, cliUsage)
+	fmt.Fprintln(c.stdout)
+	fmt.Fprintln(c.stdout, "Commands:")
+	fmt.Fprintln(c.stdout, "  up <config> [--only a,b]   Start processes (and their dependencies) and stream their logs")
+	fmt.Fprintln(c.stdout, "     [--profile name]        or the processes of a profile, with its args and env")
+	fmt.Fprintln(c.stdout, "  down <config>              Stop a stack started with up")
+	fmt.Fprintln(c.stdout, "  status <config>            Show the status of each process")
+	fmt.Fprintln(c.stdout, "  logs <config> <name> [-f]  Print the logs of a process, optionally following them")
+	fmt.Fprintln(c.stdout, "  schema                     Print the JSON Schema of the config file")
+}
+
+// --- up ---
+
+func (c *cli) up(args []string) int {
+	fs := flag.NewFlagSet("up", flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	only := fs.String("only", "", "comma-separated process names to start (dependencies are included)")
+	profile := fs.String("profile", "", "profile to start, with its arg values and env")
+	positional, err := parseInterleaved(fs, args)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 1 || (*only != "" && *profile != "") {
+		fmt.Fprintln(c.stderr, "usage: click-launch up <config> [--only name,... | --profile name]")
+		return 2
+	}
+
+	configPath, result, ok := c.loadConfig(positional[0])
+	if !ok {
+		return 1
+	}
+	selected, err := selectProcesses(result.Config, *only)
+	if err != nil {
+		fmt.Fprintln(c.stderr, err)
+		return 2
+	}
+	specs := buildProcessSpecs(result.Config, result.RootDirectory)
+	if *profile != "" {
+		p, exists := result.Config.Profiles[*profile]
+		if !exists {
+			fmt.Fprintf(c.stderr, "unknown profile: %s\n", *profile)
+			return 2
+		}
+		selected = p.Processes
+		specs = buildProfileSpecs(result.Config, result.RootDirectory, p)
+	}
+
+	run, err := openRunState(c.stateDir, configPath)
+	if err != nil {
+		fmt.Fprintln(c.stderr, err)
+		return 1
+	}
+	if pid, alive := run.supervisorAlive(); alive {
+		fmt.Fprintf(c.stderr, "%s is already running (pid %d), use `click-launch down` first\n", configPath, pid)
+		return 1
+	}
+	if err := run.reset(); err != nil {
+		fmt.Fprintln(c.stderr, err)
+		return 1
+	}
+
+	names := make([]string, len(result.Config.Processes))
+	for i, p := range result.Config.Processes {
+		names[i] = p.Name
+	}
+	emitter := newCLIEmitter(c.stdout, names, c.color, run)
+	defer emitter.close()
+	svc := newHeadlessProcessService(emitter, run.appendLog)
+
+	control, err := startControlServer(svc, configPath, run.controlPath())
+	if err != nil {
+		fmt.Fprintf(c.stderr, "control API disabled: %s\n", err)
+	} else {
+		defer control.close()
+	}
+
+	interrupt := c.interrupt
+	if interrupt == nil {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(signals)
+		interrupt = signals
+	}
+
+	for _, name := range selected {
+		for n, r := range svc.StartWithDependencies(name, specs) {
+			if r.Success {
+				emitter.register(r.ProcessID, n)
+				continue
+			}
+			emitter.printStatus(n, "failed to start: "+r.Error)
+			c.shutdown(svc, run, names)
+			return 1
+		}
+	}
+	_ = run.writeStatus(svc, names)
+
+	ticker := time.NewTicker(cliPollIntervalMs * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-interrupt:
+			fmt.Fprintln(c.stdout, "Stopping all processes...")
+			c.shutdown(svc, run, names)
+			return 0
+		case <-ticker.C:
+			if svc.activeProcessCount() == 0 {
+				c.shutdown(svc, run, names)
+				if emitter.hasFailures() {
+					return 1
+				}
+				return 0
+			}
+			_ = run.writeStatus(svc, names)
+		}
+	}
+}
+
+// shutdown stops every process, waits for them to exit, and clears the run state.
+func (c *cli) shutdown(svc *ProcessService, run *runState, names []string) {
+	deadline := time.Now().Add(svc.maxStopWaitTimeout() + cliShutdownMarginMs*time.Millisecond)
+	svc.StopAll()
+	for svc.activeProcessCount() > 0 && time.Now().Before(deadline) {
+		time.Sleep(cliPollIntervalMs * time.Millisecond / 4)
+	}
+	_ = run.writeStatus(svc, names)
+	_ = run.clearSupervisor()
+}
+
+// --- down ---
+
+func (c *cli) down(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(c.stderr, "usage: click-launch down <config>")
+		return 2
+	}
+	run, err := c.openExistingRun(args[0])
+	if err != nil {
+		fmt.Fprintln(c.stderr, err)
+		return 1
+	}
+	pid, alive := run.supervisorAlive()
+	if !alive {
+		fmt.Fprintln(c.stdout, "Nothing is running")
+		return 0
+	}
+
+	if err := syscall.Kill(pid, syscall.SIGTERM); err != nil {
+		fmt.Fprintf(c.stderr, "failed to stop pid %d: %s\n", pid, err)
+		return 1
+	}
+	// Falls back to the default stop timeout if the config can no longer be loaded
+	config, _, _ := loadConfigFile(args[0])
+	deadline := time.Now().Add(maxStopWaitTimeout(config) + 2*cliShutdownMarginMs*time.Millisecond)
+	for time.Now().Before(deadline) {
+		if _, alive := run.supervisorAlive(); !alive {
+			fmt.Fprintln(c.stdout, "Stopped")
+			return 0
+		}
+		time.Sleep(cliPollIntervalMs * time.Millisecond)
+	}
+	fmt.Fprintf(c.stderr, "pid %d did not stop in time\n", pid)
+	return 1
+}
+
+// --- status ---
+
+func (c *cli) status(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(c.stderr, "usage: click-launch status <config>")
+		return 2
+	}
+	run, err := c.openExistingRun(args[0])
+	if err != nil {
+		fmt.Fprintln(c.stderr, err)
+		return 1
+	}
+	snapshot, err := run.read()
+	_, alive := run.supervisorAlive()
+	if err != nil || !alive {
+		fmt.Fprintln(c.stdout, "Nothing is running")
+		return 0
+	}
+
+	width := len("NAME")
+	for _, p := range snapshot.Processes {
+		width = max(width, len(p.Name))
+	}
+	fmt.Fprintf(c.stdout, "%-*s  %-10s  %s\n", width, "NAME", "STATUS", "PID")
+	for _, p := range snapshot.Processes {
+		pid := "-"
+		if p.Pid > 0 {
+			pid = fmt.Sprintf("%d", p.Pid)
+		}
+		fmt.Fprintf(c.stdout, "%-*s  %-10s  %s\n", width, p.Name, p.Status, pid)
+	}
+	return 0
+}
+
+// --- logs ---
+
+func (c *cli) logs(args []string) int {
+	fs := flag.NewFlagSet("logs", flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	follow := fs.Bool("f", false, "keep printing new lines as they are written")
+	positional, err := parseInterleaved(fs, args)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 2 {
+		fmt.Fprintln(c.stderr, "usage: click-launch logs <config> <name> [-f]")
+		return 2
+	}
+
+	run, err := c.openExistingRun(positional[0])
+	if err != nil {
+		fmt.Fprintln(c.stderr, err)
+		return 1
+	}
+	file, err := os.Open(run.logPath(positional[1]))
+	if err != nil {
+		fmt.Fprintf(c.stderr, "no logs for %q\n", positional[1])
+		return 1
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(c.stdout, file); err != nil {
+		fmt.Fprintln(c.stderr, err)
+		return 1
+	}
+	if !*follow {
+		return 0
+	}
+
+	interrupt := c.interrupt
+	if interrupt == nil {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(signals)
+		interrupt = signals
+	}
+	ticker := time.NewTicker(cliFollowPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-interrupt:
+			return 0
+		case <-ticker.C:
+			if _, err := io.Copy(c.stdout, file); err != nil {
+				fmt.Fprintln(c.stderr, err)
+				return 1
+			}
+		}
+	}
+}
+
+// --- schema ---
+
+func (c *cli) schema(args []string) int {
+	if len(args) != 0 {
+		fmt.Fprintln(c.stderr, "usage: click-launch schema")
+		return 2
+	}
+	fmt.Fprint(c.stdout, configSchemaJSON())
+	return 0
+}
+
+// --- Helpers ---
+
+// loadConfig validates a config file and prints its errors. Returns the absolute config path.
+func (c *cli) loadConfig(path string) (string, ValidationResult, bool) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		fmt.Fprintln(c.stderr, err)
+		return "", ValidationResult{}, false
+	}
+	result := NewConfigService().Validate(absPath)
+	if !result.IsValid {
+		fmt.Fprintf(c.stderr, "Invalid config %s:\n", absPath)
+		for _, e := range result.Errors {
+			fmt.Fprintf(c.stderr, "  - %s\n", formatValidationError(e))
+		}
+		return "", result, false
+	}
+	return absPath, result, true
+}
+
+func (c *cli) openExistingRun(path string) (*runState, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	return openRunState(c.stateDir, absPath)
+}
+
+// selectProcesses returns the names to start: all processes, or the ones listed in only.
+func selectProcesses(config *YamlConfig, only string) ([]string, error) {
+	known := make(map[string]bool, len(config.Processes))
+	all := make([]string, 0, len(config.Processes))
+	for _, p := range config.Processes {
+		known[p.Name] = true
+		all = append(all, p.Name)
+	}
+	if strings.TrimSpace(only) == "" {
+		return all, nil
+	}
+
+	var selected []string
+	for _, name := range strings.Split(only, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !known[name] {
+			return nil, fmt.Errorf("unknown process: %s", name)
+		}
+		selected = append(selected, name)
+	}
+	if len(selected) == 0 {
+		return nil, errors.New("--only must list at least one process")
+	}
+	return selected, nil
+}
+
+// parseInterleaved parses flags that may appear before, between, or after positional arguments.
+func parseInterleaved(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,179 @@
+package backend
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+var cliColors = []string{"36", "33", "32", "35", "34", "96", "93", "92", "95", "94"}
+
+// cliEvent is a single process event waiting for its process ID to be registered.
+type cliEvent struct {
+	name    string
+	payload any
+}
+
+// cliEmitter is the eventEmitter used in headless mode: it prints prefixed log lines and
+// lifecycle messages to the terminal. Raw output reaches the run state log files through the
+// output sink of the service instead, as rate-limited lines are never emitted.
+type cliEmitter struct {
+	mu       sync.Mutex
+	out      io.Writer
+	run      *runState
+	color    bool
+	width    int
+	colors   map[string]string
+	names    map[string]string
+	pending  map[string][]cliEvent
+	failures bool
+}
+
+func newCLIEmitter(out io.Writer, names []string, color bool, run *runState) *cliEmitter {
+	e := &cliEmitter{
+		out:     out,
+		run:     run,
+		color:   color,
+		colors:  make(map[string]string, len(names)),
+		names:   make(map[string]string),
+		pending: make(map[string][]cliEvent),
+	}
+	for i, name := range names {
+		e.width = max(e.width, len(name))
+		e.colors[name] = cliColors[i%len(cliColors)]
+	}
+	return e
+}
+
+// register maps a process ID to its name and replays the events received before the mapping was known.
+func (e *cliEmitter) register(processID string, name string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.registerLocked(processID, name)
+}
+
+func (e *cliEmitter) registerLocked(processID string, name string) {
+	if _, exists := e.names[processID]; exists {
+		return
+	}
+	e.names[processID] = name
+	for _, event := range e.pending[processID] {
+		e.handle(name, event)
+	}
+	delete(e.pending, processID)
+}
+
+// hasFailures reports whether a process crashed without being restarted.
+func (e *cliEmitter) hasFailures() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.failures
+}
+
+func (e *cliEmitter) close() {
+	if e.run != nil {
+		e.run.closeLogs()
+	}
+}
+
+// Emit implements eventEmitter.
+func (e *cliEmitter) Emit(name string, data ...any) {
+	if len(data) == 0 {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if batch, ok := data[0].([]ProcessLogData); ok {
+		for _, log := range batch {
+			e.dispatch(log.ProcessID, cliEvent{name: name, payload: log})
+		}
+		return
+	}
+
+	var processID string
+	switch payload := data[0].(type) {
+	case ProcessStartedData:
+		// Processes (re)started after up, e.g. through the control API, get a new ID
+		e.registerLocked(payload.ProcessID, payload.Name)
+		return
+	case ProcessCrashData:
+		processID = payload.ProcessID
+	case ProcessRestartData:
+		processID = payload.ProcessID
+	case ProcessReadyData:
+		processID = payload.ProcessID
+	case ProcessUnhealthyData:
+		processID = payload.ProcessID
+	default:
+		return
+	}
+	e.dispatch(processID, cliEvent{name: name, payload: data[0]})
+}
+
+func (e *cliEmitter) dispatch(processID string, event cliEvent) {
+	name, known := e.names[processID]
+	if !known {
+		e.pending[processID] = append(e.pending[processID], event)
+		return
+	}
+	e.handle(name, event)
+}
+
+func (e *cliEmitter) handle(name string, event cliEvent) {
+	switch payload := event.payload.(type) {
+	case ProcessLogData:
+		switch payload.Type {
+		case "exit":
+			switch {
+			case payload.Code != nil:
+				e.print(name, fmt.Sprintf("exited with code %d", *payload.Code))
+			case payload.Signal != nil:
+				e.print(name, "killed by "+*payload.Signal)
+			default:
+				e.print(name, "exited")
+			}
+		default:
+			// Terminal output is line-based, so only complete lines are printed
+			if payload.Partial {
+				return
+			}
+			for _, line := range strings.Split(strings.TrimRight(payload.Output, "\n"), "\n") {
+				e.print(name, line)
+			}
+		}
+	case ProcessCrashData:
+		if payload.WillRestart {
+			e.print(name, "crashed, restarting...")
+		} else {
+			e.failures = true
+			e.print(name, "crashed")
+		}
+	case ProcessRestartData:
+		e.print(name, fmt.Sprintf("restarted (%d/%d)", payload.RetryCount, payload.MaxRetries))
+	case ProcessReadyData:
+		if event.name == "process-ready-timeout" {
+			e.print(name, "did not become ready in time")
+		} else {
+			e.print(name, "ready")
+		}
+	case ProcessUnhealthyData:
+		e.print(name, fmt.Sprintf("unhealthy after %d failed healthchecks, killing it", payload.Failures))
+	}
+}
+
+// printStatus prints a lifecycle message for a process.
+func (e *cliEmitter) printStatus(name string, message string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.print(name, message)
+}
+
+func (e *cliEmitter) print(name string, line string) {
+	prefix := fmt.Sprintf("%-*s |", e.width, name)
+	if e.color {
+		prefix = "\x1b[" + e.colors[name] + "m" + prefix + "\x1b[0m"
+	}
+	fmt.Fprintf(e.out, "%s %s\n", prefix, line)
+}
@@ -0,0 +1,185 @@
+package backend
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// defaultRunStateDir is where `up` records its state so that other subcommands can find it.
+func defaultRunStateDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "click-launch", "run")
+	}
+	return filepath.Join(home, ".click-launch", "run")
+}
+
+// runSnapshot is the on-disk state of a headless `up` session.
+type runSnapshot struct {
+	ConfigPath string               `json:"configPath"`
+	Pid        int                  `json:"pid"`
+	UpdatedAt  string               `json:"updatedAt"`
+	Processes  []runProcessSnapshot `json:"processes"`
+}
+
+type runProcessSnapshot struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Pid    int    `json:"pid,omitempty"`
+}
+
+// runState manages the state directory of one config file: state.json plus one log file per process.
+type runState struct {
+	configPath string
+	dir        string
+
+	mu       sync.Mutex
+	logFiles map[string]*os.File
+}
+
+// openRunState returns the run state for a config, keyed by a hash of its absolute path.
+func openRunState(stateDir string, configPath string) (*runState, error) {
+	sum := sha256.Sum256([]byte(configPath))
+	dir := filepath.Join(stateDir, hex.EncodeToString(sum[:])[:12])
+	if err := os.MkdirAll(dir, 0o755); err != nil { //nolint:gosec // per-user state directory
+		return nil, fmt.Errorf("creating state directory: %w", err)
+	}
+	return &runState{configPath: configPath, dir: dir, logFiles: make(map[string]*os.File)}, nil
+}
+
+func (r *runState) statePath() string {
+	return filepath.Join(r.dir, "state.json")
+}
+
+// controlPath is where `up` publishes its control API URL and token.
+func (r *runState) controlPath() string {
+	return filepath.Join(r.dir, "control.json")
+}
+
+func (r *runState) logPath(name string) string {
+	return filepath.Join(r.dir, unsafeFileNameChars.ReplaceAllString(name, "_")+".log")
+}
+
+// reset removes the state and logs of a previous session.
+func (r *runState) reset() error {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.Name() == "state.json" || strings.HasSuffix(entry.Name(), ".log") {
+			if err := os.Remove(filepath.Join(r.dir, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r *runState) read() (runSnapshot, error) {
+	var snapshot runSnapshot
+	content, err := os.ReadFile(r.statePath())
+	if err != nil {
+		return snapshot, err
+	}
+	err = json.Unmarshal(content, &snapshot)
+	return snapshot, err
+}
+
+// supervisorAlive returns the pid of the `up` session and whether it is still running.
+func (r *runState) supervisorAlive() (int, bool) {
+	snapshot, err := r.read()
+	if err != nil || snapshot.Pid <= 0 {
+		return 0, false
+	}
+	err = syscall.Kill(snapshot.Pid, 0)
+	return snapshot.Pid, err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// writeStatus records the current status of every process, with this process as supervisor.
+func (r *runState) writeStatus(svc *ProcessService, names []string) error {
+	return r.write(os.Getpid(), svc, names)
+}
+
+// clearSupervisor keeps the last known statuses but marks the session as finished.
+func (r *runState) clearSupervisor() error {
+	snapshot, err := r.read()
+	if err != nil {
+		return err
+	}
+	snapshot.Pid = 0
+	return r.save(snapshot)
+}
+
+func (r *runState) write(pid int, svc *ProcessService, names []string) error {
+	snapshot := runSnapshot{
+		ConfigPath: r.configPath,
+		Pid:        pid,
+		UpdatedAt:  time.Now().UTC().Format(time.RFC3339Nano),
+		Processes:  make([]runProcessSnapshot, 0, len(names)),
+	}
+	active := make(map[string]ProcessInfo)
+	for _, info := range svc.listProcesses() {
+		active[info.Name] = info
+	}
+	for _, name := range names {
+		process := runProcessSnapshot{Name: name, Status: processStatusStopped}
+		if info, exists := active[name]; exists {
+			process.Status = info.Status
+			process.Pid = info.Pid
+		}
+		snapshot.Processes = append(snapshot.Processes, process)
+	}
+	return r.save(snapshot)
+}
+
+// save writes the snapshot atomically so readers never see a partial file.
+func (r *runState) save(snapshot runSnapshot) error {
+	content, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := r.statePath() + ".tmp"
+	if err := os.WriteFile(tmp, content, 0o644); err != nil { //nolint:gosec // non-sensitive status file
+		return err
+	}
+	return os.Rename(tmp, r.statePath())
+}
+
+// appendLog appends raw output to the log file of a process.
+func (r *runState) appendLog(name string, output string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	file, exists := r.logFiles[name]
+	if !exists {
+		var err error
+		file, err = os.OpenFile(r.logPath(name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644) //nolint:gosec // per-user log file
+		if err != nil {
+			return
+		}
+		r.logFiles[name] = file
+	}
+	_, _ = io.WriteString(file, output)
+}
+
+func (r *runState) closeLogs() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for name, file := range r.logFiles {
+		_ = file.Close()
+		delete(r.logFiles, name)
+	}
+}
@@ -0,0 +1,312 @@
+package backend
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// --- Test helpers ---
+
+func newTestCLI(t *testing.T) (*cli, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	return &cli{
+		stdout:    stdout,
+		stderr:    stderr,
+		stateDir:  t.TempDir(),
+		interrupt: make(chan os.Signal),
+	}, stdout, stderr
+}
+
+func writeTestConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+const cliTestConfig = `project_name: "CLI"
+processes:
+  - name: "db"
+    base_command: "echo db started"
+  - name: "api"
+    base_command: "echo api"
+    depends_on: ["db"]
+    args:
+      - type: "toggle"
+        name: "Verbose"
+        default: true
+        values:
+          - value: true
+            output: "--verbose"
+          - value: false
+            output: ""
+`
+
+// --- Tests ---
+
+func TestIsCLICommand(t *testing.T) {
+	for _, arg := range []string{"up", "down", "status", "logs", "--help"} {
+		if !IsCLICommand(arg) {
+			t.Errorf("expected %q to be a CLI command", arg)
+		}
+	}
+	for _, arg := range []string{"", "-psn_0_12345", "config.yml"} {
+		if IsCLICommand(arg) {
+			t.Errorf("expected %q not to be a CLI command", arg)
+		}
+	}
+}
+
+func TestSelectProcesses(t *testing.T) {
+	config := &YamlConfig{Processes: []ProcessConfig{{Name: "db"}, {Name: "api"}, {Name: "web"}}}
+
+	all, err := selectProcesses(config, "")
+	if err != nil || strings.Join(all, ",") != "db,api,web" {
+		t.Errorf("expected every process, got %v (%v)", all, err)
+	}
+
+	selected, err := selectProcesses(config, " web, api ,")
+	if err != nil || strings.Join(selected, ",") != "web,api" {
+		t.Errorf("expected web,api, got %v (%v)", selected, err)
+	}
+
+	if _, err := selectProcesses(config, "worker"); err == nil {
+		t.Error("expected an error for an unknown process")
+	}
+	if _, err := selectProcesses(config, ","); err == nil {
+		t.Error("expected an error for an empty selection")
+	}
+}
+
+func TestParseInterleaved(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	follow := fs.Bool("f", false, "")
+	positional, err := parseInterleaved(fs, []string{"config.yml", "-f", "api"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !*follow {
+		t.Error("expected -f to be parsed after a positional argument")
+	}
+	if strings.Join(positional, ",") != "config.yml,api" {
+		t.Errorf("expected config.yml,api, got %v", positional)
+	}
+}
+
+func TestCLIUp(t *testing.T) {
+	c, stdout, stderr := newTestCLI(t)
+	configPath := writeTestConfig(t, cliTestConfig)
+
+	if code := c.run([]string{"up", configPath}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	output := stdout.String()
+	if !strings.Contains(output, "db  | db started") {
+		t.Errorf("expected prefixed db output, got:\n%s", output)
+	}
+	if !strings.Contains(output, "api | api --verbose") {
+		t.Errorf("expected api output with default args, got:\n%s", output)
+	}
+	if !strings.Contains(output, "exited with code 0") {
+		t.Errorf("expected exit messages, got:\n%s", output)
+	}
+
+	t.Run("logs", func(t *testing.T) {
+		stdout.Reset()
+		if code := c.run([]string{"logs", configPath, "db"}); code != 0 {
+			t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, stderr.String())
+		}
+		if stdout.String() != "db started\n" {
+			t.Errorf("expected raw db logs, got %q", stdout.String())
+		}
+		if code := c.run([]string{"logs", configPath, "unknown"}); code != 1 {
+			t.Errorf("expected exit code 1 for unknown process, got %d", code)
+		}
+	})
+
+	t.Run("status", func(t *testing.T) {
+		stdout.Reset()
+		if code := c.run([]string{"status", configPath}); code != 0 {
+			t.Fatalf("expected exit code 0, got %d", code)
+		}
+		if !strings.Contains(stdout.String(), "Nothing is running") {
+			t.Errorf("expected no running session, got %q", stdout.String())
+		}
+	})
+}
+
+func TestCLIUpLogsRateLimited(t *testing.T) {
+	c, stdout, stderr := newTestCLI(t)
+	configPath := writeTestConfig(t, `project_name: "CLI"
+processes:
+  - name: "noisy"
+    base_command: "for i in 1 2 3 4 5; do echo line $i; done"
+    log_rate_limit: 2
+`)
+
+	if code := c.run([]string{"up", configPath}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	if strings.Contains(stdout.String(), "line 5") {
+		t.Errorf("expected lines over the rate limit to be suppressed in the terminal, got:\n%s", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := c.run([]string{"logs", configPath, "noisy"}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	if stdout.String() != "line 1\nline 2\nline 3\nline 4\nline 5\n" {
+		t.Errorf("expected every line in the log file, got %q", stdout.String())
+	}
+}
+
+func TestCLIUpOnly(t *testing.T) {
+	c, stdout, stderr := newTestCLI(t)
+	configPath := writeTestConfig(t, cliTestConfig+`  - name: "worker"
+    base_command: "echo worker"
+`)
+
+	if code := c.run([]string{"up", configPath, "--only", "api"}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	output := stdout.String()
+	if !strings.Contains(output, "db started") {
+		t.Error("expected dependency db to be started")
+	}
+	if strings.Contains(output, "worker |") {
+		t.Error("expected worker not to be started")
+	}
+}
+
+func TestCLIUpProfile(t *testing.T) {
+	c, stdout, stderr := newTestCLI(t)
+	configPath := writeTestConfig(t, cliTestConfig+`  - name: "worker"
+    base_command: "echo worker $QUEUE"
+profiles:
+  quiet:
+    processes: ["api", "worker"]
+    args:
+      api:
+        Verbose: false
+    env:
+      worker:
+        QUEUE: "low"
+`)
+
+	if code := c.run([]string{"up", configPath, "--profile", "quiet"}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	output := stdout.String()
+	if !strings.Contains(output, "db started") || strings.Contains(output, "--verbose") {
+		t.Errorf("expected api to start after db with the profile args, got:\n%s", output)
+	}
+	if !strings.Contains(output, "worker | worker low") {
+		t.Errorf("expected worker to start with the profile env, got:\n%s", output)
+	}
+	if code := c.run([]string{"up", configPath, "--profile", "unknown"}); code != 2 {
+		t.Errorf("expected exit code 2 for unknown profile, got %d", code)
+	}
+	if code := c.run([]string{"up", configPath, "--profile", "quiet", "--only", "db"}); code != 2 {
+		t.Errorf("expected exit code 2 for --only with --profile, got %d", code)
+	}
+}
+
+func TestCLIEmitterRestart(t *testing.T) {
+	out := &bytes.Buffer{}
+	emitter := newCLIEmitter(out, []string{"api"}, false, nil)
+	svc := newHeadlessProcessService(emitter, nil)
+	t.Cleanup(svc.StopAll)
+
+	output := func() string {
+		emitter.mu.Lock()
+		defer emitter.mu.Unlock()
+		return out.String()
+	}
+	waitForRuns := func(count int) bool {
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			if strings.Count(output(), "api | run\n") >= count {
+				return true
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		return false
+	}
+
+	result := svc.StartWithDependencies("api", []ProcessSpec{{Name: "api", Cwd: t.TempDir(), Command: "echo run; sleep 30"}})["api"]
+	emitter.register(result.ProcessID, "api")
+	if !waitForRuns(1) {
+		t.Fatalf("expected the first run output, got:\n%s", output())
+	}
+
+	// Like a restart through the control API: the new run is not registered by up
+	if restarted := svc.restartProcess(result.ProcessID); !restarted.Success {
+		t.Fatalf("expected restart to succeed, got %+v", restarted)
+	}
+	if !waitForRuns(2) {
+		t.Fatalf("expected the restarted run output, got:\n%s", output())
+	}
+	emitter.mu.Lock()
+	pending := len(emitter.pending)
+	emitter.mu.Unlock()
+	if pending != 0 {
+		t.Errorf("expected no pending events, got %d", pending)
+	}
+}
+
+func TestCLIUpFailure(t *testing.T) {
+	c, _, _ := newTestCLI(t)
+	configPath := writeTestConfig(t, `project_name: "CLI"
+processes:
+  - name: "failing"
+    base_command: "exit 3"
+`)
+	if code := c.run([]string{"up", configPath}); code != 1 {
+		t.Errorf("expected exit code 1 when a process crashes, got %d", code)
+	}
+}
+
+func TestCLIInvalidConfig(t *testing.T) {
+	c, _, stderr := newTestCLI(t)
+	configPath := writeTestConfig(t, `project_name: "CLI"
+processes: []
+`)
+	if code := c.run([]string{"up", configPath}); code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "Invalid config") {
+		t.Errorf("expected validation errors, got %q", stderr.String())
+	}
+}
+
+func TestCLISchema(t *testing.T) {
+	c, stdout, stderr := newTestCLI(t)
+	if code := c.run([]string{"schema"}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	if stdout.String() != configSchemaJSON() {
+		t.Errorf("expected the config schema, got:\n%s", stdout.String())
+	}
+}
+
+func TestCLIUsage(t *testing.T) {
+	c, _, _ := newTestCLI(t)
+	if code := c.run(nil); code != 2 {
+		t.Errorf("expected exit code 2 without arguments, got %d", code)
+	}
+	if code := c.run([]string{"restart"}); code != 2 {
+		t.Errorf("expected exit code 2 for an unknown command, got %d", code)
+	}
+	if code := c.run([]string{"up"}); code != 2 {
+		t.Errorf("expected exit code 2 without a config, got %d", code)
+	}
+}
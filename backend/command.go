@@ -0,0 +1,257 @@
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Characters that never need quoting in a POSIX shell word.
+var shellSafePattern = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+const (
+	shellSh   = "sh"
+	shellNone = "none"
+)
+
+// buildCommand appends the output of every arg to the base command (or the shell-quoted argv of command).
+// argValues maps arg names to the value picked in the UI (bool for toggles, the option value for selects,
+// the raw text for inputs). Missing args use their default. Input values are shell-quoted, and their ${VAR}
+// references escaped so that they are passed literally.
+func buildCommand(process ProcessConfig, argValues map[string]any) string {
+	command := process.BaseCommand
+	if len(process.Command) > 0 {
+		quoted := make([]string, len(process.Command))
+		for i, part := range process.Command {
+			quoted[i] = quoteArgvPart(part)
+		}
+		command = strings.Join(quoted, " ")
+	}
+	for _, arg := range process.Args {
+		value, exists := argValues[arg.Name]
+		if !exists {
+			value = arg.Default
+		}
+
+		output := ""
+		switch arg.Type {
+		case "toggle", "select":
+			for _, v := range arg.Values {
+				if argValueEquals(v.Value, value) {
+					output = v.Output
+					break
+				}
+			}
+		case "input":
+			text := ""
+			if value != nil {
+				text = fmt.Sprintf("%v", value)
+			}
+			if text != "" {
+				output = shellQuote(escapeVariables(text))
+				if arg.OutputPrefix != nil && *arg.OutputPrefix != "" {
+					output = *arg.OutputPrefix + " " + output
+				}
+			}
+		}
+		if output != "" {
+			command += " " + output
+		}
+	}
+	return command
+}
+
+// argValueEquals compares arg values across YAML and JSON decoding (e.g. int 3000 vs float64 3000).
+func argValueEquals(a any, b any) bool {
+	return fmt.Sprintf("%v", a) == fmt.Sprintf("%v", b)
+}
+
+// shellQuote returns s as a single POSIX shell word.
+func shellQuote(s string) string {
+	if shellSafePattern.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// quoteArgvPart shell-quotes a part of an argv command, but keeps its ${VAR} references in double quotes
+// so that they are still expanded when the process starts, within the same argument.
+func quoteArgvPart(part string) string {
+	var quoted strings.Builder
+	last := 0
+	for _, match := range variablePattern.FindAllStringIndex(part, -1) {
+		if part[match[0]:match[1]] == "$${" {
+			continue
+		}
+		if match[0] > last {
+			quoted.WriteString(shellQuote(part[last:match[0]]))
+		}
+		quoted.WriteString(`"` + part[match[0]:match[1]] + `"`)
+		last = match[1]
+	}
+	if last < len(part) || quoted.Len() == 0 {
+		quoted.WriteString(shellQuote(part[last:]))
+	}
+	return quoted.String()
+}
+
+// processShell returns the shell used to run a process: the configured one,
+// or none for argv commands and sh for base_command.
+func processShell(process ProcessConfig) string {
+	switch {
+	case process.Shell != nil:
+		return *process.Shell
+	case len(process.Command) > 0:
+		return shellNone
+	default:
+		return shellSh
+	}
+}
+
+// splitShellWords splits a command line into words like a POSIX shell would, without any expansion:
+// quotes and backslashes are honoured, but $VARS, globs, pipes and redirections are kept literally.
+func splitShellWords(command string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	for i := 0; i < len(command); i++ {
+		c := command[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case c == '\\':
+			inWord = true
+			if i+1 < len(command) {
+				i++
+				if command[i] != '\n' {
+					word.WriteByte(command[i])
+				}
+			}
+		case c == '\'':
+			inWord = true
+			end := strings.IndexByte(command[i+1:], '\'')
+			if end < 0 {
+				return nil, errors.New("unterminated single quote")
+			}
+			word.WriteString(command[i+1 : i+1+end])
+			i += end + 1
+		case c == '"':
+			inWord = true
+			closed := false
+			for i++; i < len(command); i++ {
+				if command[i] == '"' {
+					closed = true
+					break
+				}
+				// Inside double quotes, a backslash only escapes these characters
+				if command[i] == '\\' && i+1 < len(command) && strings.IndexByte("\"\\$`", command[i+1]) >= 0 {
+					i++
+				}
+				word.WriteByte(command[i])
+			}
+			if !closed {
+				return nil, errors.New("unterminated double quote")
+			}
+		default:
+			inWord = true
+			word.WriteByte(c)
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// --- Process specs ---
+
+// loadConfigFile reads and validates a config file. Returns the config and its root directory.
+func loadConfigFile(configPath string) (*YamlConfig, string, error) {
+	absPath, err := filepath.Abs(configPath)
+	if err != nil {
+		return nil, "", err
+	}
+	result := NewConfigService().Validate(absPath)
+	if !result.IsValid {
+		messages := make([]string, 0, len(result.Errors))
+		for _, e := range result.Errors {
+			messages = append(messages, formatValidationError(e))
+		}
+		return nil, "", fmt.Errorf("invalid config %s: %s", absPath, strings.Join(messages, "; "))
+	}
+	if result.Config == nil {
+		return nil, "", errors.New("invalid config: empty")
+	}
+	return result.Config, result.RootDirectory, nil
+}
+
+// buildProcessSpec resolves a configured process into a launch spec.
+// argValues and env are the UI overrides (nil for defaults); env is merged over the config env.
+func buildProcessSpec(process ProcessConfig, rootDirectory string, argValues map[string]any, env map[string]string) ProcessSpec {
+	mergedEnv := make(map[string]string, len(process.Env)+len(env))
+	for k, v := range process.Env {
+		mergedEnv[k] = v
+	}
+	for k, v := range env {
+		mergedEnv[k] = v
+	}
+	logFormat := ""
+	if process.LogFormat != nil {
+		logFormat = *process.LogFormat
+	}
+	return ProcessSpec{
+		Name:         process.Name,
+		Cwd:          resolveProcessCwd(rootDirectory, process.Cwd),
+		Command:      buildCommand(process, argValues),
+		Shell:        processShell(process),
+		TTY:          process.TTY != nil && *process.TTY,
+		Stdin:        process.Stdin != nil && *process.Stdin,
+		Restart:      process.Restart,
+		Stop:         process.Stop,
+		Logs:         resolveLogsConfig(rootDirectory, process.Name, process.Logs),
+		LogFormat:    logFormat,
+		LogRateLimit: process.LogRateLimit,
+		Multiline:    process.Multiline,
+		Alerts:       process.Alerts,
+		Env:          mergedEnv,
+		EnvFiles:     process.EnvFile,
+		DependsOn:    process.DependsOn,
+		ReadyWhen:    process.ReadyWhen,
+		Healthcheck:  process.Healthcheck,
+	}
+}
+
+// buildProcessSpecs resolves every process of a config with default arg values.
+func buildProcessSpecs(config *YamlConfig, rootDirectory string) []ProcessSpec {
+	specs := make([]ProcessSpec, 0, len(config.Processes))
+	for _, p := range config.Processes {
+		specs = append(specs, buildProcessSpec(p, rootDirectory, nil, nil))
+	}
+	return specs
+}
+
+// buildProfileSpecs resolves every process of a config with the arg values and env of a profile.
+func buildProfileSpecs(config *YamlConfig, rootDirectory string, profile ProfileConfig) []ProcessSpec {
+	specs := make([]ProcessSpec, 0, len(config.Processes))
+	for _, p := range config.Processes {
+		specs = append(specs, buildProcessSpec(p, rootDirectory, profile.Args[p.Name], profile.Env[p.Name]))
+	}
+	return specs
+}
+
+// resolveProcessCwd resolves a process cwd relative to the config directory.
+func resolveProcessCwd(rootDirectory string, cwd *string) string {
+	if cwd == nil || *cwd == "" {
+		return rootDirectory
+	}
+	if filepath.IsAbs(*cwd) {
+		return *cwd
+	}
+	return filepath.Join(rootDirectory, *cwd)
+}
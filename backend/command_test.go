@@ -0,0 +1,263 @@
+package backend
+
+import (
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBuildCommand(t *testing.T) {
+	t.Parallel()
+
+	prefix := "--port"
+	nameFlag := "--name"
+	process := ProcessConfig{
+		BaseCommand: "npm run dev",
+		Args: []ArgConfig{
+			{Type: "toggle", Name: "Watch", Default: true, Values: []ArgValue{{Value: true, Output: "--watch"}, {Value: false, Output: ""}}},
+			{Type: "select", Name: "Mode", Default: "prod", Values: []ArgValue{{Value: "dev", Output: "--mode dev"}, {Value: "prod", Output: "--mode prod"}}},
+			{Type: "select", Name: "Workers", Default: 2, Values: []ArgValue{{Value: 2, Output: "-w 2"}, {Value: 4, Output: "-w 4"}}},
+			{Type: "input", Name: "Port", Default: 3000, OutputPrefix: &prefix},
+			{Type: "input", Name: "Name", Default: "", OutputPrefix: &nameFlag},
+		},
+	}
+
+	cases := []struct {
+		name      string
+		argValues map[string]any
+		expected  string
+	}{
+		{
+			name:      "defaults",
+			argValues: nil,
+			expected:  "npm run dev --watch --mode prod -w 2 --port 3000",
+		},
+		{
+			name:      "picked values",
+			argValues: map[string]any{"Watch": false, "Mode": "dev", "Port": "8080"},
+			expected:  "npm run dev --mode dev -w 2 --port 8080",
+		},
+		{
+			name:      "JSON numbers match YAML ints",
+			argValues: map[string]any{"Workers": float64(4)},
+			expected:  "npm run dev --watch --mode prod -w 4 --port 3000",
+		},
+		{
+			name:      "input with spaces is quoted",
+			argValues: map[string]any{"Name": "my app"},
+			expected:  "npm run dev --watch --mode prod -w 2 --port 3000 --name 'my app'",
+		},
+		{
+			name:      "input with quotes is escaped",
+			argValues: map[string]any{"Name": `it's "here"`},
+			expected:  `npm run dev --watch --mode prod -w 2 --port 3000 --name 'it'\''s "here"'`,
+		},
+		{
+			name:      "input variables are escaped",
+			argValues: map[string]any{"Name": "${HOME}"},
+			expected:  "npm run dev --watch --mode prod -w 2 --port 3000 --name '$${HOME}'",
+		},
+		{
+			name:      "empty input is omitted",
+			argValues: map[string]any{"Port": ""},
+			expected:  "npm run dev --watch --mode prod -w 2",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := buildCommand(process, tc.argValues); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	t.Parallel()
+
+	inputs := []string{"simple", "with space", `it's`, `"double"`, "$HOME", "a;b", "back\\slash", "multi\nline", ""}
+	for _, input := range inputs {
+		out, err := exec.Command("sh", "-c", "printf %s "+shellQuote(input)).Output()
+		if err != nil {
+			t.Fatalf("shell rejected %q: %v", shellQuote(input), err)
+		}
+		if string(out) != input {
+			t.Errorf("expected the shell to receive %q, got %q", input, string(out))
+		}
+	}
+	if shellQuote("./path/to-file.txt") != "./path/to-file.txt" {
+		t.Error("expected safe words to be left unquoted")
+	}
+}
+
+func TestResolveProcessCwd(t *testing.T) {
+	t.Parallel()
+
+	relative := "./api"
+	absolute := "/srv/api"
+	empty := ""
+	cases := []struct {
+		cwd      *string
+		expected string
+	}{
+		{nil, "/project"},
+		{&empty, "/project"},
+		{&relative, "/project/api"},
+		{&absolute, "/srv/api"},
+	}
+	for _, tc := range cases {
+		if got := resolveProcessCwd("/project", tc.cwd); got != tc.expected {
+			t.Errorf("expected %q, got %q", tc.expected, got)
+		}
+	}
+}
+
+func TestBuildProcessSpec(t *testing.T) {
+	t.Parallel()
+
+	cwd := "web"
+	process := ProcessConfig{
+		Name:        "web",
+		BaseCommand: "serve",
+		Cwd:         &cwd,
+		EnvFile:     EnvFiles{".env"},
+		Env:         map[string]string{"PORT": "3000", "DEBUG": "false"},
+		DependsOn:   []string{"api"},
+	}
+
+	spec := buildProcessSpec(process, "/project", nil, map[string]string{"DEBUG": "true"})
+
+	if spec.Name != "web" || spec.Command != "serve" || spec.Cwd != filepath.Join("/project", "web") || !slices.Equal(spec.EnvFiles, []string{".env"}) {
+		t.Errorf("unexpected spec: %+v", spec)
+	}
+	if spec.Env["PORT"] != "3000" || spec.Env["DEBUG"] != "true" {
+		t.Errorf("expected env overrides merged over the config env, got %v", spec.Env)
+	}
+	if strings.Join(spec.DependsOn, ",") != "api" {
+		t.Errorf("expected dependencies to be kept, got %v", spec.DependsOn)
+	}
+	if process.Env["DEBUG"] != "false" {
+		t.Error("expected the config env to be left untouched")
+	}
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	t.Parallel()
+
+	config, rootDirectory, err := loadConfigFile(filepath.Join("testdata", "valid-yaml.yml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config == nil || !filepath.IsAbs(rootDirectory) {
+		t.Errorf("expected a config and an absolute root directory, got %v %q", config, rootDirectory)
+	}
+
+	if _, _, err := loadConfigFile(filepath.Join("testdata", "invalid-depends-on-config.yml")); err == nil || !strings.Contains(err.Error(), "depends_on") {
+		t.Errorf("expected validation errors, got %v", err)
+	}
+}
+
+func TestBuildCommand_Argv(t *testing.T) {
+	t.Parallel()
+
+	prefix := "--name"
+	process := ProcessConfig{
+		Command: []string{"node", "my server.js"},
+		Args: []ArgConfig{
+			{Type: "select", Name: "Mode", Default: "dev", Values: []ArgValue{{Value: "dev", Output: "--mode dev"}}},
+			{Type: "input", Name: "Name", Default: "it's me", OutputPrefix: &prefix},
+		},
+	}
+
+	command := buildCommand(process, nil)
+
+	expected := `node 'my server.js' --mode dev --name 'it'\''s me'`
+	if command != expected {
+		t.Fatalf("expected %q, got %q", expected, command)
+	}
+	words, err := splitShellWords(command)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(words, "|") != "node|my server.js|--mode|dev|--name|it's me" {
+		t.Errorf("expected the argv to survive the round trip, got %q", words)
+	}
+}
+
+func TestQuoteArgvPart(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		part     string
+		expected string
+	}{
+		{"server.js", "server.js"},
+		{"", "''"},
+		{"${MSG}", `"${MSG}"`},
+		{"msg: ${MSG}!", `'msg: '"${MSG}"'!'`},
+		{"${HOST}:${PORT:-3000}", `"${HOST}":"${PORT:-3000}"`},
+		{"$${MSG} it's", `'$${MSG} it'\''s'`},
+	}
+	for _, tc := range cases {
+		if got := quoteArgvPart(tc.part); got != tc.expected {
+			t.Errorf("%q: expected %q, got %q", tc.part, tc.expected, got)
+		}
+	}
+}
+
+func TestProcessShell(t *testing.T) {
+	t.Parallel()
+
+	bash := "bash"
+	cases := []struct {
+		process  ProcessConfig
+		expected string
+	}{
+		{ProcessConfig{BaseCommand: "echo"}, "sh"},
+		{ProcessConfig{BaseCommand: "echo", Shell: &bash}, "bash"},
+		{ProcessConfig{Command: []string{"echo"}}, "none"},
+	}
+	for _, tc := range cases {
+		if got := processShell(tc.process); got != tc.expected {
+			t.Errorf("expected %q, got %q", tc.expected, got)
+		}
+	}
+}
+
+func TestSplitShellWords(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		command  string
+		expected []string
+	}{
+		{"", nil},
+		{"  npm   run dev ", []string{"npm", "run", "dev"}},
+		{`echo 'single $HOME' "double $HOME"`, []string{"echo", "single $HOME", "double $HOME"}},
+		{`echo "esc \"quote\" \\ \n"`, []string{"echo", `esc "quote" \ \n`}},
+		{`echo a\ b c\\d`, []string{"echo", "a b", `c\d`}},
+		{`echo ''`, []string{"echo", ""}},
+		{"echo 'it'\\''s'", []string{"echo", "it's"}},
+		{"echo a | grep b", []string{"echo", "a", "|", "grep", "b"}},
+	}
+	for _, tc := range cases {
+		words, err := splitShellWords(tc.command)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.command, err)
+			continue
+		}
+		if strings.Join(words, "|") != strings.Join(tc.expected, "|") || len(words) != len(tc.expected) {
+			t.Errorf("%q: expected %q, got %q", tc.command, tc.expected, words)
+		}
+	}
+
+	for _, command := range []string{`echo 'open`, `echo "open`} {
+		if _, err := splitShellWords(command); err == nil {
+			t.Errorf("%q: expected an unterminated quote error", command)
+		}
+	}
+}
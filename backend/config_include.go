@@ -0,0 +1,194 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configSource is a parsed config file: the opened config, or one of the files it includes.
+type configSource struct {
+	// file is the path shown in errors, relative to the opened config ("" for the config itself)
+	file string
+	// path and dir are absolute, and empty for content validated without a file (ExtractYamlConfig)
+	path string
+	dir  string
+	root yaml.Node
+	raw  map[string]any
+}
+
+// processOrigin locates a process of the merged config in the file defining it.
+type processOrigin struct {
+	path   string
+	source *configSource
+}
+
+// parseConfigSource parses the content of a config file. Returns an error for invalid YAML,
+// or a root that is not a mapping.
+func parseConfigSource(content string, file string, path string) (*configSource, *ValidationError) {
+	source := &configSource{file: file, path: path}
+	if path != "" {
+		source.dir = filepath.Dir(path)
+	}
+	var raw any
+	if err := yaml.Unmarshal([]byte(content), &source.root); err != nil {
+		parseErr := yamlParseError(err)
+		parseErr.File = file
+		return nil, &parseErr
+	}
+	if err := source.root.Decode(&raw); err != nil {
+		parseErr := yamlParseError(err)
+		parseErr.File = file
+		return nil, &parseErr
+	}
+	config, ok := raw.(map[string]any)
+	if !ok || config == nil {
+		return nil, &ValidationError{Message: "Invalid YAML file", File: file}
+	}
+	source.raw = config
+	return source, nil
+}
+
+// claim marks errors as coming from this file.
+func (s *configSource) claim(errors []ValidationError) {
+	for i := range errors {
+		errors[i].File = s.file
+	}
+}
+
+// locate sets the line and column of the errors of this file.
+func (s *configSource) locate(errors []ValidationError) {
+	positions := make(map[string]yamlPosition)
+	indexYamlPositions(&s.root, "", positions)
+	for i := range errors {
+		if errors[i].File == s.file {
+			locateValidationErrors(errors[i:i+1], positions)
+		}
+	}
+}
+
+// rebase resolves the paths of a process of an included file from its directory:
+// cwd defaults to the directory of the file, and env files and logs.file become absolute.
+func (s *configSource) rebase(process *ProcessConfig) {
+	cwd := resolveProcessCwd(s.dir, process.Cwd)
+	process.Cwd = &cwd
+	if len(process.EnvFile) > 0 {
+		envFiles := make(EnvFiles, len(process.EnvFile))
+		for i, envFile := range process.EnvFile {
+			envFiles[i] = resolveEnvFileEntry(envFile, cwd)
+		}
+		process.EnvFile = envFiles
+	}
+	if process.Logs != nil && process.Logs.File != nil && !filepath.IsAbs(*process.Logs.File) {
+		logs := *process.Logs
+		file := filepath.Join(s.dir, *logs.File)
+		logs.File = &file
+		process.Logs = &logs
+	}
+}
+
+// loadIncludes reads the files included by main, and the files they include in turn, in order.
+// Patterns are relative to the file declaring them, and a file is only included once.
+// Returns the errors met while reading and parsing them.
+func loadIncludes(main *configSource, rootDirectory string) ([]*configSource, []ValidationError) {
+	var includes []*configSource
+	var errors []ValidationError
+	seen := map[string]bool{main.path: true}
+
+	var load func(source *configSource)
+	load = func(source *configSource) {
+		patterns, _ := source.raw["include"].([]any)
+		for i, p := range patterns {
+			pattern, ok := p.(string)
+			if !ok || pattern == "" {
+				continue
+			}
+			if !filepath.IsAbs(pattern) {
+				pattern = filepath.Join(source.dir, pattern)
+			}
+			matches, err := filepath.Glob(pattern)
+			if err != nil {
+				errors = append(errors, ValidationError{
+					Message: fmt.Sprintf("include[%d] must be a valid glob pattern", i),
+					Path:    "include",
+					File:    source.file,
+				})
+				continue
+			}
+			if len(matches) == 0 && !strings.ContainsAny(p.(string), "*?[") {
+				errors = append(errors, ValidationError{
+					Message: fmt.Sprintf("include[%d] file not found: %s", i, p),
+					Path:    "include",
+					File:    source.file,
+				})
+				continue
+			}
+			for _, path := range matches {
+				if seen[path] {
+					continue
+				}
+				seen[path] = true
+				file, err := filepath.Rel(rootDirectory, path)
+				if err != nil {
+					file = path
+				}
+				content, err := os.ReadFile(path) //nolint:gosec // included by the user config
+				if err != nil {
+					errors = append(errors, ValidationError{
+						Message: fmt.Sprintf("Failed to read file: %s", err.Error()),
+						File:    file,
+					})
+					continue
+				}
+				included, parseErr := parseConfigSource(string(content), file, path)
+				if parseErr != nil {
+					errors = append(errors, *parseErr)
+					continue
+				}
+				includes = append(includes, included)
+				load(included)
+			}
+		}
+	}
+	load(main)
+	return includes, errors
+}
+
+// validateUniqueNames checks that processes of different files do not share a name.
+func validateUniqueNames(processes []any, origins []processOrigin, errors *[]ValidationError) {
+	fileByName := make(map[string]string)
+	for i, p := range processes {
+		process, ok := p.(map[string]any)
+		if !ok {
+			continue
+		}
+		name, ok := process["name"].(string)
+		if !ok || name == "" {
+			continue
+		}
+		file, seen := fileByName[name]
+		if !seen {
+			fileByName[name] = origins[i].source.file
+			continue
+		}
+		if file != origins[i].source.file {
+			*errors = append(*errors, ValidationError{
+				Message: fmt.Sprintf("name is already used by a process of %s", describeConfigFile(file)),
+				Path:    origins[i].path,
+				File:    origins[i].source.file,
+				field:   origins[i].path + ".name",
+			})
+		}
+	}
+}
+
+// describeConfigFile names a file for error messages.
+func describeConfigFile(file string) string {
+	if file == "" {
+		return "the config"
+	}
+	return file
+}
@@ -0,0 +1,115 @@
+package backend
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// writeIncludeProject writes a config including the files of its services directory.
+func writeIncludeProject(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		writeTestFile(t, path, content)
+	}
+	return filepath.Join(dir, "config.yml")
+}
+
+func TestValidateIncludes(t *testing.T) {
+	t.Parallel()
+	path := writeIncludeProject(t, map[string]string{
+		"config.yml": "project_name: monorepo\ninclude: [./services/*.yml]\nprocesses:\n  - name: db\n    base_command: postgres\n",
+		"services/api.yml": "processes:\n  - name: api\n    base_command: pnpm start\n    env_file: .env\n" +
+			"    depends_on: [db]\n    logs:\n      file: logs/api.log\n",
+		"services/web.yml":  "include: [../shared/worker.yml]\nprocesses:\n  - name: web\n    base_command: pnpm dev\n    cwd: ../apps/web\n",
+		"shared/worker.yml": "processes:\n  - name: worker\n    base_command: python worker.py\n",
+	})
+	dir := filepath.Dir(path)
+
+	result := validateConfigFile(path)
+	if !result.IsValid {
+		t.Fatalf("expected a valid config, got %+v", result.Errors)
+	}
+	names := make([]string, 0, len(result.Config.Processes))
+	for _, process := range result.Config.Processes {
+		names = append(names, process.Name)
+	}
+	if !slices.Equal(names, []string{"db", "api", "web", "worker"}) {
+		t.Errorf("expected processes merged in include order, got %v", names)
+	}
+	if len(result.IncludedFiles) != 3 {
+		t.Errorf("expected 3 included files, got %v", result.IncludedFiles)
+	}
+
+	db, api, web, worker := result.Config.Processes[0], result.Config.Processes[1], result.Config.Processes[2], result.Config.Processes[3]
+	if db.Cwd != nil {
+		t.Errorf("expected processes of the config to be left as is, got cwd %q", *db.Cwd)
+	}
+	services := filepath.Join(dir, "services")
+	if *api.Cwd != services || api.EnvFile[0] != filepath.Join(services, ".env") || *api.Logs.File != filepath.Join(services, "logs", "api.log") {
+		t.Errorf("expected api paths relative to its file, got cwd %q, env_file %v, logs.file %q", *api.Cwd, api.EnvFile, *api.Logs.File)
+	}
+	if *web.Cwd != filepath.Join(dir, "apps", "web") {
+		t.Errorf("expected web cwd relative to its file, got %q", *web.Cwd)
+	}
+	if *worker.Cwd != filepath.Join(dir, "shared") {
+		t.Errorf("expected nested includes relative to their own file, got %q", *worker.Cwd)
+	}
+}
+
+func TestValidateIncludesErrors(t *testing.T) {
+	t.Parallel()
+	path := writeIncludeProject(t, map[string]string{
+		"config.yml": "project_name: monorepo\ninclude: [services/*.yml, missing.yml]\n",
+		"services/api.yml": "processes:\n  - name: api\n    base_command: pnpm start\n" +
+			"    depends_on: [db]\n  - name: web\n    tty: yes please\n    base_command: pnpm dev\n",
+		"services/broken.yml": "processes: [\n",
+		"services/web.yml":    "processes:\n  - name: web\n    base_command: pnpm dev\n",
+	})
+
+	result := validateConfigFile(path)
+	if result.IsValid {
+		t.Fatal("expected an invalid config")
+	}
+	expected := []ValidationError{
+		{Message: "Invalid YAML file: did not find expected node content", File: filepath.Join("services", "broken.yml"), Line: 1},
+		{Message: "include[1] file not found: missing.yml", Path: "include", Line: 2, Column: 1},
+		{Message: "tty must be a boolean", Path: "processes[1]", File: filepath.Join("services", "api.yml"), Line: 6, Column: 5},
+		{Message: "name is already used by a process of " + filepath.Join("services", "api.yml"), Path: "processes[0]", File: filepath.Join("services", "web.yml"), Line: 2, Column: 5},
+		{Message: "depends_on references unknown process: db", Path: "processes[0].depends_on", File: filepath.Join("services", "api.yml"), Line: 4, Column: 18},
+	}
+	if len(result.Errors) != len(expected) {
+		t.Fatalf("got %d errors, want %d\ngot:  %+v\nwant: %+v", len(result.Errors), len(expected), result.Errors, expected)
+	}
+	for i, want := range expected {
+		if result.Errors[i] != want {
+			t.Errorf("error[%d] = %+v, want %+v", i, result.Errors[i], want)
+		}
+	}
+}
+
+func TestValidateIncludesOnly(t *testing.T) {
+	t.Parallel()
+	path := writeIncludeProject(t, map[string]string{
+		"config.yml":       "project_name: monorepo\ninclude: [services/*.yml]\n",
+		"services/api.yml": "processes:\n  - name: api\n    base_command: pnpm start\n",
+	})
+
+	result := validateConfigFile(path)
+	if !result.IsValid || len(result.Config.Processes) != 1 {
+		t.Errorf("expected processes to only come from includes, got %+v", result)
+	}
+	empty := writeIncludeProject(t, map[string]string{"config.yml": "project_name: monorepo\ninclude: [services/*.yml]\n"})
+	if errors := validateConfigFile(empty).Errors; len(errors) != 1 || errors[0].Path != "include" {
+		t.Errorf("expected an error when includes define no process, got %+v", errors)
+	}
+	if errors := ExtractYamlConfig("project_name: test\n").Errors; len(errors) != 1 || errors[0].Message != "processes must be an array - min length: 1" {
+		t.Errorf("expected processes to be required without include, got %+v", errors)
+	}
+}
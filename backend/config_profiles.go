@@ -0,0 +1,188 @@
+package backend
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// validateProfiles validates the profiles of a config against its processes (once merged with
+// the processes of its included files, and their templates applied).
+func validateProfiles(config map[string]any, processes []any, errors *[]ValidationError) {
+	raw, exists := config["profiles"]
+	if !exists {
+		return
+	}
+	profiles, ok := raw.(map[string]any)
+	if !ok {
+		*errors = append(*errors, ValidationError{Message: "profiles must be an object", Path: "profiles"})
+		return
+	}
+
+	argsByProcess := make(map[string]map[string]map[string]any)
+	for _, p := range processes {
+		process, ok := p.(map[string]any)
+		if !ok {
+			continue
+		}
+		name, ok := process["name"].(string)
+		if !ok || name == "" {
+			continue
+		}
+		args := make(map[string]map[string]any)
+		list, _ := process["args"].([]any)
+		for _, a := range list {
+			if arg, ok := a.(map[string]any); ok {
+				if argName, ok := arg["name"].(string); ok {
+					args[argName] = arg
+				}
+			}
+		}
+		argsByProcess[name] = args
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(profiles)) {
+		path := "profiles." + name
+		profile, ok := profiles[name].(map[string]any)
+		if !ok {
+			*errors = append(*errors, ValidationError{Message: fmt.Sprintf("profiles.%s must be an object", name), Path: path})
+			continue
+		}
+		if description, exists := profile["description"]; exists {
+			validateString("description", description, true, path, errors)
+		}
+		validateArray("processes", profile["processes"], intPtr(1), nil, path, errors)
+		if list, ok := profile["processes"].([]any); ok {
+			for i, p := range list {
+				processName, ok := p.(string)
+				switch {
+				case !ok || processName == "":
+					validateString(fmt.Sprintf("processes[%d]", i), p, true, path+".processes", errors)
+				case argsByProcess[processName] == nil:
+					*errors = append(*errors, ValidationError{
+						Message: fmt.Sprintf("processes references unknown process: %s", processName),
+						Path:    path + ".processes",
+						field:   fmt.Sprintf("%s.processes[%d]", path, i),
+					})
+				}
+			}
+		}
+		if args, exists := profile["args"]; exists {
+			validateProfileArgs(args, argsByProcess, path+".args", errors)
+		}
+		if env, exists := profile["env"]; exists {
+			validateProfileEnv(env, argsByProcess, path+".env", errors)
+		}
+	}
+}
+
+// validateProfileArgs validates the arg values of a profile (process name → arg name → value)
+// against the args of each process.
+func validateProfileArgs(raw any, argsByProcess map[string]map[string]map[string]any, path string, errors *[]ValidationError) {
+	args, ok := raw.(map[string]any)
+	if !ok {
+		*errors = append(*errors, ValidationError{Message: "args must be an object", Path: path})
+		return
+	}
+	for _, processName := range slices.Sorted(maps.Keys(args)) {
+		processPath := path + "." + processName
+		processArgs, known := argsByProcess[processName]
+		if !known {
+			*errors = append(*errors, ValidationError{
+				Message: fmt.Sprintf("args references unknown process: %s", processName),
+				Path:    processPath,
+			})
+			continue
+		}
+		values, ok := args[processName].(map[string]any)
+		if !ok {
+			*errors = append(*errors, ValidationError{Message: fmt.Sprintf("args.%s must be an object", processName), Path: processPath})
+			continue
+		}
+		for _, argName := range slices.Sorted(maps.Keys(values)) {
+			field := fmt.Sprintf("args.%s.%s", processName, argName)
+			arg, known := processArgs[argName]
+			if !known {
+				*errors = append(*errors, ValidationError{
+					Message: fmt.Sprintf("args.%s references unknown arg: %s", processName, argName),
+					Path:    processPath + "." + argName,
+				})
+				continue
+			}
+			value := values[argName]
+			switch arg["type"] {
+			case "toggle":
+				validateValueIn(field, value, []any{true, false}, processPath+"."+argName, errors)
+			case "select":
+				var allowed []any
+				options, _ := arg["values"].([]any)
+				for _, o := range options {
+					if option, ok := o.(map[string]any); ok {
+						allowed = append(allowed, option["value"])
+					}
+				}
+				validateValueIn(field, value, allowed, processPath+"."+argName, errors)
+			case "input":
+				switch value.(type) {
+				case string, int, float64:
+				default:
+					*errors = append(*errors, ValidationError{
+						Message: fmt.Sprintf("%s must be a string or a number", field),
+						Path:    processPath + "." + argName,
+					})
+				}
+			}
+		}
+	}
+}
+
+// validateProfileEnv validates the env of a profile (process name → env var → value).
+func validateProfileEnv(raw any, argsByProcess map[string]map[string]map[string]any, path string, errors *[]ValidationError) {
+	env, ok := raw.(map[string]any)
+	if !ok {
+		*errors = append(*errors, ValidationError{Message: "env must be an object", Path: path})
+		return
+	}
+	for _, processName := range slices.Sorted(maps.Keys(env)) {
+		processPath := path + "." + processName
+		if _, known := argsByProcess[processName]; !known {
+			*errors = append(*errors, ValidationError{
+				Message: fmt.Sprintf("env references unknown process: %s", processName),
+				Path:    processPath,
+			})
+			continue
+		}
+		values, ok := env[processName].(map[string]any)
+		if !ok {
+			*errors = append(*errors, ValidationError{Message: fmt.Sprintf("env.%s must be an object", processName), Path: processPath})
+			continue
+		}
+		for _, key := range slices.Sorted(maps.Keys(values)) {
+			if _, ok := values[key].(string); !ok {
+				*errors = append(*errors, ValidationError{
+					Message: fmt.Sprintf("env.%s.%s must be a string", processName, key),
+					Path:    processPath + "." + key,
+				})
+			}
+		}
+	}
+}
+
+// listProfiles returns the profiles of a config, sorted by name.
+func listProfiles(config YamlConfig) []ProfileInfo {
+	profiles := make([]ProfileInfo, 0, len(config.Profiles))
+	for _, name := range slices.Sorted(maps.Keys(config.Profiles)) {
+		profile := config.Profiles[name]
+		info := ProfileInfo{
+			Name:      name,
+			Processes: profile.Processes,
+			Args:      profile.Args,
+			Env:       profile.Env,
+		}
+		if profile.Description != nil {
+			info.Description = *profile.Description
+		}
+		profiles = append(profiles, info)
+	}
+	return profiles
+}
@@ -0,0 +1,40 @@
+package backend
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestListProfiles(t *testing.T) {
+	t.Parallel()
+	content, err := os.ReadFile(filepath.Join("testdata", "valid-profiles-config.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := ExtractYamlConfig(string(content))
+	if !result.IsValid {
+		t.Fatalf("expected a valid config, got %+v", result.Errors)
+	}
+
+	profiles := NewConfigService().ListProfiles(*result.Config)
+	if len(profiles) != 2 || profiles[0].Name != "frontend-only" || profiles[1].Name != "full-stack-staging" {
+		t.Fatalf("expected profiles sorted by name, got %+v", profiles)
+	}
+	staging := profiles[1]
+	if staging.Description != "API and web against the staging backend" || !slices.Equal(staging.Processes, []string{"api", "web"}) {
+		t.Errorf("expected the profile description and processes, got %+v", staging)
+	}
+	if staging.Args["api"]["Backend"] != "staging" || staging.Env["web"]["API_URL"] != "https://staging.example.com" {
+		t.Errorf("expected the profile overrides, got %+v / %+v", staging.Args, staging.Env)
+	}
+
+	specs := buildProfileSpecs(result.Config, "/project", result.Config.Profiles["full-stack-staging"])
+	if specs[0].Command != "pnpm start --backend staging --port 4000" || specs[1].Env["API_URL"] != "https://staging.example.com" {
+		t.Errorf("expected the profile overrides in the specs, got %q / %v", specs[0].Command, specs[1].Env)
+	}
+	if empty := NewConfigService().ListProfiles(YamlConfig{}); empty == nil || len(empty) != 0 {
+		t.Errorf("expected an empty list without profiles, got %v", empty)
+	}
+}
@@ -0,0 +1,225 @@
+package backend
+
+import (
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+const (
+	configSchemaDraft = "http://json-schema.org/draft-07/schema#"
+	// URL of the schema committed at the root of the repository, kept in sync by TestConfigSchemaFile
+	configSchemaID = "https://raw.githubusercontent.com/Jordan-Kowal/click-launch/main/config.schema.json"
+)
+
+// schemaFieldRules holds the constraints of config fields that Go types cannot express,
+// keyed by "<Go type>.<YAML key>". They mirror the validate* functions of config_service.go.
+var schemaFieldRules = map[string]map[string]any{
+	"YamlConfig.include":            {"minItems": 1, "items": nonEmptyStringSchema()},
+	"YamlConfig.processes":          {"minItems": 1},
+	"YamlConfig.vars":               {"propertyNames": map[string]any{"pattern": variableNamePattern.String(), "not": map[string]any{"enum": []any{builtinProjectRoot, builtinConfigDir}}}},
+	"ProcessConfig.command":         {"minItems": 1, "items": []any{nonEmptyStringSchema()}, "additionalItems": map[string]any{"type": "string"}},
+	"ProcessConfig.shell":           {"enum": []any{"sh", "bash", "zsh", "none"}},
+	"ProcessConfig.env":             {"propertyNames": map[string]any{"minLength": 1}},
+	"ProcessConfig.log_format":      {"enum": []any{logFormatJSON, logFormatLogfmt, logFormatText}},
+	"ProcessConfig.alerts":          {"minItems": 1},
+	"ProcessConfig.depends_on":      {"items": nonEmptyStringSchema()},
+	"RestartConfig.delay_ms":        {"minimum": 0},
+	"RestartConfig.reset_after_ms":  {"minimum": 0},
+	"StopConfig.signal":             {"enum": []any{"SIGINT", "SIGTERM", "SIGQUIT", "SIGHUP", "SIGKILL", "SIGUSR1", "SIGUSR2"}},
+	"MultilineConfig.start_pattern": {"format": "regex"},
+	"AlertConfig.pattern":           {"format": "regex"},
+	"AlertConfig.level":             {"enum": []any{"info", "warn", "error"}},
+	"ReadyConfig.tcp":               {"maximum": 65535},
+	"ReadyConfig.http":              {"pattern": "^https?://"},
+	"ReadyConfig.log_match":         {"format": "regex"},
+	"HealthConfig.tcp":              {"maximum": 65535},
+	"HealthConfig.http":             {"pattern": "^https?://"},
+	"ArgConfig.type":                {"enum": []any{"toggle", "select", "input"}},
+	"ProfileConfig.processes":       {"minItems": 1, "items": nonEmptyStringSchema()},
+}
+
+// schemaFieldSchemas replaces the schema of config fields accepting several shapes, keyed like schemaFieldRules.
+var schemaFieldSchemas = map[string]map[string]any{
+	"YamlConfig.env_file":    envFilesSchema(),
+	"ProcessConfig.env_file": envFilesSchema(),
+}
+
+// schemaTypeRules holds the constraints involving several fields of a Go type.
+var schemaTypeRules = map[string]map[string]any{
+	// Processes may all come from included files
+	"YamlConfig": {"anyOf": requiredAlternatives("processes", "include")},
+	"ProcessConfig": {
+		"not": map[string]any{"required": []any{"base_command", "command"}},
+		"allOf": []any{
+			// One of base_command and command, unless inherited from a template
+			map[string]any{
+				"if":   map[string]any{"required": []any{"extends"}},
+				"else": map[string]any{"anyOf": requiredAlternatives("base_command", "command")},
+			},
+			// No shell with command
+			map[string]any{
+				"if":   map[string]any{"required": []any{"command"}},
+				"then": map[string]any{"properties": map[string]any{"shell": map[string]any{"const": "none"}}},
+			},
+		},
+	},
+	"ReadyConfig":  {"anyOf": requiredAlternatives("tcp", "http", "log_match", "command")},
+	"HealthConfig": {"anyOf": requiredAlternatives("tcp", "http", "command")},
+	"ArgConfig": {
+		"allOf": []any{
+			argTypeRule("toggle", map[string]any{
+				"required": []any{"values"},
+				"properties": map[string]any{
+					"default": map[string]any{"type": "boolean"},
+					"values": map[string]any{
+						"minItems": 2,
+						"maxItems": 2,
+						"allOf":    []any{containsArgValue(true), containsArgValue(false)},
+					},
+				},
+			}),
+			argTypeRule("select", map[string]any{
+				"required": []any{"values"},
+				"properties": map[string]any{
+					"default": map[string]any{"type": "string"},
+					"values": map[string]any{
+						"minItems": 2,
+						"items":    map[string]any{"properties": map[string]any{"value": nonEmptyStringSchema()}},
+					},
+				},
+			}),
+			argTypeRule("input", map[string]any{"required": []any{"output_prefix"}}),
+		},
+	},
+}
+
+// String fields that may be empty; every other string of the config must be non-empty.
+var schemaEmptyStringFields = []string{"ArgConfig.output_prefix", "ArgValue.output"}
+
+// ConfigSchema returns the JSON Schema of the config file, generated from YamlConfig.
+func ConfigSchema() map[string]any {
+	schema := typeSchema(reflect.TypeFor[YamlConfig]())
+
+	// Defaults and templates are partial processes
+	properties := schema["properties"].(map[string]any)
+	template := maps.Clone(typeSchema(reflect.TypeFor[ProcessConfig]()))
+	delete(template, "required")
+	delete(template, "allOf")
+	template["not"] = map[string]any{"required": []any{"name"}}
+	defaults := maps.Clone(template)
+	defaults["not"] = map[string]any{"anyOf": requiredAlternatives(nonDefaultableFields...)}
+	properties["defaults"] = defaults
+	properties["templates"] = map[string]any{"type": "object", "additionalProperties": template}
+
+	schema["$schema"] = configSchemaDraft
+	schema["$id"] = configSchemaID
+	schema["title"] = "Click Launch config"
+	return schema
+}
+
+// configSchemaJSON returns ConfigSchema as indented JSON, as published in config.schema.json.
+func configSchemaJSON() string {
+	data, _ := json.MarshalIndent(ConfigSchema(), "", "  ")
+	return string(data) + "\n"
+}
+
+// typeSchema returns the schema of a Go type of the config. Struct fields use their YAML key,
+// and are required unless they are omitempty.
+func typeSchema(t reflect.Type) map[string]any {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		properties := make(map[string]any)
+		required := []any{}
+		for i := range t.NumField() {
+			field := t.Field(i)
+			key, options, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			if key == "" || key == "-" {
+				continue
+			}
+			property := typeSchema(field.Type)
+			if schema, exists := schemaFieldSchemas[t.Name()+"."+key]; exists {
+				property = schema
+			}
+			if property["type"] == "string" && !slices.Contains(schemaEmptyStringFields, t.Name()+"."+key) {
+				property["minLength"] = 1
+			}
+			for name, value := range schemaFieldRules[t.Name()+"."+key] {
+				property[name] = value
+			}
+			properties[key] = property
+			if options != "omitempty" {
+				required = append(required, key)
+			}
+		}
+		schema := map[string]any{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		for name, value := range schemaTypeRules[t.Name()] {
+			schema[name] = value
+		}
+		return schema
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int:
+		return map[string]any{"type": "integer", "minimum": 1}
+	default:
+		// any: the arg rules restrict default and values by type
+		return map[string]any{}
+	}
+}
+
+func nonEmptyStringSchema() map[string]any {
+	return map[string]any{"type": "string", "minLength": 1}
+}
+
+// envFilesSchema accepts a path, or a list of paths and {path, optional} objects.
+func envFilesSchema() map[string]any {
+	file := map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"path": nonEmptyStringSchema(), "optional": map[string]any{"type": "boolean"}},
+		"required":   []any{"path"},
+	}
+	return map[string]any{
+		"oneOf": []any{
+			nonEmptyStringSchema(),
+			map[string]any{"type": "array", "minItems": 1, "items": map[string]any{"oneOf": []any{nonEmptyStringSchema(), file}}},
+		},
+	}
+}
+
+// requiredAlternatives returns one schema requiring each key, for anyOf and oneOf.
+func requiredAlternatives(keys ...string) []any {
+	rules := make([]any, 0, len(keys))
+	for _, key := range keys {
+		rules = append(rules, map[string]any{"required": []any{key}})
+	}
+	return rules
+}
+
+// argTypeRule applies then to the args of the given type.
+func argTypeRule(argType string, then map[string]any) map[string]any {
+	return map[string]any{
+		"if":   map[string]any{"properties": map[string]any{"type": map[string]any{"const": argType}}, "required": []any{"type"}},
+		"then": then,
+	}
+}
+
+// containsArgValue requires a values entry whose value is value.
+func containsArgValue(value bool) map[string]any {
+	return map[string]any{
+		"contains": map[string]any{"properties": map[string]any{"value": map[string]any{"const": value}}, "required": []any{"value"}},
+	}
+}
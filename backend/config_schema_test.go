@@ -0,0 +1,281 @@
+package backend
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Invalid fixtures whose errors cannot be expressed in JSON Schema (YAML syntax, dependency cycles),
+// and are only caught by the Go validator.
+var schemaOnlyValidatorFixtures = map[string]bool{
+	"not-yaml.txt":                 true,
+	"invalid-yaml-parsing.yml":     true,
+	"invalid-depends-on-cycle.yml": true,
+}
+
+// --- Test helpers ---
+
+// loadTestSchema returns the published schema, decoded like a JSON Schema validator would.
+func loadTestSchema(t *testing.T) map[string]any {
+	t.Helper()
+	var schema map[string]any
+	if err := json.Unmarshal([]byte(configSchemaJSON()), &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+	return schema
+}
+
+// matchesSchema is a minimal JSON Schema (draft-07) validator, covering the keywords used by ConfigSchema.
+func matchesSchema(value any, schema map[string]any) bool {
+	if expected, exists := schema["type"]; exists && !matchesType(value, expected.(string)) {
+		return false
+	}
+	if allowed, exists := schema["enum"]; exists && !containsValue(allowed.([]any), value) {
+		return false
+	}
+	if constant, exists := schema["const"]; exists && !sameValue(constant, value) {
+		return false
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		if !matchesObject(v, schema) {
+			return false
+		}
+	case []any:
+		if !matchesArray(v, schema) {
+			return false
+		}
+	case string:
+		if n, exists := schema["minLength"]; exists && len(v) < int(n.(float64)) {
+			return false
+		}
+		if pattern, exists := schema["pattern"]; exists && !regexp.MustCompile(pattern.(string)).MatchString(v) {
+			return false
+		}
+	case int:
+		if n, exists := schema["minimum"]; exists && float64(v) < n.(float64) {
+			return false
+		}
+		if n, exists := schema["maximum"]; exists && float64(v) > n.(float64) {
+			return false
+		}
+	}
+
+	if rules, exists := schema["allOf"]; exists {
+		for _, rule := range rules.([]any) {
+			if !matchesSchema(value, rule.(map[string]any)) {
+				return false
+			}
+		}
+	}
+	if rules, exists := schema["anyOf"]; exists && countMatches(value, rules.([]any)) == 0 {
+		return false
+	}
+	if rules, exists := schema["oneOf"]; exists && countMatches(value, rules.([]any)) != 1 {
+		return false
+	}
+	if rule, exists := schema["not"]; exists && matchesSchema(value, rule.(map[string]any)) {
+		return false
+	}
+	if rule, exists := schema["if"]; exists {
+		branch := "else"
+		if matchesSchema(value, rule.(map[string]any)) {
+			branch = "then"
+		}
+		if then, exists := schema[branch]; exists && !matchesSchema(value, then.(map[string]any)) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesObject(object map[string]any, schema map[string]any) bool {
+	if required, exists := schema["required"]; exists {
+		for _, key := range required.([]any) {
+			if _, exists := object[key.(string)]; !exists {
+				return false
+			}
+		}
+	}
+	properties, _ := schema["properties"].(map[string]any)
+	for key, value := range object {
+		if property, exists := properties[key]; exists {
+			if !matchesSchema(value, property.(map[string]any)) {
+				return false
+			}
+		} else if additional, exists := schema["additionalProperties"]; exists && !matchesSchema(value, additional.(map[string]any)) {
+			return false
+		}
+		if names, exists := schema["propertyNames"]; exists && !matchesSchema(key, names.(map[string]any)) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesArray(array []any, schema map[string]any) bool {
+	if n, exists := schema["minItems"]; exists && len(array) < int(n.(float64)) {
+		return false
+	}
+	if n, exists := schema["maxItems"]; exists && len(array) > int(n.(float64)) {
+		return false
+	}
+	for i, item := range array {
+		var itemSchema any
+		switch items := schema["items"].(type) {
+		case []any:
+			itemSchema = schema["additionalItems"]
+			if i < len(items) {
+				itemSchema = items[i]
+			}
+		case map[string]any:
+			itemSchema = items
+		}
+		if itemSchema != nil && !matchesSchema(item, itemSchema.(map[string]any)) {
+			return false
+		}
+	}
+	if contains, exists := schema["contains"]; exists && !slices.ContainsFunc(array, func(item any) bool { return matchesSchema(item, contains.(map[string]any)) }) {
+		return false
+	}
+	return true
+}
+
+// countMatches returns how many rules value matches.
+func countMatches(value any, rules []any) int {
+	count := 0
+	for _, rule := range rules {
+		if matchesSchema(value, rule.(map[string]any)) {
+			count++
+		}
+	}
+	return count
+}
+
+func matchesType(value any, expected string) bool {
+	switch expected {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		return isBool(value)
+	case "integer":
+		_, ok := value.(int)
+		return ok
+	default:
+		return false
+	}
+}
+
+func containsValue(values []any, value any) bool {
+	for _, v := range values {
+		if sameValue(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func sameValue(schemaValue any, value any) bool {
+	if n, ok := value.(int); ok {
+		return schemaValue == float64(n)
+	}
+	return reflect.DeepEqual(schemaValue, value)
+}
+
+// --- Tests ---
+
+func TestConfigSchemaFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join("..", "config.schema.json")
+	if os.Getenv("UPDATE_CONFIG_SCHEMA") != "" {
+		if err := os.WriteFile(path, []byte(configSchemaJSON()), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != configSchemaJSON() {
+		t.Error("config.schema.json is outdated, run `UPDATE_CONFIG_SCHEMA=1 go test ./backend -run TestConfigSchemaFile`")
+	}
+}
+
+func TestConfigSchemaMatchesValidator(t *testing.T) {
+	t.Parallel()
+
+	schema := loadTestSchema(t)
+	for _, tc := range configTestCases {
+		if schemaOnlyValidatorFixtures[tc.filename] {
+			continue
+		}
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			content, err := os.ReadFile(filepath.Join("testdata", tc.filename))
+			if err != nil {
+				t.Fatalf("failed to read fixture %s: %v", tc.filename, err)
+			}
+			var config any
+			if err := yaml.Unmarshal(content, &config); err != nil {
+				t.Fatalf("failed to parse fixture %s: %v", tc.filename, err)
+			}
+			if got := matchesSchema(config, schema); got != tc.shouldBeValid {
+				t.Errorf("schema accepts %s = %v, validator = %v", tc.filename, got, tc.shouldBeValid)
+			}
+		})
+	}
+}
+
+func TestConfigSchemaArgRules(t *testing.T) {
+	t.Parallel()
+
+	schema := loadTestSchema(t)
+	tests := []struct {
+		name  string
+		arg   string
+		valid bool
+	}{
+		{"toggle", "{type: toggle, name: a, default: true, values: [{value: true, output: x}, {value: false, output: ''}]}", true},
+		{"toggle missing false", "{type: toggle, name: a, default: true, values: [{value: true, output: x}, {value: true, output: y}]}", false},
+		{"toggle string default", "{type: toggle, name: a, default: 'yes', values: [{value: true, output: x}, {value: false, output: ''}]}", false},
+		{"select", "{type: select, name: a, default: a, values: [{value: a, output: x}, {value: b, output: y}]}", true},
+		{"select single value", "{type: select, name: a, default: a, values: [{value: a, output: x}]}", false},
+		{"input", "{type: input, name: a, default: '', output_prefix: '--a='}", true},
+		{"input missing default", "{type: input, name: a, output_prefix: '--a='}", false},
+		{"input missing output_prefix", "{type: input, name: a, default: ''}", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var config any
+			content := "project_name: p\nprocesses: [{name: p, base_command: echo, args: [" + tt.arg + "]}]"
+			if err := yaml.Unmarshal([]byte(content), &config); err != nil {
+				t.Fatal(err)
+			}
+			if got := matchesSchema(config, schema); got != tt.valid {
+				t.Errorf("schema accepts arg = %v, want %v", got, tt.valid)
+			}
+			if got := ExtractYamlConfig(content).IsValid; got != tt.valid {
+				t.Errorf("validator accepts arg = %v, want %v", got, tt.valid)
+			}
+		})
+	}
+}
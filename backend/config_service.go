@@ -4,21 +4,63 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-
-	"gopkg.in/yaml.v3"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 )
 
-// ConfigService handles YAML config parsing and validation.
-type ConfigService struct{}
+// ConfigService handles YAML config parsing and validation, and watches the opened config for changes.
+type ConfigService struct {
+	mu      sync.Mutex
+	watcher *configWatcher
+	emitter eventEmitter
+}
 
 // NewConfigService creates a new ConfigService.
 func NewConfigService() *ConfigService {
-	return &ConfigService{}
+	return &ConfigService{emitter: &wailsEmitter{}}
 }
 
 // Validate reads a YAML file, parses it, and validates all fields.
 // Returns a ValidationResult with the parsed config or accumulated errors.
 func (s *ConfigService) Validate(filePath string) ValidationResult {
+	return validateConfigFile(filePath)
+}
+
+// WatchConfig watches a config file and the env files it references, and emits config-changed
+// with the new validation result and a diff of its processes once they change.
+// Replaces the previous watch, if any.
+func (s *ConfigService) WatchConfig(filePath string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.watcher != nil {
+		s.watcher.close()
+	}
+	s.watcher = startConfigWatcher(filePath, configWatchIntervalMs*time.Millisecond, func(data ConfigChangedData) {
+		s.emitter.Emit("config-changed", data)
+	})
+}
+
+// UnwatchConfig stops watching the config passed to WatchConfig.
+func (s *ConfigService) UnwatchConfig() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.watcher != nil {
+		s.watcher.close()
+		s.watcher = nil
+	}
+}
+
+// ServiceShutdown is called by Wails when the application is shutting down.
+func (s *ConfigService) ServiceShutdown() error {
+	s.UnwatchConfig()
+	return nil
+}
+
+// validateConfigFile reads and validates a YAML config file, along with the files it includes.
+func validateConfigFile(filePath string) ValidationResult {
 	rootDirectory := filepath.Dir(filePath)
 
 	content, err := os.ReadFile(filePath) //nolint:gosec // user-specified config path
@@ -33,41 +75,105 @@ func (s *ConfigService) Validate(filePath string) ValidationResult {
 		}
 	}
 
-	result := ExtractYamlConfig(string(content))
+	main, parseErr := parseConfigSource(string(content), "", filePath)
+	if parseErr != nil {
+		return ValidationResult{
+			IsValid:       false,
+			Config:        nil,
+			RootDirectory: rootDirectory,
+			Errors:        []ValidationError{*parseErr},
+		}
+	}
+	includes, errors := loadIncludes(main, rootDirectory)
+	result := validateConfigSources(main, includes, errors)
 	result.RootDirectory = rootDirectory
+	for _, source := range includes {
+		result.IncludedFiles = append(result.IncludedFiles, source.path)
+	}
 	return result
 }
 
+// GetDependents returns the processes that transitively depend on name, in the order they should be stopped.
+func (s *ConfigService) GetDependents(config YamlConfig, name string) []string {
+	dependents := newDependencyGraph(config.Processes).dependents(name)
+	if dependents == nil {
+		return []string{}
+	}
+	return dependents
+}
+
+// GetSchema returns the JSON Schema of the config file, for editors to validate and autocomplete it.
+func (s *ConfigService) GetSchema() string {
+	return configSchemaJSON()
+}
+
+// ListProfiles returns the profiles of a config, sorted by name.
+func (s *ConfigService) ListProfiles(config YamlConfig) []ProfileInfo {
+	return listProfiles(config)
+}
+
+// BuildCommand returns the command of a process for the given arg values (arg name → picked value).
+// Missing args use their default, and input values are shell-quoted.
+func (s *ConfigService) BuildCommand(process ProcessConfig, argValues map[string]any) string {
+	return buildCommand(process, argValues)
+}
+
 // ExtractYamlConfig parses YAML content and validates it against the config schema.
+// Errors are located at the line and column of the node their path points to.
+// Includes are only resolved by validateConfigFile, which knows the location of the config.
 func ExtractYamlConfig(yamlContent string) ValidationResult {
-	var raw any
-	if err := yaml.Unmarshal([]byte(yamlContent), &raw); err != nil {
+	source, parseErr := parseConfigSource(yamlContent, "", "")
+	if parseErr != nil {
 		return ValidationResult{
 			IsValid: false,
 			Config:  nil,
-			Errors:  []ValidationError{{Message: "Invalid YAML file"}},
+			Errors:  []ValidationError{*parseErr},
 		}
 	}
+	return validateConfigSources(source, nil, nil)
+}
 
-	config, ok := raw.(map[string]any)
-	if !ok || config == nil {
-		return ValidationResult{
-			IsValid: false,
-			Config:  nil,
-			Errors:  []ValidationError{{Message: "Invalid YAML file"}},
-		}
+// validateConfigSources validates a config and its included files as a whole, and merges their processes.
+// Processes are validated once their defaults and templates are applied.
+// errors holds the errors met while loading the includes.
+func validateConfigSources(main *configSource, includes []*configSource, errors []ValidationError) ValidationResult {
+	validateRootStructure(main.raw, &errors)
+	for _, source := range includes {
+		start := len(errors)
+		validateIncludedRootStructure(source.raw, &errors)
+		source.claim(errors[start:])
 	}
+	templates := newProcessTemplates(main.raw, &errors)
+	variables := newConfigVariables(main.raw, main.dir, &errors)
 
-	var errors []ValidationError
-	validateRootStructure(config, &errors)
-
-	if processes, ok := config["processes"].([]any); ok {
-		for i, p := range processes {
-			validateProcess(p, fmt.Sprintf("processes[%d]", i), &errors)
+	var processes []any
+	var origins []processOrigin
+	for _, source := range append([]*configSource{main}, includes...) {
+		list, _ := source.raw["processes"].([]any)
+		for i, p := range list {
+			path := fmt.Sprintf("processes[%d]", i)
+			start := len(errors)
+			p = variables.apply(templates.apply(p, path, &errors), source.dir)
+			validateProcess(p, path, &errors)
+			source.claim(errors[start:])
+			processes = append(processes, p)
+			origins = append(origins, processOrigin{path: path, source: source})
 		}
 	}
+	if len(processes) == 0 && len(errors) == 0 && main.path != "" {
+		errors = append(errors, ValidationError{
+			Message: "include must match files defining at least one process",
+			Path:    "include",
+		})
+	}
+	validateUniqueNames(processes, origins, &errors)
+	validateDependencies(processes, origins, &errors)
+	validateProfiles(main.raw, processes, &errors)
 
 	if len(errors) > 0 {
+		for _, source := range append([]*configSource{main}, includes...) {
+			source.locate(errors)
+		}
 		return ValidationResult{
 			IsValid: false,
 			Config:  nil,
@@ -76,12 +182,41 @@ func ExtractYamlConfig(yamlContent string) ValidationResult {
 	}
 
 	var typedConfig YamlConfig
-	if err := yaml.Unmarshal([]byte(yamlContent), &typedConfig); err != nil {
+	if err := main.root.Decode(&typedConfig); err != nil {
 		return ValidationResult{
 			IsValid: false,
 			Config:  nil,
-			Errors:  []ValidationError{{Message: "Invalid YAML file"}},
+			Errors:  []ValidationError{yamlParseError(err)},
+		}
+	}
+	// Project env files are relative to the config, and loaded before the env files of each process
+	for i, envFile := range typedConfig.EnvFile {
+		envFile = variables.path(envFile, main.dir)
+		if main.dir != "" {
+			envFile = resolveEnvFileEntry(envFile, main.dir)
+		}
+		typedConfig.EnvFile[i] = envFile
+	}
+	typedConfig.Processes = make([]ProcessConfig, 0, len(processes))
+	for i, p := range processes {
+		process, err := decodeProcess(p)
+		if err != nil {
+			parseErr := yamlParseError(err)
+			parseErr.Path = origins[i].path
+			parseErr.File = origins[i].source.file
+			return ValidationResult{
+				IsValid: false,
+				Config:  nil,
+				Errors:  []ValidationError{parseErr},
+			}
+		}
+		if origins[i].source != main {
+			origins[i].source.rebase(&process)
 		}
+		if len(typedConfig.EnvFile) > 0 {
+			process.EnvFile = append(slices.Clone(typedConfig.EnvFile), process.EnvFile...)
+		}
+		typedConfig.Processes = append(typedConfig.Processes, process)
 	}
 
 	return ValidationResult{
@@ -93,7 +228,27 @@ func ExtractYamlConfig(yamlContent string) ValidationResult {
 
 func validateRootStructure(config map[string]any, errors *[]ValidationError) {
 	validateString("project_name", config["project_name"], true, "", errors)
-	validateArray("processes", config["processes"], intPtr(1), nil, "", errors)
+	if envFile, exists := config["env_file"]; exists {
+		validateEnvFile(envFile, "", errors)
+	}
+	validateIncludedRootStructure(config, errors)
+}
+
+// validateIncludedRootStructure validates the root of an included file, which only defines processes.
+// Processes are optional in files that include others.
+func validateIncludedRootStructure(config map[string]any, errors *[]ValidationError) {
+	include, hasInclude := config["include"]
+	if hasInclude {
+		validateArray("include", include, intPtr(1), nil, "include", errors)
+		if patterns, ok := include.([]any); ok {
+			for i, pattern := range patterns {
+				validateString(fmt.Sprintf("include[%d]", i), pattern, true, "include", errors)
+			}
+		}
+	}
+	if processes, exists := config["processes"]; exists || !hasInclude {
+		validateArray("processes", processes, intPtr(1), nil, "", errors)
+	}
 }
 
 func validateProcess(raw any, basePath string, errors *[]ValidationError) {
@@ -107,7 +262,17 @@ func validateProcess(raw any, basePath string, errors *[]ValidationError) {
 	}
 
 	validateString("name", process["name"], true, basePath, errors)
-	validateString("base_command", process["base_command"], true, basePath, errors)
+	validateCommand(process, basePath, errors)
+
+	for _, field := range []string{"tty", "stdin"} {
+		if value, exists := process[field]; exists && !isBool(value) {
+			*errors = append(*errors, ValidationError{
+				Message: field + " must be a boolean",
+				Path:    basePath,
+				field:   basePath + "." + field,
+			})
+		}
+	}
 
 	if _, exists := process["group"]; exists {
 		validateString("group", process["group"], true, basePath, errors)
@@ -115,8 +280,8 @@ func validateProcess(raw any, basePath string, errors *[]ValidationError) {
 	if _, exists := process["cwd"]; exists {
 		validateString("cwd", process["cwd"], true, basePath, errors)
 	}
-	if _, exists := process["env_file"]; exists {
-		validateString("env_file", process["env_file"], true, basePath, errors)
+	if envFile, exists := process["env_file"]; exists {
+		validateEnvFile(envFile, basePath, errors)
 	}
 	if _, exists := process["env"]; exists {
 		validateEnvConfig(process["env"], basePath+".env", errors)
@@ -124,6 +289,43 @@ func validateProcess(raw any, basePath string, errors *[]ValidationError) {
 	if restart, exists := process["restart"]; exists && restart != nil {
 		validateRestartConfig(restart, basePath+".restart", errors)
 	}
+	if stop, exists := process["stop"]; exists {
+		validateStopConfig(stop, basePath+".stop", errors)
+	}
+	if logs, exists := process["logs"]; exists {
+		validateLogsConfig(logs, basePath+".logs", errors)
+	}
+	if alerts, exists := process["alerts"]; exists {
+		validateArray("alerts", alerts, intPtr(1), nil, basePath, errors)
+		if alertList, ok := alerts.([]any); ok {
+			for i, alert := range alertList {
+				validateAlert(alert, fmt.Sprintf("%s.alerts[%d]", basePath, i), errors)
+			}
+		}
+	}
+	if multiline, exists := process["multiline"]; exists {
+		validateMultilineConfig(multiline, basePath+".multiline", errors)
+	}
+	if logFormat, exists := process["log_format"]; exists {
+		validateValueIn("log_format", logFormat, []any{logFormatJSON, logFormatLogfmt, logFormatText}, basePath+".log_format", errors)
+	}
+	if rate, exists := process["log_rate_limit"]; exists {
+		if n, ok := toInt(rate); !ok || n < 1 {
+			*errors = append(*errors, ValidationError{
+				Message: "log_rate_limit must be a positive number",
+				Path:    basePath + ".log_rate_limit",
+			})
+		}
+	}
+	if readyWhen, exists := process["ready_when"]; exists {
+		validateReadyConfig(readyWhen, basePath+".ready_when", errors)
+	}
+	if healthcheck, exists := process["healthcheck"]; exists {
+		validateHealthConfig(healthcheck, basePath+".healthcheck", errors)
+	}
+	if dependsOn, exists := process["depends_on"]; exists {
+		validateDependsOn(dependsOn, basePath+".depends_on", errors)
+	}
 	if args, exists := process["args"]; exists {
 		validateArray("args", args, intPtr(0), nil, basePath, errors)
 		if argList, ok := args.([]any); ok {
@@ -134,6 +336,67 @@ func validateProcess(raw any, basePath string, errors *[]ValidationError) {
 	}
 }
 
+// validateCommand checks that exactly one of base_command and command (argv) is set, and that shell fits it.
+func validateCommand(process map[string]any, basePath string, errors *[]ValidationError) {
+	_, hasBaseCommand := process["base_command"]
+	argv, hasArgv := process["command"]
+	switch {
+	case hasBaseCommand && hasArgv:
+		*errors = append(*errors, ValidationError{
+			Message: "base_command and command cannot be used together",
+			Path:    basePath,
+		})
+	case hasArgv:
+		path := basePath + ".command"
+		validateArray("command", argv, intPtr(1), nil, path, errors)
+		if parts, ok := argv.([]any); ok {
+			for i, part := range parts {
+				validateString(fmt.Sprintf("command[%d]", i), part, i == 0, path, errors)
+			}
+		}
+	default:
+		validateString("base_command", process["base_command"], true, basePath, errors)
+	}
+
+	shell, exists := process["shell"]
+	if !exists {
+		return
+	}
+	validateValueIn("shell", shell, []any{"sh", "bash", "zsh", "none"}, basePath+".shell", errors)
+	if hasArgv && (shell == "sh" || shell == "bash" || shell == "zsh") {
+		*errors = append(*errors, ValidationError{
+			Message: "shell cannot be used with command, which is executed directly",
+			Path:    basePath + ".shell",
+		})
+	}
+}
+
+// validateEnvFile checks that env_file is a path, or a list of paths and {path, optional} objects.
+func validateEnvFile(raw any, path string, errors *[]ValidationError) {
+	files, ok := raw.([]any)
+	if !ok {
+		validateString("env_file", raw, true, path, errors)
+		return
+	}
+	validateArray("env_file", raw, intPtr(1), nil, path, errors)
+	for i, f := range files {
+		field := fmt.Sprintf("env_file[%d]", i)
+		file, ok := f.(map[string]any)
+		if !ok {
+			validateString(field, f, true, path, errors)
+			continue
+		}
+		validateString(field+".path", file["path"], true, path, errors)
+		if optional, exists := file["optional"]; exists && !isBool(optional) {
+			*errors = append(*errors, ValidationError{
+				Message: field + ".optional must be a boolean",
+				Path:    path,
+				field:   joinFieldPath(path, field+".optional"),
+			})
+		}
+	}
+}
+
 func validateEnvConfig(raw any, path string, errors *[]ValidationError) {
 	env, ok := raw.(map[string]any)
 	if !ok {
@@ -155,6 +418,7 @@ func validateEnvConfig(raw any, path string, errors *[]ValidationError) {
 			*errors = append(*errors, ValidationError{
 				Message: fmt.Sprintf("env.%s must be a string", key),
 				Path:    path,
+				field:   path + "." + key,
 			})
 		}
 	}
@@ -174,6 +438,7 @@ func validateRestartConfig(raw any, path string, errors *[]ValidationError) {
 		*errors = append(*errors, ValidationError{
 			Message: "restart.enabled must be a boolean",
 			Path:    path,
+			field:   path + ".enabled",
 		})
 	}
 
@@ -182,6 +447,7 @@ func validateRestartConfig(raw any, path string, errors *[]ValidationError) {
 			*errors = append(*errors, ValidationError{
 				Message: "restart.max_retries must be a positive number",
 				Path:    path,
+				field:   path + ".max_retries",
 			})
 		}
 	}
@@ -191,6 +457,7 @@ func validateRestartConfig(raw any, path string, errors *[]ValidationError) {
 			*errors = append(*errors, ValidationError{
 				Message: "restart.delay_ms must be a non-negative number",
 				Path:    path,
+				field:   path + ".delay_ms",
 			})
 		}
 	}
@@ -200,11 +467,356 @@ func validateRestartConfig(raw any, path string, errors *[]ValidationError) {
 			*errors = append(*errors, ValidationError{
 				Message: "restart.reset_after_ms must be a non-negative number",
 				Path:    path,
+				field:   path + ".reset_after_ms",
 			})
 		}
 	}
 }
 
+func validateStopConfig(raw any, path string, errors *[]ValidationError) {
+	stop, ok := raw.(map[string]any)
+	if !ok {
+		*errors = append(*errors, ValidationError{
+			Message: "stop must be an object",
+			Path:    path,
+		})
+		return
+	}
+
+	if v, exists := stop["signal"]; exists {
+		validateValueIn("stop.signal", v, []any{"SIGINT", "SIGTERM", "SIGQUIT", "SIGHUP", "SIGKILL", "SIGUSR1", "SIGUSR2"}, path, errors)
+	}
+	if v, exists := stop["timeout_ms"]; exists {
+		if n, ok := toInt(v); !ok || n < 1 {
+			*errors = append(*errors, ValidationError{
+				Message: "stop.timeout_ms must be a positive number",
+				Path:    path,
+				field:   path + ".timeout_ms",
+			})
+		}
+	}
+	if v, exists := stop["command"]; exists {
+		validateString("stop.command", v, true, path, errors)
+	}
+}
+
+func validateAlert(raw any, path string, errors *[]ValidationError) {
+	alert, ok := raw.(map[string]any)
+	if !ok {
+		*errors = append(*errors, ValidationError{
+			Message: "alert must be an object",
+			Path:    path,
+		})
+		return
+	}
+
+	pattern, ok := alert["pattern"].(string)
+	if !ok || pattern == "" {
+		*errors = append(*errors, ValidationError{
+			Message: "pattern must be a non-empty string",
+			Path:    path,
+			field:   path + ".pattern",
+		})
+	} else if _, err := regexp.Compile(pattern); err != nil {
+		*errors = append(*errors, ValidationError{
+			Message: "pattern must be a valid regular expression",
+			Path:    path,
+			field:   path + ".pattern",
+		})
+	}
+	if v, exists := alert["level"]; exists {
+		validateValueIn("level", v, []any{"info", "warn", "error"}, path, errors)
+	}
+	if v, exists := alert["notify"]; exists && !isBool(v) {
+		*errors = append(*errors, ValidationError{
+			Message: "notify must be a boolean",
+			Path:    path,
+			field:   path + ".notify",
+		})
+	}
+}
+
+func validateMultilineConfig(raw any, path string, errors *[]ValidationError) {
+	multiline, ok := raw.(map[string]any)
+	if !ok {
+		*errors = append(*errors, ValidationError{
+			Message: "multiline must be an object",
+			Path:    path,
+		})
+		return
+	}
+
+	if v, exists := multiline["start_pattern"]; exists {
+		pattern, ok := v.(string)
+		if !ok || pattern == "" {
+			*errors = append(*errors, ValidationError{
+				Message: "multiline.start_pattern must be a non-empty string",
+				Path:    path,
+				field:   path + ".start_pattern",
+			})
+		} else if _, err := regexp.Compile(pattern); err != nil {
+			*errors = append(*errors, ValidationError{
+				Message: "multiline.start_pattern must be a valid regular expression",
+				Path:    path,
+				field:   path + ".start_pattern",
+			})
+		}
+	}
+	if v, exists := multiline["timeout_ms"]; exists {
+		if n, ok := toInt(v); !ok || n < 1 {
+			*errors = append(*errors, ValidationError{
+				Message: "multiline.timeout_ms must be a positive number",
+				Path:    path,
+				field:   path + ".timeout_ms",
+			})
+		}
+	}
+}
+
+func validateLogsConfig(raw any, path string, errors *[]ValidationError) {
+	logs, ok := raw.(map[string]any)
+	if !ok {
+		*errors = append(*errors, ValidationError{
+			Message: "logs must be an object",
+			Path:    path,
+		})
+		return
+	}
+
+	if v, exists := logs["file"]; exists {
+		validateString("logs.file", v, true, path, errors)
+	}
+	for _, field := range []string{"max_size_mb", "max_files"} {
+		if v, exists := logs[field]; exists {
+			if n, ok := toInt(v); !ok || n < 1 {
+				*errors = append(*errors, ValidationError{
+					Message: fmt.Sprintf("logs.%s must be a positive number", field),
+					Path:    path,
+					field:   path + "." + field,
+				})
+			}
+		}
+	}
+}
+
+func validateReadyConfig(raw any, path string, errors *[]ValidationError) {
+	ready, ok := raw.(map[string]any)
+	if !ok {
+		*errors = append(*errors, ValidationError{
+			Message: "ready_when must be an object",
+			Path:    path,
+		})
+		return
+	}
+
+	hasCheck := false
+	if v, exists := ready["tcp"]; exists {
+		hasCheck = true
+		if n, ok := toInt(v); !ok || n < 1 || n > 65535 {
+			*errors = append(*errors, ValidationError{
+				Message: "ready_when.tcp must be a port number between 1 and 65535",
+				Path:    path,
+				field:   path + ".tcp",
+			})
+		}
+	}
+	if v, exists := ready["http"]; exists {
+		hasCheck = true
+		url, ok := v.(string)
+		if !ok || !(strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://")) {
+			*errors = append(*errors, ValidationError{
+				Message: "ready_when.http must be an http:// or https:// URL",
+				Path:    path,
+				field:   path + ".http",
+			})
+		}
+	}
+	if v, exists := ready["log_match"]; exists {
+		hasCheck = true
+		pattern, ok := v.(string)
+		if !ok || pattern == "" {
+			*errors = append(*errors, ValidationError{
+				Message: "ready_when.log_match must be a non-empty string",
+				Path:    path,
+				field:   path + ".log_match",
+			})
+		} else if _, err := regexp.Compile(pattern); err != nil {
+			*errors = append(*errors, ValidationError{
+				Message: "ready_when.log_match must be a valid regular expression",
+				Path:    path,
+				field:   path + ".log_match",
+			})
+		}
+	}
+	if v, exists := ready["command"]; exists {
+		hasCheck = true
+		validateString("ready_when.command", v, true, path, errors)
+	}
+	if !hasCheck {
+		*errors = append(*errors, ValidationError{
+			Message: "ready_when must define at least one of: tcp, http, log_match, command",
+			Path:    path,
+		})
+	}
+
+	if v, exists := ready["timeout_ms"]; exists {
+		if n, ok := toInt(v); !ok || n < 1 {
+			*errors = append(*errors, ValidationError{
+				Message: "ready_when.timeout_ms must be a positive number",
+				Path:    path,
+				field:   path + ".timeout_ms",
+			})
+		}
+	}
+	if v, exists := ready["interval_ms"]; exists {
+		if n, ok := toInt(v); !ok || n < 1 {
+			*errors = append(*errors, ValidationError{
+				Message: "ready_when.interval_ms must be a positive number",
+				Path:    path,
+				field:   path + ".interval_ms",
+			})
+		}
+	}
+}
+
+func validateHealthConfig(raw any, path string, errors *[]ValidationError) {
+	health, ok := raw.(map[string]any)
+	if !ok {
+		*errors = append(*errors, ValidationError{
+			Message: "healthcheck must be an object",
+			Path:    path,
+		})
+		return
+	}
+
+	hasCheck := false
+	if v, exists := health["tcp"]; exists {
+		hasCheck = true
+		if n, ok := toInt(v); !ok || n < 1 || n > 65535 {
+			*errors = append(*errors, ValidationError{
+				Message: "healthcheck.tcp must be a port number between 1 and 65535",
+				Path:    path,
+				field:   path + ".tcp",
+			})
+		}
+	}
+	if v, exists := health["http"]; exists {
+		hasCheck = true
+		url, ok := v.(string)
+		if !ok || !(strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://")) {
+			*errors = append(*errors, ValidationError{
+				Message: "healthcheck.http must be an http:// or https:// URL",
+				Path:    path,
+				field:   path + ".http",
+			})
+		}
+	}
+	if v, exists := health["command"]; exists {
+		hasCheck = true
+		validateString("healthcheck.command", v, true, path, errors)
+	}
+	if !hasCheck {
+		*errors = append(*errors, ValidationError{
+			Message: "healthcheck must define at least one of: tcp, http, command",
+			Path:    path,
+		})
+	}
+
+	for _, field := range []string{"interval_ms", "timeout_ms", "failure_threshold"} {
+		if v, exists := health[field]; exists {
+			if n, ok := toInt(v); !ok || n < 1 {
+				*errors = append(*errors, ValidationError{
+					Message: fmt.Sprintf("healthcheck.%s must be a positive number", field),
+					Path:    path,
+					field:   path + "." + field,
+				})
+			}
+		}
+	}
+}
+
+func validateDependsOn(raw any, path string, errors *[]ValidationError) {
+	validateArray("depends_on", raw, intPtr(0), nil, path, errors)
+	deps, ok := raw.([]any)
+	if !ok {
+		return
+	}
+	for i, dep := range deps {
+		validateString(fmt.Sprintf("depends_on[%d]", i), dep, true, path, errors)
+	}
+}
+
+// validateDependencies checks depends_on references across processes:
+// unknown names, self-references, and cycles.
+// origins holds the file and path of each process, as processes may come from included files.
+func validateDependencies(processes []any, origins []processOrigin, errors *[]ValidationError) {
+	known := make(map[string]bool, len(processes))
+	for _, p := range processes {
+		if process, ok := p.(map[string]any); ok {
+			if name, ok := process["name"].(string); ok && name != "" {
+				known[name] = true
+			}
+		}
+	}
+
+	graph := dependencyGraph{deps: make(map[string][]string)}
+	originByName := make(map[string]processOrigin)
+	for i, p := range processes {
+		process, ok := p.(map[string]any)
+		if !ok {
+			continue
+		}
+		deps, ok := process["depends_on"].([]any)
+		if !ok {
+			continue
+		}
+		name, _ := process["name"].(string)
+		file, path := origins[i].source.file, origins[i].path+".depends_on"
+
+		var validDeps []string
+		for j, d := range deps {
+			dep, ok := d.(string)
+			if !ok || dep == "" {
+				continue
+			}
+			if dep == name {
+				*errors = append(*errors, ValidationError{
+					Message: "depends_on cannot reference the process itself",
+					Path:    path,
+					File:    file,
+					field:   fmt.Sprintf("%s[%d]", path, j),
+				})
+				continue
+			}
+			if !known[dep] {
+				*errors = append(*errors, ValidationError{
+					Message: fmt.Sprintf("depends_on references unknown process: %s", dep),
+					Path:    path,
+					File:    file,
+					field:   fmt.Sprintf("%s[%d]", path, j),
+				})
+				continue
+			}
+			validDeps = append(validDeps, dep)
+		}
+
+		if name != "" {
+			if _, seen := originByName[name]; !seen {
+				originByName[name] = processOrigin{path: path, source: origins[i].source}
+			}
+			graph.add(name, validDeps)
+		}
+	}
+
+	if cycle := graph.findCycle(); cycle != nil {
+		*errors = append(*errors, ValidationError{
+			Message: fmt.Sprintf("depends_on creates a cycle: %s", strings.Join(cycle, " -> ")),
+			Path:    originByName[cycle[0]].path,
+			File:    originByName[cycle[0]].source.file,
+		})
+	}
+}
+
 func validateArg(raw any, basePath string, errors *[]ValidationError) {
 	arg, ok := raw.(map[string]any)
 	if !ok {
@@ -316,6 +928,7 @@ func validateString(fieldName string, value any, required bool, path string, err
 			*errors = append(*errors, ValidationError{
 				Message: fmt.Sprintf("%s must be a non-empty string", fieldName),
 				Path:    path,
+				field:   joinFieldPath(path, fieldName),
 			})
 		}
 	} else {
@@ -323,6 +936,7 @@ func validateString(fieldName string, value any, required bool, path string, err
 			*errors = append(*errors, ValidationError{
 				Message: fmt.Sprintf("%s must be a non-empty string", fieldName),
 				Path:    path,
+				field:   joinFieldPath(path, fieldName),
 			})
 		}
 	}
@@ -339,7 +953,7 @@ func validateArray(fieldName string, value any, minLength *int, maxLength *int,
 		if maxLength != nil {
 			msg += fmt.Sprintf(" - max length: %d", *maxLength)
 		}
-		*errors = append(*errors, ValidationError{Message: msg, Path: path})
+		*errors = append(*errors, ValidationError{Message: msg, Path: path, field: joinFieldPath(path, fieldName)})
 		return
 	}
 
@@ -351,7 +965,7 @@ func validateArray(fieldName string, value any, minLength *int, maxLength *int,
 		if maxLength != nil {
 			msg += fmt.Sprintf(" - max length: %d", *maxLength)
 		}
-		*errors = append(*errors, ValidationError{Message: msg, Path: path})
+		*errors = append(*errors, ValidationError{Message: msg, Path: path, field: joinFieldPath(path, fieldName)})
 	}
 }
 
@@ -361,6 +975,7 @@ func validateValueIn(fieldName string, value any, allowed []any, path string, er
 		*errors = append(*errors, ValidationError{
 			Message: fmt.Sprintf("%s must be one of the following values: %s", fieldName, formatValues(allowed)),
 			Path:    path,
+			field:   joinFieldPath(path, fieldName),
 		})
 		return
 	}
@@ -374,6 +989,7 @@ func validateValueIn(fieldName string, value any, allowed []any, path string, er
 	*errors = append(*errors, ValidationError{
 		Message: fmt.Sprintf("%s must be one of the following values: %s", fieldName, formatValues(allowed)),
 		Path:    path,
+		field:   joinFieldPath(path, fieldName),
 	})
 }
 
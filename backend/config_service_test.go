@@ -3,6 +3,7 @@ package backend
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -26,7 +27,7 @@ var configTestCases = []configTestCase{
 		name:     "invalid YAML parsing error",
 		filename: "invalid-yaml-parsing.yml",
 		expectedErrors: []ValidationError{
-			{Message: "Invalid YAML file"},
+			{Message: "Invalid YAML file: did not find expected ',' or ']'", Line: 8},
 		},
 		shouldBeValid: false,
 	},
@@ -43,8 +44,8 @@ var configTestCases = []configTestCase{
 		name:     "missing name and base_command",
 		filename: "missing-process-fields.yml",
 		expectedErrors: []ValidationError{
-			{Message: "name must be a non-empty string", Path: "processes[0]"},
-			{Message: "base_command must be a non-empty string", Path: "processes[0]"},
+			{Message: "name must be a non-empty string", Path: "processes[0]", Line: 3, Column: 5},
+			{Message: "base_command must be a non-empty string", Path: "processes[0]", Line: 3, Column: 5},
 		},
 		shouldBeValid: false,
 	},
@@ -156,11 +157,16 @@ var configTestCases = []configTestCase{
 		shouldBeValid:  true,
 	},
 	{
-		name:     "invalid env_file config (non-string and empty)",
+		name:     "invalid env_file config (non-string, empty, and invalid list entries)",
 		filename: "invalid-env-file-config.yml",
 		expectedErrors: []ValidationError{
 			{Message: "env_file must be a non-empty string", Path: "processes[0]"},
 			{Message: "env_file must be a non-empty string", Path: "processes[1]"},
+			{Message: "env_file must be an array - min length: 1", Path: "processes[2]"},
+			{Message: "env_file[0] must be a non-empty string", Path: "processes[3]"},
+			{Message: "env_file[1].path must be a non-empty string", Path: "processes[3]"},
+			{Message: "env_file[1].optional must be a boolean", Path: "processes[3]"},
+			{Message: "env_file[2] must be a non-empty string", Path: "processes[3]"},
 		},
 		shouldBeValid: false,
 	},
@@ -173,6 +179,249 @@ var configTestCases = []configTestCase{
 		},
 		shouldBeValid: false,
 	},
+	{
+		name:           "valid depends_on config",
+		filename:       "valid-depends-on-config.yml",
+		expectedErrors: []ValidationError{},
+		shouldBeValid:  true,
+	},
+	{
+		name:     "invalid depends_on config (non-array, non-string, self, unknown)",
+		filename: "invalid-depends-on-config.yml",
+		expectedErrors: []ValidationError{
+			{Message: "depends_on must be an array - min length: 0", Path: "processes[0].depends_on"},
+			{Message: "depends_on[0] must be a non-empty string", Path: "processes[1].depends_on"},
+			{Message: "depends_on cannot reference the process itself", Path: "processes[2].depends_on"},
+			{Message: "depends_on references unknown process: Missing", Path: "processes[3].depends_on"},
+		},
+		shouldBeValid: false,
+	},
+	{
+		name:     "invalid depends_on cycle",
+		filename: "invalid-depends-on-cycle.yml",
+		expectedErrors: []ValidationError{
+			{Message: "depends_on creates a cycle: A -> C -> B -> A", Path: "processes[1].depends_on"},
+		},
+		shouldBeValid: false,
+	},
+	{
+		name:           "valid ready_when config",
+		filename:       "valid-ready-config.yml",
+		expectedErrors: []ValidationError{},
+		shouldBeValid:  true,
+	},
+	{
+		name:     "invalid ready_when config",
+		filename: "invalid-ready-config.yml",
+		expectedErrors: []ValidationError{
+			{Message: "ready_when must be an object", Path: "processes[0].ready_when"},
+			{Message: "ready_when must define at least one of: tcp, http, log_match, command", Path: "processes[1].ready_when"},
+			{Message: "ready_when.tcp must be a port number between 1 and 65535", Path: "processes[2].ready_when"},
+			{Message: "ready_when.http must be an http:// or https:// URL", Path: "processes[3].ready_when"},
+			{Message: "ready_when.log_match must be a valid regular expression", Path: "processes[4].ready_when"},
+			{Message: "ready_when.command must be a non-empty string", Path: "processes[5].ready_when"},
+			{Message: "ready_when.timeout_ms must be a positive number", Path: "processes[6].ready_when"},
+			{Message: "ready_when.interval_ms must be a positive number", Path: "processes[6].ready_when"},
+		},
+		shouldBeValid: false,
+	},
+	{
+		name:           "valid healthcheck config",
+		filename:       "valid-healthcheck-config.yml",
+		expectedErrors: []ValidationError{},
+		shouldBeValid:  true,
+	},
+	{
+		name:     "invalid healthcheck config",
+		filename: "invalid-healthcheck-config.yml",
+		expectedErrors: []ValidationError{
+			{Message: "healthcheck must be an object", Path: "processes[0].healthcheck"},
+			{Message: "healthcheck must define at least one of: tcp, http, command", Path: "processes[1].healthcheck"},
+			{Message: "healthcheck.tcp must be a port number between 1 and 65535", Path: "processes[2].healthcheck"},
+			{Message: "healthcheck.http must be an http:// or https:// URL", Path: "processes[3].healthcheck"},
+			{Message: "healthcheck.interval_ms must be a positive number", Path: "processes[4].healthcheck"},
+			{Message: "healthcheck.timeout_ms must be a positive number", Path: "processes[4].healthcheck"},
+			{Message: "healthcheck.failure_threshold must be a positive number", Path: "processes[4].healthcheck"},
+		},
+		shouldBeValid: false,
+	},
+	{
+		name:           "valid stop config",
+		filename:       "valid-stop-config.yml",
+		expectedErrors: []ValidationError{},
+		shouldBeValid:  true,
+	},
+	{
+		name:     "invalid stop config",
+		filename: "invalid-stop-config.yml",
+		expectedErrors: []ValidationError{
+			{Message: "stop must be an object", Path: "processes[0].stop"},
+			{Message: "stop.signal must be one of the following values: SIGINT, SIGTERM, SIGQUIT, SIGHUP, SIGKILL, SIGUSR1, SIGUSR2", Path: "processes[1].stop"},
+			{Message: "stop.timeout_ms must be a positive number", Path: "processes[2].stop"},
+			{Message: "stop.command must be a non-empty string", Path: "processes[3].stop"},
+		},
+		shouldBeValid: false,
+	},
+	{
+		name:           "valid logs config",
+		filename:       "valid-logs-config.yml",
+		expectedErrors: []ValidationError{},
+		shouldBeValid:  true,
+	},
+	{
+		name:     "invalid logs config",
+		filename: "invalid-logs-config.yml",
+		expectedErrors: []ValidationError{
+			{Message: "logs must be an object", Path: "processes[0].logs"},
+			{Message: "logs.file must be a non-empty string", Path: "processes[1].logs"},
+			{Message: "logs.max_size_mb must be a positive number", Path: "processes[2].logs"},
+			{Message: "logs.max_files must be a positive number", Path: "processes[2].logs"},
+		},
+		shouldBeValid: false,
+	},
+	{
+		name:           "valid log format config",
+		filename:       "valid-log-format-config.yml",
+		expectedErrors: []ValidationError{},
+		shouldBeValid:  true,
+	},
+	{
+		name:     "invalid log format config",
+		filename: "invalid-log-format-config.yml",
+		expectedErrors: []ValidationError{
+			{Message: "log_format must be one of the following values: json, logfmt, text", Path: "processes[0].log_format"},
+			{Message: "log_format must be one of the following values: json, logfmt, text", Path: "processes[1].log_format"},
+		},
+		shouldBeValid: false,
+	},
+	{
+		name:           "valid multiline config",
+		filename:       "valid-multiline-config.yml",
+		expectedErrors: []ValidationError{},
+		shouldBeValid:  true,
+	},
+	{
+		name:     "invalid multiline config",
+		filename: "invalid-multiline-config.yml",
+		expectedErrors: []ValidationError{
+			{Message: "multiline must be an object", Path: "processes[0].multiline"},
+			{Message: "multiline.start_pattern must be a valid regular expression", Path: "processes[1].multiline"},
+			{Message: "multiline.start_pattern must be a non-empty string", Path: "processes[2].multiline"},
+			{Message: "multiline.timeout_ms must be a positive number", Path: "processes[2].multiline"},
+		},
+		shouldBeValid: false,
+	},
+	{
+		name:           "valid alerts config",
+		filename:       "valid-alerts-config.yml",
+		expectedErrors: []ValidationError{},
+		shouldBeValid:  true,
+	},
+	{
+		name:     "invalid alerts config",
+		filename: "invalid-alerts-config.yml",
+		expectedErrors: []ValidationError{
+			{Message: "alerts must be an array - min length: 1", Path: "processes[0]"},
+			{Message: "alerts must be an array - min length: 1", Path: "processes[1]"},
+			{Message: "alert must be an object", Path: "processes[2].alerts[0]"},
+			{Message: "pattern must be a valid regular expression", Path: "processes[2].alerts[1]"},
+			{Message: "pattern must be a non-empty string", Path: "processes[2].alerts[2]"},
+			{Message: "level must be one of the following values: info, warn, error", Path: "processes[2].alerts[2]"},
+			{Message: "notify must be a boolean", Path: "processes[2].alerts[2]"},
+		},
+		shouldBeValid: false,
+	},
+	{
+		name:           "valid log rate limit config",
+		filename:       "valid-log-rate-limit-config.yml",
+		expectedErrors: []ValidationError{},
+		shouldBeValid:  true,
+	},
+	{
+		name:     "invalid log rate limit config",
+		filename: "invalid-log-rate-limit-config.yml",
+		expectedErrors: []ValidationError{
+			{Message: "log_rate_limit must be a positive number", Path: "processes[0].log_rate_limit"},
+			{Message: "log_rate_limit must be a positive number", Path: "processes[1].log_rate_limit"},
+		},
+		shouldBeValid: false,
+	},
+	{
+		name:           "valid command and shell config",
+		filename:       "valid-command-config.yml",
+		expectedErrors: []ValidationError{},
+		shouldBeValid:  true,
+	},
+	{
+		name:     "invalid command and shell config",
+		filename: "invalid-command-config.yml",
+		expectedErrors: []ValidationError{
+			{Message: "base_command and command cannot be used together", Path: "processes[0]"},
+			{Message: "command must be an array - min length: 1", Path: "processes[1].command"},
+			{Message: "command must be an array - min length: 1", Path: "processes[2].command"},
+			{Message: "command[0] must be a non-empty string", Path: "processes[3].command"},
+			{Message: "shell must be one of the following values: sh, bash, zsh, none", Path: "processes[4].shell"},
+			{Message: "shell cannot be used with command, which is executed directly", Path: "processes[5].shell"},
+			{Message: "tty must be a boolean", Path: "processes[6]"},
+			{Message: "stdin must be a boolean", Path: "processes[7]"},
+		},
+		shouldBeValid: false,
+	},
+	{
+		name:           "valid templates config (defaults, extends chain, overrides)",
+		filename:       "valid-templates-config.yml",
+		expectedErrors: []ValidationError{},
+		shouldBeValid:  true,
+	},
+	{
+		name:     "invalid templates config",
+		filename: "invalid-templates-config.yml",
+		expectedErrors: []ValidationError{
+			{Message: "defaults cannot set name", Path: "defaults.name", Line: 4, Column: 3},
+			{Message: "templates.base cannot set name", Path: "templates.base.name", Line: 10, Column: 5},
+			{Message: "extends creates a cycle: loop-a -> loop-b -> loop-a", Path: "templates.loop-a.extends", Line: 13, Column: 5},
+			{Message: "extends references unknown template: missing", Path: "templates.orphan.extends", Line: 17, Column: 5},
+			{Message: "extends references unknown template: unknown", Path: "processes[0].extends", Line: 21, Column: 5},
+			{Message: "restart.enabled must be a boolean", Path: "processes[0].restart"},
+			{Message: "restart.enabled must be a boolean", Path: "processes[1].restart"},
+		},
+		shouldBeValid: false,
+	},
+	{
+		name:           "valid vars config (references, defaults, built-ins, escapes)",
+		filename:       "valid-vars-config.yml",
+		expectedErrors: []ValidationError{},
+		shouldBeValid:  true,
+	},
+	{
+		name:     "invalid vars config",
+		filename: "invalid-vars-config.yml",
+		expectedErrors: []ValidationError{
+			{Message: "vars.1BAD must be a valid variable name", Path: "vars.1BAD", Line: 5, Column: 3},
+			{Message: "vars.PORT must be a string", Path: "vars.PORT", Line: 6, Column: 3},
+			{Message: "vars.PROJECT_ROOT cannot override a built-in variable", Path: "vars.PROJECT_ROOT", Line: 4, Column: 3},
+		},
+		shouldBeValid: false,
+	},
+	{
+		name:           "valid profiles config (processes, args, env)",
+		filename:       "valid-profiles-config.yml",
+		expectedErrors: []ValidationError{},
+		shouldBeValid:  true,
+	},
+	{
+		name:     "invalid profiles config",
+		filename: "invalid-profiles-config.yml",
+		expectedErrors: []ValidationError{
+			{Message: "processes must be an array - min length: 1", Path: "profiles.empty", Line: 18, Column: 5},
+			{Message: "processes references unknown process: missing", Path: "profiles.unknown.processes", Line: 20, Column: 24},
+			{Message: "args.api.Backend must be one of the following values: local, staging", Path: "profiles.unknown.args.api.Backend", Line: 23, Column: 9},
+			{Message: "args.api references unknown arg: Debug", Path: "profiles.unknown.args.api.Debug", Line: 24, Column: 9},
+			{Message: "args references unknown process: ghost", Path: "profiles.unknown.args.ghost", Line: 25, Column: 7},
+			{Message: "env.api.PORT must be a string", Path: "profiles.unknown.env.api.PORT", Line: 29, Column: 9},
+		},
+		shouldBeValid: false,
+	},
 }
 
 func TestExtractYamlConfig(t *testing.T) {
@@ -216,6 +465,9 @@ func TestExtractYamlConfig(t *testing.T) {
 					if got.Path != expected.Path {
 						t.Errorf("error[%d].Path = %q, want %q", i, got.Path, expected.Path)
 					}
+					if expected.Line != 0 && (got.Line != expected.Line || got.Column != expected.Column) {
+						t.Errorf("error[%d] position = %d:%d, want %d:%d", i, got.Line, got.Column, expected.Line, expected.Column)
+					}
 				}
 			}
 		})
@@ -260,3 +512,38 @@ func TestValidate(t *testing.T) {
 		}
 	})
 }
+
+func TestGetDependents(t *testing.T) {
+	t.Parallel()
+
+	svc := NewConfigService()
+	config := YamlConfig{
+		ProjectName: "Deps",
+		Processes: []ProcessConfig{
+			{Name: "db", BaseCommand: "echo db"},
+			{Name: "api", BaseCommand: "echo api", DependsOn: []string{"db"}},
+			{Name: "web", BaseCommand: "echo web", DependsOn: []string{"api"}},
+			{Name: "worker", BaseCommand: "echo worker", DependsOn: []string{"db"}},
+		},
+	}
+
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{name: "db", want: []string{"web", "api", "worker"}},
+		{name: "api", want: []string{"web"}},
+		{name: "web", want: []string{}},
+		{name: "unknown", want: []string{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := svc.GetDependents(config, tc.name)
+			if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+				t.Errorf("GetDependents(%q) = %v, want %v", tc.name, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package backend
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Process fields that defaults cannot set, as they identify a process or its command.
+var nonDefaultableFields = []string{"name", "base_command", "command", "extends"}
+
+// processTemplates holds the defaults and templates of a config, applied to its processes
+// (and to those of its included files) before they are validated.
+type processTemplates struct {
+	defaults  map[string]any
+	templates map[string]map[string]any
+}
+
+// newProcessTemplates validates the defaults and templates of a config, and returns them
+// with the extends chain of each template resolved.
+func newProcessTemplates(config map[string]any, errors *[]ValidationError) *processTemplates {
+	t := &processTemplates{templates: make(map[string]map[string]any)}
+
+	if raw, exists := config["defaults"]; exists {
+		defaults, ok := raw.(map[string]any)
+		if !ok {
+			*errors = append(*errors, ValidationError{Message: "defaults must be an object", Path: "defaults"})
+		} else {
+			for _, field := range nonDefaultableFields {
+				if _, exists := defaults[field]; exists {
+					*errors = append(*errors, ValidationError{
+						Message: fmt.Sprintf("defaults cannot set %s", field),
+						Path:    "defaults." + field,
+					})
+				}
+			}
+			t.defaults = defaults
+		}
+	}
+
+	raw, exists := config["templates"]
+	if !exists {
+		return t
+	}
+	templates, ok := raw.(map[string]any)
+	if !ok {
+		*errors = append(*errors, ValidationError{Message: "templates must be an object", Path: "templates"})
+		return t
+	}
+	for _, name := range slices.Sorted(maps.Keys(templates)) {
+		path := "templates." + name
+		template, ok := templates[name].(map[string]any)
+		if !ok {
+			*errors = append(*errors, ValidationError{Message: fmt.Sprintf("templates.%s must be an object", name), Path: path})
+			continue
+		}
+		if _, exists := template["name"]; exists {
+			*errors = append(*errors, ValidationError{Message: fmt.Sprintf("templates.%s cannot set name", name), Path: path + ".name"})
+		}
+		if extends, exists := template["extends"]; exists {
+			validateString("extends", extends, true, path+".extends", errors)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(templates)) {
+		t.resolve(name, templates, nil, errors)
+	}
+	return t
+}
+
+// resolve merges a template into the template it extends, once. chain holds the templates being
+// resolved, to report cycles.
+func (t *processTemplates) resolve(name string, raw map[string]any, chain []string, errors *[]ValidationError) map[string]any {
+	if resolved, exists := t.templates[name]; exists {
+		return resolved
+	}
+	template, ok := raw[name].(map[string]any)
+	if !ok {
+		return nil
+	}
+	if slices.Contains(chain, name) {
+		*errors = append(*errors, ValidationError{
+			Message: fmt.Sprintf("extends creates a cycle: %s", strings.Join(append(chain, name), " -> ")),
+			Path:    "templates." + chain[0] + ".extends",
+		})
+		return nil
+	}
+
+	resolved := template
+	if parentName, ok := template["extends"].(string); ok && parentName != "" {
+		if _, exists := raw[parentName]; !exists {
+			*errors = append(*errors, ValidationError{
+				Message: fmt.Sprintf("extends references unknown template: %s", parentName),
+				Path:    "templates." + name + ".extends",
+			})
+		} else if parent := t.resolve(parentName, raw, append(chain, name), errors); parent != nil {
+			resolved = mergeProcess(parent, template)
+		}
+	}
+	resolved = maps.Clone(resolved)
+	delete(resolved, "extends")
+	t.templates[name] = resolved
+	return resolved
+}
+
+// apply returns a process merged over its template and the defaults: objects (such as env or restart)
+// are merged key by key, and other values (including lists) replace the inherited ones.
+func (t *processTemplates) apply(raw any, path string, errors *[]ValidationError) any {
+	process, ok := raw.(map[string]any)
+	if !ok {
+		return raw
+	}
+	merged := process
+	if extends, exists := process["extends"]; exists {
+		name, ok := extends.(string)
+		template, found := t.templates[name]
+		switch {
+		case !ok || name == "":
+			validateString("extends", extends, true, path, errors)
+		case !found:
+			*errors = append(*errors, ValidationError{
+				Message: fmt.Sprintf("extends references unknown template: %s", name),
+				Path:    path + ".extends",
+			})
+		default:
+			merged = mergeProcess(template, merged)
+		}
+	}
+	if t.defaults != nil {
+		merged = mergeProcess(t.defaults, merged)
+	}
+	return merged
+}
+
+// mergeProcess merges a process over the process it inherits from. Setting one of base_command
+// and command replaces the other.
+func mergeProcess(base map[string]any, override map[string]any) map[string]any {
+	if _, exists := override["command"]; exists {
+		base = maps.Clone(base)
+		delete(base, "base_command")
+	}
+	if _, exists := override["base_command"]; exists {
+		base = maps.Clone(base)
+		delete(base, "command")
+	}
+	return deepMerge(base, override)
+}
+
+// deepMerge returns override merged over base, without modifying either.
+func deepMerge(base map[string]any, override map[string]any) map[string]any {
+	merged := maps.Clone(base)
+	for key, value := range override {
+		baseMap, baseIsMap := merged[key].(map[string]any)
+		overrideMap, overrideIsMap := value.(map[string]any)
+		if baseIsMap && overrideIsMap {
+			merged[key] = deepMerge(baseMap, overrideMap)
+		} else {
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
+// decodeProcess decodes a process once its defaults and templates are applied.
+func decodeProcess(raw any) (ProcessConfig, error) {
+	var node yaml.Node
+	var process ProcessConfig
+	if err := node.Encode(raw); err != nil {
+		return process, err
+	}
+	err := node.Decode(&process)
+	return process, err
+}
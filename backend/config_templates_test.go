@@ -0,0 +1,52 @@
+package backend
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestProcessTemplates(t *testing.T) {
+	t.Parallel()
+	content, err := os.ReadFile(filepath.Join("testdata", "valid-templates-config.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := ExtractYamlConfig(string(content))
+	if !result.IsValid {
+		t.Fatalf("expected a valid config, got %+v", result.Errors)
+	}
+	api, web, worker := result.Config.Processes[0], result.Config.Processes[1], result.Config.Processes[2]
+
+	if api.BaseCommand != "pnpm start" || *api.Cwd != "./services" || *api.Stop.Signal != "SIGINT" {
+		t.Errorf("expected api to inherit its templates and the defaults, got %+v", api)
+	}
+	if !maps.Equal(api.Env, map[string]string{"LOG_LEVEL": "debug", "NODE_ENV": "development", "PORT": "3000"}) {
+		t.Errorf("expected env to be merged key by key, got %v", api.Env)
+	}
+	if web.BaseCommand != "" || !slices.Equal(web.Command, []string{"pnpm", "dev"}) {
+		t.Errorf("expected command to replace the inherited base_command, got %q / %v", web.BaseCommand, web.Command)
+	}
+	if worker.Restart.Enabled || worker.Restart.MaxRetries == nil || *worker.Restart.MaxRetries != 5 {
+		t.Errorf("expected restart to be merged key by key, got %+v", worker.Restart)
+	}
+}
+
+func TestDeepMerge(t *testing.T) {
+	t.Parallel()
+	base := map[string]any{"env": map[string]any{"A": "1", "B": "2"}, "args": []any{"x"}, "cwd": "a"}
+	override := map[string]any{"env": map[string]any{"B": "3"}, "args": []any{"y"}}
+
+	merged := deepMerge(base, override)
+	if env := merged["env"].(map[string]any); env["A"] != "1" || env["B"] != "3" {
+		t.Errorf("expected objects to be merged, got %v", env)
+	}
+	if args := merged["args"].([]any); len(args) != 1 || args[0] != "y" || merged["cwd"] != "a" {
+		t.Errorf("expected lists to be replaced and other values kept, got %v", merged)
+	}
+	if base["env"].(map[string]any)["B"] != "2" {
+		t.Error("expected base to be left unchanged")
+	}
+}
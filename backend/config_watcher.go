@@ -0,0 +1,217 @@
+package backend
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Interval at which the watched config and its env files are checked for changes
+const configWatchIntervalMs = 500
+
+// Process fields (by YAML key) that only apply once a running process is restarted: every field read
+// when the process starts, as opposed to display and dependency fields (name, group, depends_on).
+var restartRequiredFields = []string{
+	"base_command", "command", "args", "shell", "tty", "stdin", "cwd", "env", "env_file",
+	"restart", "stop", "ready_when", "healthcheck", "logs", "log_format", "log_rate_limit", "multiline", "alerts",
+}
+
+// fileStamp identifies a version of a file. The zero value stands for a missing file.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// configWatcher polls a config file and the env files it references, and reports the
+// changes once they settle (editors often write a file in several steps).
+type configWatcher struct {
+	path     string
+	interval time.Duration
+	onChange func(ConfigChangedData)
+	stop     chan struct{}
+	done     chan struct{}
+	// current is the last valid config, which changes are compared to, and reloaded the stamps it was read at
+	current  ValidationResult
+	reloaded map[string]fileStamp
+}
+
+// startConfigWatcher starts watching path, calling onChange from the watcher goroutine.
+func startConfigWatcher(path string, interval time.Duration, onChange func(ConfigChangedData)) *configWatcher {
+	w := &configWatcher{
+		path:     path,
+		interval: interval,
+		onChange: onChange,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	w.current = validateConfigFile(path)
+	w.reloaded = w.stamps(w.current)
+	go w.run()
+	return w
+}
+
+// close stops the watcher and waits for it to return.
+func (w *configWatcher) close() {
+	close(w.stop)
+	<-w.done
+}
+
+func (w *configWatcher) run() {
+	defer close(w.done)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	stamps := w.reloaded
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+		}
+		next := w.stamps(w.current)
+		if !maps.Equal(next, stamps) {
+			// Wait for one more tick without changes before reloading
+			stamps = next
+			continue
+		}
+		if maps.Equal(stamps, w.reloaded) {
+			continue
+		}
+
+		result := validateConfigFile(w.path)
+		data := ConfigChangedData{Result: result, Diff: ConfigDiff{Added: []string{}, Removed: []string{}, Modified: []ProcessDiff{}}}
+		if result.IsValid {
+			changedFiles := make(map[string]bool)
+			for path, stamp := range stamps {
+				if w.reloaded[path] != stamp {
+					changedFiles[path] = true
+				}
+			}
+			data.Diff = diffConfigs(w.current.Config, result.Config)
+			markEnvFileChanges(&data.Diff, result, changedFiles)
+			w.current = result
+		}
+		w.reloaded = w.stamps(w.current)
+		stamps = w.reloaded
+		w.onChange(data)
+	}
+}
+
+// stamps returns the stamps of the config file, of the files it includes and of the env files of config.
+// The directories of the included files are stamped too, so that new files matching an include are seen.
+func (w *configWatcher) stamps(config ValidationResult) map[string]fileStamp {
+	stamps := map[string]fileStamp{w.path: statFileStamp(w.path)}
+	for _, path := range config.IncludedFiles {
+		stamps[path] = statFileStamp(path)
+		stamps[filepath.Dir(path)] = statFileStamp(filepath.Dir(path))
+	}
+	for _, paths := range envFilePaths(config) {
+		for _, path := range paths {
+			stamps[path] = statFileStamp(path)
+		}
+	}
+	return stamps
+}
+
+func statFileStamp(path string) fileStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{modTime: info.ModTime(), size: info.Size()}
+}
+
+// envFilePaths returns the resolved env files of each process of a valid config (optional ones included,
+// so that they are picked up once created), by process name.
+func envFilePaths(config ValidationResult) map[string][]string {
+	paths := make(map[string][]string)
+	if config.Config == nil {
+		return paths
+	}
+	for _, process := range config.Config.Processes {
+		cwd := resolveProcessCwd(config.RootDirectory, process.Cwd)
+		for _, envFile := range process.EnvFile {
+			path, _ := splitEnvFile(envFile)
+			paths[process.Name] = append(paths[process.Name], resolveEnvFilePath(path, cwd))
+		}
+	}
+	return paths
+}
+
+// diffConfigs lists the processes added, removed and modified from old to updated, matched by name.
+// A nil old config has no processes.
+func diffConfigs(old *YamlConfig, updated *YamlConfig) ConfigDiff {
+	diff := ConfigDiff{Added: []string{}, Removed: []string{}, Modified: []ProcessDiff{}}
+	oldProcesses := make(map[string]ProcessConfig)
+	if old != nil {
+		for _, process := range old.Processes {
+			oldProcesses[process.Name] = process
+		}
+	}
+	seen := make(map[string]bool)
+	for _, process := range updated.Processes {
+		seen[process.Name] = true
+		previous, exists := oldProcesses[process.Name]
+		if !exists {
+			diff.Added = append(diff.Added, process.Name)
+			continue
+		}
+		if fields := changedProcessFields(previous, process); len(fields) > 0 {
+			diff.Modified = append(diff.Modified, ProcessDiff{
+				Name:            process.Name,
+				Fields:          fields,
+				RestartRequired: slices.ContainsFunc(fields, isRestartRequiredField),
+			})
+		}
+	}
+	if old != nil {
+		for _, process := range old.Processes {
+			if !seen[process.Name] {
+				diff.Removed = append(diff.Removed, process.Name)
+			}
+		}
+	}
+	return diff
+}
+
+// markEnvFileChanges adds env_file to the changed fields of the processes whose env files changed.
+func markEnvFileChanges(diff *ConfigDiff, config ValidationResult, changedFiles map[string]bool) {
+	paths := envFilePaths(config)
+	for _, process := range config.Config.Processes {
+		changed := slices.ContainsFunc(paths[process.Name], func(path string) bool { return changedFiles[path] })
+		if !changed || slices.Contains(diff.Added, process.Name) {
+			continue
+		}
+		i := slices.IndexFunc(diff.Modified, func(d ProcessDiff) bool { return d.Name == process.Name })
+		if i < 0 {
+			diff.Modified = append(diff.Modified, ProcessDiff{Name: process.Name, Fields: []string{}})
+			i = len(diff.Modified) - 1
+		}
+		if !slices.Contains(diff.Modified[i].Fields, "env_file") {
+			diff.Modified[i].Fields = append(diff.Modified[i].Fields, "env_file")
+		}
+		diff.Modified[i].RestartRequired = true
+	}
+}
+
+// changedProcessFields returns the YAML keys of the fields that differ between two versions of a process.
+func changedProcessFields(old ProcessConfig, updated ProcessConfig) []string {
+	var fields []string
+	oldValue, updatedValue := reflect.ValueOf(old), reflect.ValueOf(updated)
+	processType := oldValue.Type()
+	for i := range processType.NumField() {
+		if !reflect.DeepEqual(oldValue.Field(i).Interface(), updatedValue.Field(i).Interface()) {
+			key, _, _ := strings.Cut(processType.Field(i).Tag.Get("yaml"), ",")
+			fields = append(fields, key)
+		}
+	}
+	return fields
+}
+
+func isRestartRequiredField(field string) bool {
+	return slices.Contains(restartRequiredFields, field)
+}
@@ -0,0 +1,154 @@
+package backend
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDiffConfigs(t *testing.T) {
+	t.Parallel()
+	env := map[string]string{"PORT": "3000"}
+	group := "web"
+	old := &YamlConfig{Processes: []ProcessConfig{
+		{Name: "api", BaseCommand: "pnpm start", Env: env},
+		{Name: "web", BaseCommand: "pnpm dev"},
+		{Name: "worker", BaseCommand: "python worker.py"},
+	}}
+	updated := &YamlConfig{Processes: []ProcessConfig{
+		{Name: "api", BaseCommand: "pnpm start:dev", Env: map[string]string{"PORT": "4000"}},
+		{Name: "web", BaseCommand: "pnpm dev", Group: &group},
+		{Name: "db", BaseCommand: "postgres"},
+	}}
+
+	diff := diffConfigs(old, updated)
+	if !slices.Equal(diff.Added, []string{"db"}) || !slices.Equal(diff.Removed, []string{"worker"}) {
+		t.Errorf("unexpected added/removed processes: %+v", diff)
+	}
+	if len(diff.Modified) != 2 {
+		t.Fatalf("expected 2 modified processes, got %+v", diff.Modified)
+	}
+	api, web := diff.Modified[0], diff.Modified[1]
+	if api.Name != "api" || !slices.Equal(api.Fields, []string{"base_command", "env"}) || !api.RestartRequired {
+		t.Errorf("unexpected api diff: %+v", api)
+	}
+	if web.Name != "web" || !slices.Equal(web.Fields, []string{"group"}) || web.RestartRequired {
+		t.Errorf("unexpected web diff: %+v", web)
+	}
+
+	stopSignal, logFormat := "SIGINT", "json"
+	for field, process := range map[string]ProcessConfig{
+		"restart":        {Name: "api", Restart: &RestartConfig{Enabled: true}},
+		"stop":           {Name: "api", Stop: &StopConfig{Signal: &stopSignal}},
+		"ready_when":     {Name: "api", ReadyWhen: &ReadyConfig{}},
+		"healthcheck":    {Name: "api", Healthcheck: &HealthConfig{}},
+		"logs":           {Name: "api", Logs: &LogsConfig{}},
+		"log_format":     {Name: "api", LogFormat: &logFormat},
+		"log_rate_limit": {Name: "api", LogRateLimit: new(int)},
+		"multiline":      {Name: "api", Multiline: &MultilineConfig{}},
+		"alerts":         {Name: "api", Alerts: []AlertConfig{{}}},
+	} {
+		diff := diffConfigs(&YamlConfig{Processes: []ProcessConfig{{Name: "api"}}}, &YamlConfig{Processes: []ProcessConfig{process}})
+		if len(diff.Modified) != 1 || !slices.Equal(diff.Modified[0].Fields, []string{field}) || !diff.Modified[0].RestartRequired {
+			t.Errorf("expected a change of %s to require a restart, got %+v", field, diff.Modified)
+		}
+	}
+
+	if diff := diffConfigs(nil, updated); len(diff.Added) != 3 || len(diff.Removed) != 0 {
+		t.Errorf("expected every process to be added without a previous config, got %+v", diff)
+	}
+}
+
+// watchConfig starts a watcher on a config file and returns the channel of its changes.
+func watchConfig(t *testing.T, path string) <-chan ConfigChangedData {
+	t.Helper()
+	changes := make(chan ConfigChangedData, 10)
+	watcher := startConfigWatcher(path, 10*time.Millisecond, func(data ConfigChangedData) { changes <- data })
+	t.Cleanup(watcher.close)
+	return changes
+}
+
+func waitForConfigChange(t *testing.T, changes <-chan ConfigChangedData) ConfigChangedData {
+	t.Helper()
+	select {
+	case data := <-changes:
+		return data
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for config-changed")
+		return ConfigChangedData{}
+	}
+}
+
+func writeTestFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConfigWatcher(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yml")
+	writeTestFile(t, path, "project_name: test\nprocesses:\n  - name: api\n    base_command: pnpm start\n")
+	changes := watchConfig(t, path)
+
+	writeTestFile(t, path, "project_name: test\nprocesses:\n  - name: api\n    base_command: pnpm start:dev\n")
+	data := waitForConfigChange(t, changes)
+	if !data.Result.IsValid || len(data.Diff.Modified) != 1 || !data.Diff.Modified[0].RestartRequired {
+		t.Errorf("expected api to be modified, got %+v", data)
+	}
+
+	// Invalid configs are reported without a diff, and the next change compares to the last valid one
+	writeTestFile(t, path, "project_name: test\nprocesses: []\n")
+	data = waitForConfigChange(t, changes)
+	if data.Result.IsValid || len(data.Result.Errors) == 0 || len(data.Diff.Modified) != 0 {
+		t.Errorf("expected an invalid result without diff, got %+v", data)
+	}
+	writeTestFile(t, path, "project_name: test\nprocesses:\n  - name: api\n    base_command: pnpm start:dev\n  - name: web\n    base_command: pnpm dev\n")
+	data = waitForConfigChange(t, changes)
+	if !slices.Equal(data.Diff.Added, []string{"web"}) || len(data.Diff.Modified) != 0 {
+		t.Errorf("expected web to be added, got %+v", data.Diff)
+	}
+}
+
+func TestConfigWatcher_EnvFile(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yml")
+	writeTestFile(t, filepath.Join(dir, ".env"), "PORT=3000\n")
+	writeTestFile(t, path, "project_name: test\nprocesses:\n  - name: api\n    base_command: pnpm start\n    env_file: .env\n  - name: web\n    base_command: pnpm dev\n")
+	changes := watchConfig(t, path)
+
+	writeTestFile(t, filepath.Join(dir, ".env"), "PORT=40000\n")
+	data := waitForConfigChange(t, changes)
+	expected := []ProcessDiff{{Name: "api", Fields: []string{"env_file"}, RestartRequired: true}}
+	if len(data.Diff.Modified) != 1 || data.Diff.Modified[0].Name != expected[0].Name ||
+		!slices.Equal(data.Diff.Modified[0].Fields, expected[0].Fields) || !data.Diff.Modified[0].RestartRequired {
+		t.Errorf("expected %+v, got %+v", expected, data.Diff.Modified)
+	}
+}
+
+func TestConfigWatcher_Include(t *testing.T) {
+	t.Parallel()
+	path := writeIncludeProject(t, map[string]string{
+		"config.yml":       "project_name: test\ninclude: [services/*.yml]\n",
+		"services/api.yml": "processes:\n  - name: api\n    base_command: pnpm start\n",
+	})
+	services := filepath.Join(filepath.Dir(path), "services")
+	changes := watchConfig(t, path)
+
+	writeTestFile(t, filepath.Join(services, "api.yml"), "processes:\n  - name: api\n    base_command: pnpm start:dev\n")
+	data := waitForConfigChange(t, changes)
+	if len(data.Diff.Modified) != 1 || data.Diff.Modified[0].Name != "api" {
+		t.Errorf("expected api to be modified, got %+v", data.Diff)
+	}
+
+	writeTestFile(t, filepath.Join(services, "web.yml"), "processes:\n  - name: web\n    base_command: pnpm dev\n")
+	data = waitForConfigChange(t, changes)
+	if !slices.Equal(data.Diff.Added, []string{"web"}) {
+		t.Errorf("expected web to be added, got %+v", data.Diff)
+	}
+}
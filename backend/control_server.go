@@ -0,0 +1,363 @@
+package backend
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+const (
+	controlReadHeaderTimeout = 5 * time.Second
+	// Largest request body accepted (e.g. input sent to stdin)
+	maxControlBodyBytes = 1 << 20
+)
+
+// controlInfo is written to disk so that scripts can discover the control API.
+type controlInfo struct {
+	URL   string `json:"url"`
+	Token string `json:"token"`
+	Pid   int    `json:"pid"`
+}
+
+// controlServer is the local control API: an HTTP server bound to 127.0.0.1, protected by a
+// per-session token, that drives the same ProcessService as the UI.
+type controlServer struct {
+	svc *ProcessService
+	// configPath is the config used by start requests that do not specify one (headless mode)
+	configPath string
+	token      string
+	infoPath   string
+	server     *http.Server
+}
+
+func newControlServer(svc *ProcessService, configPath string) (*controlServer, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return nil, fmt.Errorf("generating token: %w", err)
+	}
+	return &controlServer{svc: svc, configPath: configPath, token: hex.EncodeToString(token)}, nil
+}
+
+// startControlServer listens on a random local port and writes the URL and token to infoPath.
+func startControlServer(svc *ProcessService, configPath string, infoPath string) (*controlServer, error) {
+	c, err := newControlServer(svc, configPath)
+	if err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("listening: %w", err)
+	}
+	c.server = &http.Server{Handler: c.routes(), ReadHeaderTimeout: controlReadHeaderTimeout}
+	go func() { _ = c.server.Serve(listener) }()
+
+	info := controlInfo{URL: "http://" + listener.Addr().String(), Token: c.token, Pid: os.Getpid()}
+	if err := writeControlInfo(infoPath, info); err != nil {
+		_ = c.server.Close()
+		return nil, err
+	}
+	c.infoPath = infoPath
+	return c, nil
+}
+
+// close stops the server (including open log streams) and removes the discovery file.
+func (c *controlServer) close() {
+	if c.server != nil {
+		_ = c.server.Close()
+	}
+	if c.infoPath != "" {
+		_ = os.Remove(c.infoPath)
+	}
+}
+
+func writeControlInfo(path string, info controlInfo) error {
+	content, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("creating control directory: %w", err)
+	}
+	// The token grants control over local processes: keep it readable by the user only
+	return os.WriteFile(path, content, 0o600)
+}
+
+// --- Routing ---
+
+func (c *controlServer) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("GET /processes", c.authenticate(c.handleList, false))
+	mux.Handle("GET /processes/{name}", c.authenticate(c.handleStatus, false))
+	mux.Handle("POST /processes/{name}/start", c.authenticate(c.handleStart, false))
+	mux.Handle("POST /processes/{name}/stop", c.authenticate(c.handleStop, false))
+	mux.Handle("POST /processes/{name}/restart", c.authenticate(c.handleRestart, false))
+	mux.Handle("POST /processes/{name}/stdin", c.authenticate(c.handleStdin, false))
+	// EventSource clients cannot set headers
+	mux.Handle("GET /processes/{name}/logs", c.authenticate(c.handleLogs, true))
+	return mux
+}
+
+// authenticate requires the session token as a bearer token, or as a `token` query param when allowQuery is set.
+// Query params end up in logs and browser history, so they are only accepted where headers cannot be set.
+func (c *controlServer) authenticate(next http.HandlerFunc, allowQuery bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" && allowQuery {
+			token = r.URL.Query().Get("token")
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(c.token)) != 1 {
+			writeControlError(w, http.StatusUnauthorized, "invalid or missing token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// --- Handlers ---
+
+func (c *controlServer) handleList(w http.ResponseWriter, _ *http.Request) {
+	writeControlJSON(w, http.StatusOK, c.svc.listProcesses())
+}
+
+func (c *controlServer) handleStatus(w http.ResponseWriter, r *http.Request) {
+	writeControlJSON(w, http.StatusOK, c.processInfo(r.PathValue("name")))
+}
+
+func (c *controlServer) handleStart(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	var body struct {
+		Config string            `json:"config"`
+		Args   map[string]any    `json:"args"`
+		Env    map[string]string `json:"env"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		writeControlError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+
+	if id := c.svc.findActiveByName(name); id != "" {
+		writeControlJSON(w, http.StatusOK, map[string]ProcessStartResult{name: {Success: true, ProcessID: id}})
+		return
+	}
+
+	configPath := body.Config
+	if configPath == "" {
+		configPath = c.configPath
+	}
+	if configPath == "" {
+		writeControlError(w, http.StatusBadRequest, fmt.Sprintf("%s is not running: a config is required to start it", name))
+		return
+	}
+	if _, _, err := loadConfigFile(configPath); err != nil {
+		writeControlError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results := c.svc.StartByName(configPath, name, body.Args, body.Env)
+	status := http.StatusOK
+	if !results[name].Success {
+		status = http.StatusInternalServerError
+	}
+	writeControlJSON(w, status, results)
+}
+
+func (c *controlServer) handleStop(w http.ResponseWriter, r *http.Request) {
+	id := c.svc.findActiveByName(r.PathValue("name"))
+	if id == "" {
+		writeControlJSON(w, http.StatusOK, ProcessStopResult{Success: true})
+		return
+	}
+	writeControlJSON(w, http.StatusOK, c.svc.stopAndWait(id))
+}
+
+func (c *controlServer) handleRestart(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	id := c.svc.findActiveByName(name)
+	if id == "" {
+		writeControlError(w, http.StatusNotFound, name+" is not running")
+		return
+	}
+	result := c.svc.restartProcess(id)
+	status := http.StatusOK
+	if !result.Success {
+		status = http.StatusInternalServerError
+	}
+	writeControlJSON(w, status, result)
+}
+
+// handleStdin writes the raw request body to the stdin of an active process.
+func (c *controlServer) handleStdin(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	id := c.svc.findActiveByName(name)
+	if id == "" {
+		writeControlError(w, http.StatusNotFound, name+" is not running")
+		return
+	}
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxControlBodyBytes))
+	if err != nil {
+		if maxBytesErr := (*http.MaxBytesError)(nil); errors.As(err, &maxBytesErr) {
+			writeControlError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("body exceeds %d bytes", maxBytesErr.Limit))
+			return
+		}
+		writeControlError(w, http.StatusBadRequest, "reading body: "+err.Error())
+		return
+	}
+	result := c.svc.WriteStdin(id, string(data))
+	status := http.StatusOK
+	if !result.Success {
+		status = http.StatusConflict
+	}
+	writeControlJSON(w, status, result)
+}
+
+// handleLogs streams the logs of a process as server-sent events, across restarts, until the client disconnects.
+// With a cursor (the since query parameter, or the Last-Event-ID header when reconnecting), the buffered
+// logs of the latest run after that cursor are sent first.
+func (c *controlServer) handleLogs(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeControlError(w, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+	name := r.PathValue("name")
+	since := r.URL.Query().Get("since")
+	if since == "" {
+		since = r.Header.Get("Last-Event-ID")
+	}
+	var sinceCursor int64
+	if since != "" {
+		cursor, err := strconv.ParseInt(since, 10, 64)
+		if err != nil || cursor < 0 {
+			writeControlError(w, http.StatusBadRequest, "since must be a log cursor")
+			return
+		}
+		sinceCursor = cursor
+	}
+	// Subscribe before reading the buffer, so that no entry falls between the two
+	batches, unsubscribe := c.svc.subscribeLogs()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	lastCursor := sinceCursor
+	writeEntry := func(entry ProcessLogData) error {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return nil
+		}
+		lastCursor = entry.Cursor
+		_, err = fmt.Fprintf(w, "id: %d\nevent: log\ndata: %s\n\n", entry.Cursor, data)
+		return err
+	}
+
+	if since != "" {
+		if id := c.svc.latestLogBufferID(name); id != "" {
+			for _, entry := range c.svc.GetLogs(id, sinceCursor, 0).Logs {
+				if err := writeEntry(entry); err != nil {
+					return
+				}
+			}
+			flusher.Flush()
+		}
+	}
+
+	// Match by name rather than ID, so that the stream follows the process across restarts
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case batch := <-batches:
+			for _, entry := range batch {
+				if entry.processName != name || entry.Cursor <= lastCursor {
+					continue
+				}
+				if err := writeEntry(entry); err != nil {
+					return
+				}
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// --- Helpers ---
+
+func (c *controlServer) processInfo(name string) ProcessInfo {
+	for _, info := range c.svc.listProcesses() {
+		if info.Name == name {
+			return info
+		}
+	}
+	return ProcessInfo{Name: name, Status: processStatusStopped}
+}
+
+func writeControlJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeControlError(w http.ResponseWriter, status int, message string) {
+	writeControlJSON(w, status, map[string]string{"error": message})
+}
+
+// --- Wails service ---
+
+// ControlService runs the local control API for the lifetime of the app, so that editors, git hooks
+// and scripts can drive the processes started from the UI.
+type ControlService struct {
+	processes *ProcessService
+	server    *controlServer
+}
+
+// NewControlService creates a ControlService serving the given ProcessService.
+func NewControlService(processes *ProcessService) *ControlService {
+	return &ControlService{processes: processes}
+}
+
+// defaultControlInfoPath is where the app publishes the control API URL and token.
+func defaultControlInfoPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "click-launch", "control.json")
+	}
+	return filepath.Join(home, ".click-launch", "control.json")
+}
+
+// ServiceStartup is called by Wails when the application starts.
+// The app keeps working without the control API, so failures are only logged.
+func (s *ControlService) ServiceStartup(_ context.Context, _ application.ServiceOptions) error {
+	server, err := startControlServer(s.processes, "", defaultControlInfoPath())
+	if err != nil {
+		log.Printf("control API disabled: %s", err)
+		return nil
+	}
+	s.server = server
+	return nil
+}
+
+// ServiceShutdown is called by Wails when the application is shutting down.
+func (s *ControlService) ServiceShutdown() error {
+	if s.server != nil {
+		s.server.close()
+	}
+	return nil
+}
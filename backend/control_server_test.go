@@ -0,0 +1,354 @@
+package backend
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// --- Test helpers ---
+
+func newTestControlServer(t *testing.T, configPath string) (*httptest.Server, *controlServer, *ProcessService) {
+	t.Helper()
+	svc, _ := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+	c, err := newControlServer(svc, configPath)
+	if err != nil {
+		t.Fatalf("failed to create control server: %v", err)
+	}
+	server := httptest.NewServer(c.routes())
+	t.Cleanup(server.Close)
+	return server, c, svc
+}
+
+func controlRequest(t *testing.T, server *httptest.Server, token string, method string, path string, body string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func decodeControlResponse[T any](t *testing.T, resp *http.Response) T {
+	t.Helper()
+	var v T
+	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return v
+}
+
+// --- Tests ---
+
+func TestControlServer_RequiresToken(t *testing.T) {
+	t.Parallel()
+	server, c, _ := newTestControlServer(t, "")
+
+	if resp := controlRequest(t, server, "", http.MethodGet, "/processes", ""); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401 without token, got %d", resp.StatusCode)
+	}
+	if resp := controlRequest(t, server, "wrong", http.MethodGet, "/processes", ""); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401 with a wrong token, got %d", resp.StatusCode)
+	}
+	if resp := controlRequest(t, server, c.token, http.MethodGet, "/processes", ""); resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200 with the token, got %d", resp.StatusCode)
+	}
+	if resp := controlRequest(t, server, "", http.MethodGet, "/processes?token="+c.token, ""); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401 with the token as query param outside the logs stream, got %d", resp.StatusCode)
+	}
+	if resp := controlRequest(t, server, "", http.MethodPost, "/processes/api/stop?token="+c.token, ""); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401 with the token as query param outside the logs stream, got %d", resp.StatusCode)
+	}
+	if resp := controlRequest(t, server, "", http.MethodGet, "/processes/api/logs?token="+c.token, ""); resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200 with the token as query param for the logs stream, got %d", resp.StatusCode)
+	}
+}
+
+func TestControlServer_StartStatusStop(t *testing.T) {
+	t.Parallel()
+	configPath := writeTestConfig(t, `project_name: "API"
+processes:
+  - name: "db"
+    base_command: "sleep 30"
+  - name: "api"
+    base_command: "sleep 30"
+    depends_on: ["db"]
+`)
+	server, c, svc := newTestControlServer(t, "")
+
+	resp := controlRequest(t, server, c.token, http.MethodPost, "/processes/api/start", `{"config": "`+configPath+`"}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	results := decodeControlResponse[map[string]ProcessStartResult](t, resp)
+	if !results["db"].Success || !results["api"].Success {
+		t.Fatalf("expected db and api to start, got %+v", results)
+	}
+
+	resp = controlRequest(t, server, c.token, http.MethodGet, "/processes", "")
+	if infos := decodeControlResponse[[]ProcessInfo](t, resp); len(infos) != 2 {
+		t.Errorf("expected 2 processes, got %+v", infos)
+	}
+
+	resp = controlRequest(t, server, c.token, http.MethodGet, "/processes/api", "")
+	info := decodeControlResponse[ProcessInfo](t, resp)
+	if info.ProcessID != results["api"].ProcessID || info.Status != processStatusRunning {
+		t.Errorf("unexpected api status: %+v", info)
+	}
+
+	resp = controlRequest(t, server, c.token, http.MethodPost, "/processes/api/stop", "")
+	if stop := decodeControlResponse[ProcessStopResult](t, resp); !stop.Success {
+		t.Fatalf("expected stop to succeed, got %+v", stop)
+	}
+	if svc.findActiveByName("api") != "" {
+		t.Error("expected api to be stopped")
+	}
+	if svc.findActiveByName("db") == "" {
+		t.Error("expected db to keep running")
+	}
+
+	resp = controlRequest(t, server, c.token, http.MethodGet, "/processes/api", "")
+	if info := decodeControlResponse[ProcessInfo](t, resp); info.Status != processStatusStopped {
+		t.Errorf("expected api to be reported as stopped, got %+v", info)
+	}
+}
+
+func TestControlServer_StartErrors(t *testing.T) {
+	t.Parallel()
+	server, c, _ := newTestControlServer(t, "")
+
+	if resp := controlRequest(t, server, c.token, http.MethodPost, "/processes/api/start", ""); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 without a config, got %d", resp.StatusCode)
+	}
+	if resp := controlRequest(t, server, c.token, http.MethodPost, "/processes/api/start", "{"); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid body, got %d", resp.StatusCode)
+	}
+	missing := filepath.Join(t.TempDir(), "missing.yml")
+	if resp := controlRequest(t, server, c.token, http.MethodPost, "/processes/api/start", `{"config": "`+missing+`"}`); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for a missing config, got %d", resp.StatusCode)
+	}
+}
+
+func TestControlServer_StartUsesDefaultConfig(t *testing.T) {
+	t.Parallel()
+	configPath := writeTestConfig(t, `project_name: "API"
+processes:
+  - name: "api"
+    base_command: "sleep 30"
+`)
+	server, c, _ := newTestControlServer(t, configPath)
+
+	resp := controlRequest(t, server, c.token, http.MethodPost, "/processes/api/start", "")
+	results := decodeControlResponse[map[string]ProcessStartResult](t, resp)
+	if !results["api"].Success {
+		t.Fatalf("expected api to start from the default config, got %+v", results)
+	}
+
+	// Starting an active process returns its current ID
+	resp = controlRequest(t, server, c.token, http.MethodPost, "/processes/api/start", "")
+	again := decodeControlResponse[map[string]ProcessStartResult](t, resp)
+	if again["api"].ProcessID != results["api"].ProcessID {
+		t.Errorf("expected the active process to be reused, got %+v", again)
+	}
+}
+
+func TestControlServer_Restart(t *testing.T) {
+	t.Parallel()
+	server, c, svc := newTestControlServer(t, "")
+
+	if resp := controlRequest(t, server, c.token, http.MethodPost, "/processes/api/restart", ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 for a stopped process, got %d", resp.StatusCode)
+	}
+
+	started := svc.StartWithDependencies("api", []ProcessSpec{{Name: "api", Cwd: t.TempDir(), Command: "sleep 30"}})
+	resp := controlRequest(t, server, c.token, http.MethodPost, "/processes/api/restart", "")
+	result := decodeControlResponse[ProcessStartResult](t, resp)
+	if !result.Success || result.ProcessID == started["api"].ProcessID {
+		t.Fatalf("expected a new process ID, got %+v", result)
+	}
+	if svc.findActiveByName("api") != result.ProcessID {
+		t.Error("expected the restarted process to be active")
+	}
+}
+
+func TestControlServer_Stdin(t *testing.T) {
+	t.Parallel()
+	server, c, svc := newTestControlServer(t, "")
+
+	if resp := controlRequest(t, server, c.token, http.MethodPost, "/processes/prompt/stdin", "hello\n"); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 for a stopped process, got %d", resp.StatusCode)
+	}
+
+	dir := t.TempDir()
+	svc.StartWithDependencies("prompt", []ProcessSpec{{Name: "prompt", Cwd: dir, Command: "cat", Stdin: true}})
+	resp := controlRequest(t, server, c.token, http.MethodPost, "/processes/prompt/stdin", "hello\n")
+	if result := decodeControlResponse[ProcessStdinResult](t, resp); !result.Success {
+		t.Errorf("expected the write to succeed, got %+v", result)
+	}
+
+	resp = controlRequest(t, server, c.token, http.MethodPost, "/processes/prompt/stdin", strings.Repeat("a", maxControlBodyBytes+1))
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413 for a body over %d bytes, got %d", maxControlBodyBytes, resp.StatusCode)
+	}
+
+	svc.StartWithDependencies("closed", []ProcessSpec{{Name: "closed", Cwd: dir, Command: "sleep 30"}})
+	if resp := controlRequest(t, server, c.token, http.MethodPost, "/processes/closed/stdin", "hello\n"); resp.StatusCode != http.StatusConflict {
+		t.Errorf("expected 409 for a process without stdin, got %d", resp.StatusCode)
+	}
+}
+
+func TestControlServer_StreamsLogs(t *testing.T) {
+	t.Parallel()
+	server, c, svc := newTestControlServer(t, "")
+
+	resp := controlRequest(t, server, c.token, http.MethodGet, "/processes/api/logs", "")
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("expected an event stream, got %q", resp.Header.Get("Content-Type"))
+	}
+
+	dir := t.TempDir()
+	svc.StartWithDependencies("other", []ProcessSpec{{Name: "other", Cwd: dir, Command: "echo other"}})
+	svc.StartWithDependencies("api", []ProcessSpec{{Name: "api", Cwd: dir, Command: "sleep 0.2; echo hello"}})
+
+	lines := make(chan string, 100)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	deadline := time.After(3 * time.Second)
+	var received []ProcessLogData
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				t.Fatal("stream closed unexpectedly")
+			}
+			data, found := strings.CutPrefix(line, "data: ")
+			if !found {
+				continue
+			}
+			var entry ProcessLogData
+			if err := json.Unmarshal([]byte(data), &entry); err != nil {
+				t.Fatalf("invalid event data: %v", err)
+			}
+			received = append(received, entry)
+			if entry.Type == "exit" {
+				if received[0].Output != "hello\n" {
+					t.Errorf("expected only api logs, got %+v", received)
+				}
+				return
+			}
+		case <-deadline:
+			t.Fatalf("expected api logs and exit, got %+v", received)
+		}
+	}
+}
+
+func TestStartControlServer_WritesInfoFile(t *testing.T) {
+	t.Parallel()
+	svc, _ := newTestProcessService()
+	infoPath := filepath.Join(t.TempDir(), "nested", "control.json")
+
+	c, err := startControlServer(svc, "", infoPath)
+	if err != nil {
+		t.Fatalf("failed to start control server: %v", err)
+	}
+
+	content, err := os.ReadFile(infoPath)
+	if err != nil {
+		t.Fatalf("expected info file: %v", err)
+	}
+	stat, _ := os.Stat(infoPath)
+	if stat.Mode().Perm() != 0o600 {
+		t.Errorf("expected info file to be private, got %v", stat.Mode().Perm())
+	}
+	var info controlInfo
+	if err := json.Unmarshal(content, &info); err != nil {
+		t.Fatalf("invalid info file: %v", err)
+	}
+	if info.Token != c.token || !strings.HasPrefix(info.URL, "http://127.0.0.1:") {
+		t.Errorf("unexpected info: %+v", info)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, info.URL+"/processes", nil)
+	req.Header.Set("Authorization", "Bearer "+info.Token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+
+	c.close()
+	if _, err := os.Stat(infoPath); !os.IsNotExist(err) {
+		t.Error("expected info file to be removed on close")
+	}
+}
+
+func TestControlServer_StreamsLogsSinceCursor(t *testing.T) {
+	t.Parallel()
+	server, c, svc := newTestControlServer(t, "")
+
+	results := svc.StartWithDependencies("api", []ProcessSpec{{Name: "api", Cwd: t.TempDir(), Command: "echo one; echo two; echo three"}})
+	id := results["api"].ProcessID
+	deadline := time.Now().Add(5 * time.Second)
+	for svc.IsRunning(id) && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	first := svc.GetLogs(id, 0, 1)
+
+	if resp := controlRequest(t, server, c.token, http.MethodGet, "/processes/api/logs?since=abc", ""); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid cursor, got %d", resp.StatusCode)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/processes/api/logs", nil)
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Last-Event-ID", strconv.FormatInt(first.Cursor, 10))
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	scanner := bufio.NewScanner(resp.Body)
+	var ids []string
+	var outputs []string
+	for len(outputs) < 3 && scanner.Scan() {
+		line := scanner.Text()
+		if id, found := strings.CutPrefix(line, "id: "); found {
+			ids = append(ids, id)
+		}
+		if data, found := strings.CutPrefix(line, "data: "); found {
+			var entry ProcessLogData
+			if err := json.Unmarshal([]byte(data), &entry); err != nil {
+				t.Fatalf("invalid event data: %v", err)
+			}
+			outputs = append(outputs, entry.Output)
+		}
+	}
+	if strings.Join(outputs, "") != "two\nthree\n" || len(ids) != 3 {
+		t.Errorf("expected the entries after the cursor to be replayed, got %q (ids %v)", outputs, ids)
+	}
+}
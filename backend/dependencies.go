@@ -0,0 +1,167 @@
+package backend
+
+import (
+	"fmt"
+	"strings"
+)
+
+// dependencyGraph maps each process name to the names it depends on.
+// names keeps the declaration order so that every traversal is deterministic.
+type dependencyGraph struct {
+	names []string
+	deps  map[string][]string
+}
+
+// newDependencyGraph builds a graph from the processes of a parsed config.
+func newDependencyGraph(processes []ProcessConfig) dependencyGraph {
+	g := dependencyGraph{deps: make(map[string][]string, len(processes))}
+	for _, p := range processes {
+		g.add(p.Name, p.DependsOn)
+	}
+	return g
+}
+
+// newSpecDependencyGraph builds a graph from resolved launch specs.
+func newSpecDependencyGraph(specs []ProcessSpec) dependencyGraph {
+	g := dependencyGraph{deps: make(map[string][]string, len(specs))}
+	for _, spec := range specs {
+		g.add(spec.Name, spec.DependsOn)
+	}
+	return g
+}
+
+func (g *dependencyGraph) add(name string, deps []string) {
+	if _, exists := g.deps[name]; !exists {
+		g.names = append(g.names, name)
+	}
+	g.deps[name] = deps
+}
+
+// startOrder returns the transitive dependencies of name in topological order, followed by name itself.
+func (g dependencyGraph) startOrder(name string) ([]string, error) {
+	if _, exists := g.deps[name]; !exists {
+		return nil, fmt.Errorf("unknown process: %s", name)
+	}
+
+	var order []string
+	visited := make(map[string]bool)
+	var stack []string
+	onStack := make(map[string]bool)
+
+	var visit func(n string) error
+	visit = func(n string) error {
+		if onStack[n] {
+			return fmt.Errorf("dependency cycle: %s", formatCycle(stack, n))
+		}
+		if visited[n] {
+			return nil
+		}
+		deps, exists := g.deps[n]
+		if !exists {
+			return fmt.Errorf("unknown process: %s", n)
+		}
+		onStack[n] = true
+		stack = append(stack, n)
+		for _, dep := range deps {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+		onStack[n] = false
+		visited[n] = true
+		order = append(order, n)
+		return nil
+	}
+
+	if err := visit(name); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// dependents returns every process that transitively depends on name, ordered so that
+// each process comes before the ones it depends on (i.e. the order to stop them in).
+func (g dependencyGraph) dependents(name string) []string {
+	reverse := make(map[string][]string, len(g.names))
+	for _, n := range g.names {
+		for _, dep := range g.deps[n] {
+			reverse[dep] = append(reverse[dep], n)
+		}
+	}
+
+	// Post-order over the reverse edges yields the deepest dependents first
+	var order []string
+	visited := map[string]bool{name: true}
+	var visit func(n string)
+	visit = func(n string) {
+		for _, dependent := range reverse[n] {
+			if visited[dependent] {
+				continue
+			}
+			visited[dependent] = true
+			visit(dependent)
+			order = append(order, dependent)
+		}
+	}
+	visit(name)
+	return order
+}
+
+// findCycle returns the first dependency cycle found, as a closed path (first == last), or nil.
+// Self-references and unknown names are ignored; they are reported separately.
+func (g dependencyGraph) findCycle() []string {
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+	state := make(map[string]int, len(g.names))
+	var stack []string
+	var cycle []string
+
+	var visit func(n string) bool
+	visit = func(n string) bool {
+		state[n] = inProgress
+		stack = append(stack, n)
+		for _, dep := range g.deps[n] {
+			if _, known := g.deps[dep]; !known || dep == n {
+				continue
+			}
+			switch state[dep] {
+			case inProgress:
+				cycle = cyclePath(stack, dep)
+				return true
+			case unvisited:
+				if visit(dep) {
+					return true
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[n] = done
+		return false
+	}
+
+	for _, n := range g.names {
+		if state[n] == unvisited && visit(n) {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// cyclePath extracts the closed cycle ending at n from the current DFS stack.
+func cyclePath(stack []string, n string) []string {
+	for i, s := range stack {
+		if s == n {
+			path := append([]string{}, stack[i:]...)
+			return append(path, n)
+		}
+	}
+	return []string{n, n}
+}
+
+func formatCycle(stack []string, n string) string {
+	return strings.Join(cyclePath(stack, n), " -> ")
+}
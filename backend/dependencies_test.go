@@ -0,0 +1,91 @@
+package backend
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDependencyGraphStartOrder(t *testing.T) {
+	t.Parallel()
+
+	graph := newDependencyGraph([]ProcessConfig{
+		{Name: "db"},
+		{Name: "cache"},
+		{Name: "api", DependsOn: []string{"db", "cache"}},
+		{Name: "web", DependsOn: []string{"api", "db"}},
+		{Name: "loop-a", DependsOn: []string{"loop-b"}},
+		{Name: "loop-b", DependsOn: []string{"loop-a"}},
+		{Name: "broken", DependsOn: []string{"missing"}},
+	})
+
+	tests := []struct {
+		name    string
+		process string
+		want    []string
+		wantErr string
+	}{
+		{name: "no dependencies", process: "db", want: []string{"db"}},
+		{name: "direct dependencies", process: "api", want: []string{"db", "cache", "api"}},
+		{name: "transitive dependencies", process: "web", want: []string{"db", "cache", "api", "web"}},
+		{name: "unknown process", process: "nope", wantErr: "unknown process: nope"},
+		{name: "unknown dependency", process: "broken", wantErr: "unknown process: missing"},
+		{name: "cycle", process: "loop-a", wantErr: "dependency cycle: loop-a -> loop-b -> loop-a"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := graph.startOrder(tc.process)
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Fatalf("error = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+				t.Errorf("startOrder(%q) = %v, want %v", tc.process, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDependencyGraphFindCycle(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no cycle", func(t *testing.T) {
+		t.Parallel()
+		graph := newDependencyGraph([]ProcessConfig{
+			{Name: "db"},
+			{Name: "api", DependsOn: []string{"db"}},
+		})
+		if cycle := graph.findCycle(); cycle != nil {
+			t.Errorf("findCycle() = %v, want nil", cycle)
+		}
+	})
+
+	t.Run("ignores self-references and unknown names", func(t *testing.T) {
+		t.Parallel()
+		graph := newDependencyGraph([]ProcessConfig{
+			{Name: "api", DependsOn: []string{"api", "missing"}},
+		})
+		if cycle := graph.findCycle(); cycle != nil {
+			t.Errorf("findCycle() = %v, want nil", cycle)
+		}
+	})
+
+	t.Run("reports closed cycle path", func(t *testing.T) {
+		t.Parallel()
+		graph := newDependencyGraph([]ProcessConfig{
+			{Name: "a", DependsOn: []string{"b"}},
+			{Name: "b", DependsOn: []string{"c"}},
+			{Name: "c", DependsOn: []string{"a"}},
+		})
+		got := strings.Join(graph.findCycle(), " -> ")
+		if got != "a -> b -> c -> a" {
+			t.Errorf("findCycle() = %q, want %q", got, "a -> b -> c -> a")
+		}
+	})
+}
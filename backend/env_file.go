@@ -1,11 +1,58 @@
 package backend
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
 	"path/filepath"
+	"strings"
 
 	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
 )
 
+// Suffix of the env files that may not exist (e.g. ".env.local?")
+const optionalEnvFileSuffix = "?"
+
+// UnmarshalYAML accepts a single path, or a list of paths and {path, optional} objects.
+// Optional files are stored with the optional suffix.
+func (f *EnvFiles) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*f = EnvFiles{node.Value}
+		return nil
+	}
+	var items []yaml.Node
+	if err := node.Decode(&items); err != nil {
+		return err
+	}
+	files := make(EnvFiles, 0, len(items))
+	for _, item := range items {
+		if item.Kind == yaml.ScalarNode {
+			files = append(files, item.Value)
+			continue
+		}
+		var file struct {
+			Path     string `yaml:"path"`
+			Optional bool   `yaml:"optional"`
+		}
+		if err := item.Decode(&file); err != nil {
+			return err
+		}
+		if file.Optional && !strings.HasSuffix(file.Path, optionalEnvFileSuffix) {
+			file.Path += optionalEnvFileSuffix
+		}
+		files = append(files, file.Path)
+	}
+	*f = files
+	return nil
+}
+
+// splitEnvFile returns the path of an env file entry, and whether the file may not exist.
+func splitEnvFile(envFile string) (string, bool) {
+	path, optional := strings.CutSuffix(envFile, optionalEnvFileSuffix)
+	return path, optional
+}
+
 // resolveEnvFilePath resolves an env file path relative to the process cwd.
 func resolveEnvFilePath(envFile string, cwd string) string {
 	if filepath.IsAbs(envFile) {
@@ -14,7 +61,37 @@ func resolveEnvFilePath(envFile string, cwd string) string {
 	return filepath.Join(cwd, envFile)
 }
 
+// resolveEnvFileEntry resolves the path of an env file entry relative to the process cwd, keeping its optional suffix.
+func resolveEnvFileEntry(envFile string, cwd string) string {
+	path, optional := splitEnvFile(envFile)
+	resolved := resolveEnvFilePath(path, cwd)
+	if optional {
+		resolved += optionalEnvFileSuffix
+	}
+	return resolved
+}
+
 // parseEnvFile reads a .env file and returns key-value pairs.
 func parseEnvFile(path string) (map[string]string, error) {
 	return godotenv.Read(path)
 }
+
+// loadEnvFiles reads env files in order, later files overriding earlier ones.
+// Optional files that do not exist are skipped.
+func loadEnvFiles(envFiles []string, cwd string) (map[string]string, error) {
+	merged := make(map[string]string)
+	for _, envFile := range envFiles {
+		path, optional := splitEnvFile(envFile)
+		vars, err := parseEnvFile(resolveEnvFilePath(path, cwd))
+		if err != nil {
+			if optional && errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("%q: %w", path, err)
+		}
+		for k, v := range vars {
+			merged[k] = v
+		}
+	}
+	return merged, nil
+}
@@ -1,8 +1,14 @@
 package backend
 
 import (
+	"maps"
+	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestParseEnvFile(t *testing.T) {
@@ -71,3 +77,75 @@ func TestResolveEnvFilePath(t *testing.T) {
 		}
 	})
 }
+
+func TestEnvFilesUnmarshal(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		yaml     string
+		expected EnvFiles
+	}{
+		{`.env`, EnvFiles{".env"}},
+		{"[.env, '.env.local?']", EnvFiles{".env", ".env.local?"}},
+		{"- .env\n- .env.local?\n", EnvFiles{".env", ".env.local?"}},
+		{`[{path: .env.development, optional: true}, {path: .env.test}]`, EnvFiles{".env.development?", ".env.test"}},
+	}
+	for _, tc := range cases {
+		var files EnvFiles
+		if err := yaml.Unmarshal([]byte(tc.yaml), &files); err != nil {
+			t.Fatalf("failed to decode %s: %v", tc.yaml, err)
+		}
+		if !slices.Equal(files, tc.expected) {
+			t.Errorf("%s: expected %v, got %v", tc.yaml, tc.expected, files)
+		}
+	}
+}
+
+func TestLoadEnvFiles(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, ".env"), "HOST=localhost\nPORT=3000\n")
+	writeTestFile(t, filepath.Join(dir, ".env.local"), "PORT=4000\n")
+
+	env, err := loadEnvFiles([]string{".env", ".env.local", ".env.development?"}, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !maps.Equal(env, map[string]string{"HOST": "localhost", "PORT": "4000"}) {
+		t.Errorf("expected later files to override earlier ones, and missing optional files to be skipped, got %v", env)
+	}
+
+	_, err = loadEnvFiles([]string{".env", ".env.development"}, dir)
+	if err == nil || !strings.Contains(err.Error(), `".env.development"`) {
+		t.Errorf("expected an error naming the missing file, got %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, ".env.dir"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadEnvFiles([]string{".env.dir?"}, dir); err == nil {
+		t.Error("expected optional files that exist but cannot be read to fail")
+	}
+}
+
+func TestProjectEnvFile(t *testing.T) {
+	t.Parallel()
+	path := writeIncludeProject(t, map[string]string{
+		"config.yml": "project_name: env\nenv_file: [.env, \".env.local?\"]\ninclude: [services/api.yml]\n" +
+			"processes:\n  - name: web\n    base_command: serve\n    cwd: web\n    env_file: .env.web\n",
+		"services/api.yml": "processes:\n  - name: api\n    base_command: serve\n",
+	})
+	dir := filepath.Dir(path)
+
+	result := validateConfigFile(path)
+	if !result.IsValid {
+		t.Fatalf("expected a valid config, got %+v", result.Errors)
+	}
+	web, api := result.Config.Processes[0], result.Config.Processes[1]
+	project := []string{filepath.Join(dir, ".env"), filepath.Join(dir, ".env.local") + "?"}
+	if !slices.Equal(web.EnvFile, EnvFiles(append(project, ".env.web"))) {
+		t.Errorf("expected project env files before the process ones, got %v", web.EnvFile)
+	}
+	if !slices.Equal(api.EnvFile, EnvFiles(project)) {
+		t.Errorf("expected project env files to apply to included processes, got %v", api.EnvFile)
+	}
+}
@@ -0,0 +1,89 @@
+package backend
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	defaultHealthIntervalMs       = 10_000
+	defaultHealthTimeoutMs        = 2000
+	defaultHealthFailureThreshold = 3
+)
+
+// healthChecker runs the periodic liveness check of a single spawned process.
+type healthChecker struct {
+	cfg       HealthConfig
+	cwd       string
+	env       []string
+	interval  time.Duration
+	timeout   time.Duration
+	threshold int
+}
+
+// newHealthChecker applies defaults to a healthcheck config. Returns nil when none is set.
+func newHealthChecker(cfg *HealthConfig, cwd string, env []string) *healthChecker {
+	if cfg == nil {
+		return nil
+	}
+	intervalMs := defaultHealthIntervalMs
+	if cfg.IntervalMs != nil {
+		intervalMs = *cfg.IntervalMs
+	}
+	timeoutMs := defaultHealthTimeoutMs
+	if cfg.TimeoutMs != nil {
+		timeoutMs = *cfg.TimeoutMs
+	}
+	threshold := defaultHealthFailureThreshold
+	if cfg.FailureThreshold != nil {
+		threshold = *cfg.FailureThreshold
+	}
+	return &healthChecker{
+		cfg:       *cfg,
+		cwd:       cwd,
+		env:       env,
+		interval:  time.Duration(intervalMs) * time.Millisecond,
+		timeout:   time.Duration(timeoutMs) * time.Millisecond,
+		threshold: threshold,
+	}
+}
+
+// check runs every configured check once. All of them must pass.
+func (h *healthChecker) check() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	defer cancel()
+	if h.cfg.TCP != nil && !checkTCP(ctx, *h.cfg.TCP) {
+		return false
+	}
+	if h.cfg.HTTP != nil && !checkHTTP(ctx, *h.cfg.HTTP) {
+		return false
+	}
+	if h.cfg.Command != nil && !checkCommand(ctx, *h.cfg.Command, h.cwd, h.env) {
+		return false
+	}
+	return true
+}
+
+// waitUntilUnhealthy checks every interval until the failure threshold is reached in a row,
+// or exited is closed. Returns the number of consecutive failures, or 0 if the process exited first.
+func (h *healthChecker) waitUntilUnhealthy(exited <-chan struct{}) int {
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	failures := 0
+	for {
+		select {
+		case <-exited:
+			return 0
+		case <-ticker.C:
+		}
+		if h.check() {
+			failures = 0
+			continue
+		}
+		failures++
+		if failures >= h.threshold {
+			return failures
+		}
+	}
+}
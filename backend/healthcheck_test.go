@@ -0,0 +1,57 @@
+package backend
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestNewHealthChecker_Defaults(t *testing.T) {
+	t.Parallel()
+
+	if newHealthChecker(nil, "", nil) != nil {
+		t.Fatal("expected nil checker for nil config")
+	}
+
+	health := newHealthChecker(&HealthConfig{Command: strPtr("true")}, "", nil)
+	if health.interval != defaultHealthIntervalMs*time.Millisecond {
+		t.Errorf("interval = %v, want %v", health.interval, defaultHealthIntervalMs*time.Millisecond)
+	}
+	if health.timeout != defaultHealthTimeoutMs*time.Millisecond {
+		t.Errorf("timeout = %v, want %v", health.timeout, defaultHealthTimeoutMs*time.Millisecond)
+	}
+	if health.threshold != defaultHealthFailureThreshold {
+		t.Errorf("threshold = %d, want %d", health.threshold, defaultHealthFailureThreshold)
+	}
+}
+
+func TestHealthChecker_WaitUntilUnhealthy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reports failures once threshold is reached", func(t *testing.T) {
+		t.Parallel()
+		health := newHealthChecker(&HealthConfig{
+			Command:          strPtr("false"),
+			IntervalMs:       intPtr(10),
+			FailureThreshold: intPtr(3),
+		}, t.TempDir(), os.Environ())
+
+		if got := health.waitUntilUnhealthy(make(chan struct{})); got != 3 {
+			t.Errorf("waitUntilUnhealthy() = %d, want 3", got)
+		}
+	})
+
+	t.Run("returns zero when the process exits", func(t *testing.T) {
+		t.Parallel()
+		health := newHealthChecker(&HealthConfig{
+			Command:    strPtr("true"),
+			IntervalMs: intPtr(10),
+		}, t.TempDir(), os.Environ())
+
+		exited := make(chan struct{})
+		time.AfterFunc(100*time.Millisecond, func() { close(exited) })
+		if got := health.waitUntilUnhealthy(exited); got != 0 {
+			t.Errorf("waitUntilUnhealthy() = %d, want 0", got)
+		}
+	})
+}
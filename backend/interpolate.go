@@ -0,0 +1,279 @@
+package backend
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ${NAME} and ${NAME:-default} references, and the $${ escape of a literal ${
+var variablePattern = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// Names of the variables of vars, which are also the names of environment variables.
+var variableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Built-in variables, set from the location of the config and cannot be redefined in vars
+const (
+	builtinProjectRoot = "PROJECT_ROOT"
+	builtinConfigDir   = "CONFIG_DIR"
+)
+
+// interpolationMode decides what happens to references that cannot be resolved.
+type interpolationMode int
+
+const (
+	// Unresolved references and escapes are kept for a later pass
+	interpolatePartial interpolationMode = iota
+	// Unresolved references use their default, or are removed
+	interpolateFinal
+	// Unresolved references use their default, or are kept literally
+	interpolateCommand
+)
+
+// variableLookup returns the value of a variable, and whether it is set.
+type variableLookup func(name string) (string, bool)
+
+// interpolate replaces the variable references of s.
+func interpolate(s string, lookup variableLookup, mode interpolationMode) string {
+	if !strings.Contains(s, "${") {
+		return s
+	}
+	return variablePattern.ReplaceAllStringFunc(s, func(match string) string {
+		if match == "$${" {
+			if mode == interpolatePartial {
+				return match
+			}
+			return "${"
+		}
+		groups := variablePattern.FindStringSubmatch(match)
+		if value, ok := lookup(groups[1]); ok {
+			return value
+		}
+		if mode == interpolatePartial || (mode == interpolateCommand && !strings.Contains(match, ":-")) {
+			return match
+		}
+		return groups[2]
+	})
+}
+
+// escapeVariables escapes the references of a value that must be passed literally (e.g. input arg values).
+func escapeVariables(s string) string {
+	return strings.ReplaceAll(s, "${", "$${")
+}
+
+// unescapeVariables turns the $${ escapes of s into literal ${.
+func unescapeVariables(s string) string {
+	return strings.ReplaceAll(s, "$${", "${")
+}
+
+// resolveCommand resolves the process environment references of a command when it starts. A shell expands
+// them itself from the same environment, so that values are never run as shell code and shell-local
+// references (e.g. loop variables) keep working: only escapes are resolved. Without a shell, each word is
+// resolved on its own and quoted back, so that a value always stays within its argument.
+func resolveCommand(command string, shell string, env map[string]string) (string, error) {
+	if shell != shellNone {
+		return unescapeVariables(command), nil
+	}
+	words, err := splitShellWords(command)
+	if err != nil {
+		return "", fmt.Errorf("parsing command: %w", err)
+	}
+	lookup := lookupChain(env)
+	for i, word := range words {
+		words[i] = shellQuote(interpolate(word, lookup, interpolateCommand))
+	}
+	return strings.Join(words, " "), nil
+}
+
+// resolveVariables interpolates a set of variables that can reference each other, falling back to
+// lookup for the others. A variable referencing itself (e.g. PATH: "${PATH}:./bin") gets the fallback value.
+func resolveVariables(values map[string]string, fallback variableLookup) map[string]string {
+	resolved := make(map[string]string, len(values))
+	var resolve func(name string, stack []string) string
+	resolve = func(name string, stack []string) string {
+		if value, done := resolved[name]; done {
+			return value
+		}
+		stack = append(stack, name)
+		value := interpolate(values[name], func(ref string) (string, bool) {
+			if _, defined := values[ref]; defined && !slices.Contains(stack, ref) {
+				return resolve(ref, stack), true
+			}
+			return fallback(ref)
+		}, interpolateFinal)
+		resolved[name] = value
+		return value
+	}
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		resolve(name, nil)
+	}
+	return resolved
+}
+
+// lookupChain looks variables up in each map in turn, then in the system environment.
+func lookupChain(layers ...map[string]string) variableLookup {
+	return func(name string) (string, bool) {
+		for _, layer := range layers {
+			if value, ok := layer[name]; ok {
+				return value, true
+			}
+		}
+		return os.LookupEnv(name)
+	}
+}
+
+// --- Config variables ---
+
+// configVariables holds the vars of a config, resolved from each other and the system environment.
+type configVariables struct {
+	vars        map[string]string
+	projectRoot string
+}
+
+// newConfigVariables validates the vars of a config and resolves them. projectRoot is empty for
+// content validated without a file, in which case the built-ins are left for a later pass.
+func newConfigVariables(config map[string]any, projectRoot string, errors *[]ValidationError) *configVariables {
+	v := &configVariables{projectRoot: projectRoot}
+	raw, exists := config["vars"]
+	if !exists {
+		return v
+	}
+	vars, ok := raw.(map[string]any)
+	if !ok {
+		*errors = append(*errors, ValidationError{Message: "vars must be an object", Path: "vars"})
+		return v
+	}
+	values := make(map[string]string, len(vars))
+	for _, name := range slices.Sorted(maps.Keys(vars)) {
+		path := "vars." + name
+		switch {
+		case !variableNamePattern.MatchString(name):
+			*errors = append(*errors, ValidationError{Message: fmt.Sprintf("vars.%s must be a valid variable name", name), Path: path})
+		case name == builtinProjectRoot || name == builtinConfigDir:
+			*errors = append(*errors, ValidationError{Message: fmt.Sprintf("vars.%s cannot override a built-in variable", name), Path: path})
+		default:
+			value, ok := vars[name].(string)
+			if !ok {
+				*errors = append(*errors, ValidationError{Message: fmt.Sprintf("vars.%s must be a string", name), Path: path})
+				continue
+			}
+			values[name] = value
+		}
+	}
+	v.vars = resolveVariables(values, withSystemEnv(v.lookup(projectRoot)))
+	return v
+}
+
+// lookup looks up the built-in variables for a file in configDir, then the vars, without the system environment.
+func (v *configVariables) lookup(configDir string) variableLookup {
+	return func(name string) (string, bool) {
+		switch {
+		case name == builtinProjectRoot:
+			return v.projectRoot, v.projectRoot != ""
+		case name == builtinConfigDir:
+			return configDir, configDir != ""
+		default:
+			value, ok := v.vars[name]
+			return value, ok
+		}
+	}
+}
+
+// withSystemEnv falls back to the system environment for the variables lookup cannot resolve.
+func withSystemEnv(lookup variableLookup) variableLookup {
+	return func(name string) (string, bool) {
+		if value, ok := lookup(name); ok {
+			return value, true
+		}
+		return os.LookupEnv(name)
+	}
+}
+
+// apply interpolates the vars and built-ins in a process defined in configDir. Paths (cwd, env_file)
+// are fully resolved, including from the system environment. Commands and env values keep what
+// they cannot resolve yet, to be resolved from the process environment when it starts.
+func (v *configVariables) apply(raw any, configDir string) any {
+	process, ok := raw.(map[string]any)
+	if !ok {
+		return raw
+	}
+	partial := v.lookup(configDir)
+
+	process = maps.Clone(process)
+	if value, ok := process["cwd"].(string); ok {
+		process["cwd"] = v.path(value, configDir)
+	}
+	if envFile, exists := process["env_file"]; exists {
+		process["env_file"] = v.envFile(envFile, configDir)
+	}
+	if value, ok := process["base_command"].(string); ok {
+		process["base_command"] = interpolate(value, partial, interpolatePartial)
+	}
+	if argv, ok := process["command"].([]any); ok {
+		interpolated := make([]any, len(argv))
+		for i, part := range argv {
+			if s, ok := part.(string); ok {
+				part = interpolate(s, partial, interpolatePartial)
+			}
+			interpolated[i] = part
+		}
+		process["command"] = interpolated
+	}
+	if env, ok := process["env"].(map[string]any); ok {
+		interpolated := make(map[string]any, len(env))
+		for key, value := range env {
+			if s, ok := value.(string); ok {
+				value = interpolate(s, partial, interpolatePartial)
+			}
+			interpolated[key] = value
+		}
+		process["env"] = interpolated
+	}
+	return process
+}
+
+// path fully resolves the variables of a path of a file in configDir, including from the system environment.
+func (v *configVariables) path(s string, configDir string) string {
+	return interpolate(s, withSystemEnv(v.lookup(configDir)), interpolateFinal)
+}
+
+// envFile resolves the variables of the paths of an env_file value (a path, or a list of paths and
+// {path, optional} objects) of a file in configDir.
+func (v *configVariables) envFile(raw any, configDir string) any {
+	switch value := raw.(type) {
+	case string:
+		return v.path(value, configDir)
+	case []any:
+		files := make([]any, len(value))
+		for i, f := range value {
+			switch file := f.(type) {
+			case string:
+				f = v.path(file, configDir)
+			case map[string]any:
+				if path, ok := file["path"].(string); ok {
+					file = maps.Clone(file)
+					file["path"] = v.path(path, configDir)
+					f = file
+				}
+			}
+			files[i] = f
+		}
+		return files
+	default:
+		return raw
+	}
+}
+
+// --- Process environment ---
+
+// mergeProcessEnv merges the explicit env of a process over its env file, resolving the references
+// of explicit values from each other, the env file and the system environment.
+func mergeProcessEnv(env map[string]string, fileEnv map[string]string) map[string]string {
+	merged := make(map[string]string, len(env)+len(fileEnv))
+	maps.Copy(merged, fileEnv)
+	maps.Copy(merged, resolveVariables(env, lookupChain(fileEnv)))
+	return merged
+}
@@ -0,0 +1,142 @@
+package backend
+
+import (
+	"maps"
+	"path/filepath"
+	"testing"
+)
+
+func TestInterpolate(t *testing.T) {
+	t.Parallel()
+	values := map[string]string{"HOST": "localhost", "EMPTY": ""}
+	lookup := func(name string) (string, bool) {
+		value, ok := values[name]
+		return value, ok
+	}
+
+	cases := []struct {
+		input    string
+		partial  string
+		expected string
+		command  string
+	}{
+		{"http://${HOST}:3000", "http://localhost:3000", "http://localhost:3000", "http://localhost:3000"},
+		{"${PORT:-3000}", "${PORT:-3000}", "3000", "3000"},
+		{"${HOST:-other}", "localhost", "localhost", "localhost"},
+		{"[${EMPTY:-default}]", "[]", "[]", "[]"},
+		{"[${MISSING}]", "[${MISSING}]", "[]", "[${MISSING}]"},
+		{"$${HOST} ${HOST}", "$${HOST} localhost", "${HOST} localhost", "${HOST} localhost"},
+		{"$HOST ${1} ${HOST", "$HOST ${1} ${HOST", "$HOST ${1} ${HOST", "$HOST ${1} ${HOST"},
+	}
+	for _, tc := range cases {
+		if got := interpolate(tc.input, lookup, interpolatePartial); got != tc.partial {
+			t.Errorf("partial %q: expected %q, got %q", tc.input, tc.partial, got)
+		}
+		if got := interpolate(tc.input, lookup, interpolateFinal); got != tc.expected {
+			t.Errorf("final %q: expected %q, got %q", tc.input, tc.expected, got)
+		}
+		if got := interpolate(tc.input, lookup, interpolateCommand); got != tc.command {
+			t.Errorf("command %q: expected %q, got %q", tc.input, tc.command, got)
+		}
+	}
+}
+
+func TestResolveCommand(t *testing.T) {
+	t.Parallel()
+	env := map[string]string{"MSG": "it's; here"}
+
+	cases := []struct {
+		command  string
+		shell    string
+		expected string
+	}{
+		{`echo ${MSG} "${f}" '$${MSG}'`, "sh", `echo ${MSG} "${f}" '${MSG}'`},
+		{`echo ${MSG} "${f}" '$${MSG}'`, "", `echo ${MSG} "${f}" '${MSG}'`},
+		{`echo ${MSG} "${f}" '$${MSG}'`, "none", `echo 'it'\''s; here' '${f}' '${MSG}'`},
+		{`echo "[${MSG}]" ''`, "none", `echo '[it'\''s; here]' ''`},
+	}
+	for _, tc := range cases {
+		got, err := resolveCommand(tc.command, tc.shell, env)
+		if err != nil {
+			t.Fatalf("%q with shell %q: unexpected error: %v", tc.command, tc.shell, err)
+		}
+		if got != tc.expected {
+			t.Errorf("%q with shell %q: expected %q, got %q", tc.command, tc.shell, tc.expected, got)
+		}
+	}
+
+	if _, err := resolveCommand("echo 'open", "none", env); err == nil {
+		t.Error("expected an error for an unterminated quote")
+	}
+}
+
+func TestResolveVariables(t *testing.T) {
+	t.Parallel()
+	fallback := func(name string) (string, bool) {
+		if name == "PATH" {
+			return "/usr/bin", true
+		}
+		return "", false
+	}
+	values := map[string]string{
+		"URL":  "http://${HOST}:${PORT}",
+		"HOST": "localhost",
+		"PORT": "${DEFAULT_PORT:-3000}",
+		"PATH": "./bin:${PATH}",
+		"A":    "${B}",
+		"B":    "${A}",
+	}
+
+	expected := map[string]string{
+		"URL":  "http://localhost:3000",
+		"HOST": "localhost",
+		"PORT": "3000",
+		"PATH": "./bin:/usr/bin",
+		"A":    "",
+		"B":    "",
+	}
+	if got := resolveVariables(values, fallback); !maps.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestMergeProcessEnv(t *testing.T) {
+	t.Setenv("CLICK_LAUNCH_TEST_HOME", "/home/test")
+	env := map[string]string{"URL": "http://${DB_HOST}:${PORT}", "PORT": "5432", "DATA": "${CLICK_LAUNCH_TEST_HOME}/data"}
+	fileEnv := map[string]string{"DB_HOST": "db", "PORT": "1234"}
+
+	expected := map[string]string{"URL": "http://db:5432", "PORT": "5432", "DB_HOST": "db", "DATA": "/home/test/data"}
+	if got := mergeProcessEnv(env, fileEnv); !maps.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestConfigVariables(t *testing.T) {
+	t.Setenv("CLICK_LAUNCH_TEST_STAGE", "dev")
+	path := writeIncludeProject(t, map[string]string{
+		"config.yml": "project_name: vars\ninclude: [services/api.yml]\nvars:\n  APP: acme-${CLICK_LAUNCH_TEST_STAGE}\n" +
+			"processes:\n  - name: web\n    base_command: serve ${APP} ${PORT}\n    cwd: ${PROJECT_ROOT}/web\n",
+		"services/api.yml": "processes:\n  - name: api\n    command: [run, '${APP}', '${CONFIG_DIR}']\n    env_file: ${CONFIG_DIR}/.env\n" +
+			"    env:\n      LOG_DIR: ${PROJECT_ROOT}/logs\n      URL: http://${HOST:-localhost}\n",
+	})
+	dir := filepath.Dir(path)
+	services := filepath.Join(dir, "services")
+
+	result := validateConfigFile(path)
+	if !result.IsValid {
+		t.Fatalf("expected a valid config, got %+v", result.Errors)
+	}
+	web, api := result.Config.Processes[0], result.Config.Processes[1]
+	if web.BaseCommand != "serve acme-dev ${PORT}" || *web.Cwd != filepath.Join(dir, "web") {
+		t.Errorf("expected vars and built-ins in web, and env references kept, got %q in %q", web.BaseCommand, *web.Cwd)
+	}
+	if api.Command[1] != "acme-dev" || api.Command[2] != services || api.EnvFile[0] != filepath.Join(services, ".env") {
+		t.Errorf("expected CONFIG_DIR to be the directory of the included file, got %v / %v", api.Command, api.EnvFile)
+	}
+	if api.Env["LOG_DIR"] != filepath.Join(dir, "logs") || api.Env["URL"] != "http://${HOST:-localhost}" {
+		t.Errorf("expected env values to keep unresolved references until start, got %v", api.Env)
+	}
+	if result.Config.Vars["APP"] != "acme-${CLICK_LAUNCH_TEST_STAGE}" {
+		t.Errorf("expected vars to be returned as written, got %v", result.Config.Vars)
+	}
+}
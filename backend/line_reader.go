@@ -0,0 +1,176 @@
+package backend
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// Time without new output after which a line still missing its newline (a prompt, a progress bar) is shown
+	partialLineFlushMs = 100
+	// Lines longer than this are split into several entries, matching the previous scanner limit
+	maxOutputLineBytes   = 1024 * 1024
+	outputReadBufferSize = 32 * 1024
+)
+
+// outputLine is a line read from a process output.
+// Every line is emitted once complete, possibly after partial previews of it.
+type outputLine struct {
+	text string
+	// partial lines have not received their newline yet, and will be emitted again
+	partial bool
+	// replace lines replace the previous line of the stream: a partial preview, or a line overwritten with \r
+	replace bool
+}
+
+// outputLineReader splits output into lines. Lines missing their newline are flushed once the output
+// has been idle for the timeout, and a \r restarts the current line (as terminals do for progress bars).
+type outputLineReader struct {
+	emit    func(outputLine)
+	timeout time.Duration
+	mu      sync.Mutex
+	line    []byte
+	// pendingCR is set when the output ended with \r, which may be the start of a \r\n
+	pendingCR bool
+	// shown is set once a preview of the current line was emitted, dirty once it changed since then
+	shown      bool
+	dirty      bool
+	lastEmitAt time.Time
+	timer      *time.Timer
+	// idleAt is when the output is considered idle, as the timer may fire just before being reset
+	idleAt time.Time
+}
+
+// readOutputLines reads pipe until it is closed or fails, emitting its lines as they come.
+func readOutputLines(pipe io.Reader, timeout time.Duration, emit func(outputLine)) {
+	r := &outputLineReader{emit: emit, timeout: timeout}
+	buf := make([]byte, outputReadBufferSize)
+	for {
+		n, err := pipe.Read(buf)
+		if n > 0 {
+			r.write(buf[:n])
+		}
+		if err != nil {
+			break
+		}
+	}
+	r.close()
+}
+
+func (r *outputLineReader) write(data []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for len(data) > 0 {
+		if r.pendingCR {
+			r.pendingCR = false
+			if data[0] == '\n' {
+				// Terminals end lines with \r\n
+				r.endLineLocked()
+				data = data[1:]
+				continue
+			}
+			r.overwriteLocked()
+		}
+		i := bytes.IndexAny(data, "\r\n")
+		if i < 0 {
+			r.appendLocked(data)
+			break
+		}
+		r.appendLocked(data[:i])
+		if data[i] == '\n' {
+			r.endLineLocked()
+		} else {
+			r.pendingCR = true
+		}
+		data = data[i+1:]
+	}
+	if r.dirty {
+		r.resetTimerLocked()
+	}
+}
+
+// appendLocked adds text to the current line, splitting it once it grows over maxOutputLineBytes.
+func (r *outputLineReader) appendLocked(text []byte) {
+	if len(text) == 0 {
+		return
+	}
+	r.line = append(r.line, text...)
+	r.dirty = true
+	for len(r.line) > maxOutputLineBytes {
+		// Avoid splitting a multi-byte character
+		cut := maxOutputLineBytes
+		for cut > 0 && !utf8.RuneStart(r.line[cut]) {
+			cut--
+		}
+		if cut == 0 {
+			cut = maxOutputLineBytes
+		}
+		r.emitLocked(outputLine{text: string(r.line[:cut]), replace: r.shown})
+		r.line = append(r.line[:0], r.line[cut:]...)
+		r.shown = false
+	}
+}
+
+// endLineLocked emits the current line as complete and starts a new one.
+func (r *outputLineReader) endLineLocked() {
+	r.emitLocked(outputLine{text: string(r.line), replace: r.shown})
+	r.line = r.line[:0]
+	r.shown = false
+	r.dirty = false
+}
+
+// overwriteLocked restarts the current line after a \r. Overwritten content is only shown
+// if nothing was emitted for a while, so that a fast progress bar is not turned into hundreds of lines.
+func (r *outputLineReader) overwriteLocked() {
+	if r.dirty && time.Since(r.lastEmitAt) >= r.timeout {
+		r.previewLocked()
+	}
+	r.line = r.line[:0]
+	r.dirty = false
+}
+
+// previewLocked emits the current line as partial.
+func (r *outputLineReader) previewLocked() {
+	r.emitLocked(outputLine{text: string(r.line), partial: true, replace: r.shown})
+	r.shown = true
+	r.dirty = false
+}
+
+func (r *outputLineReader) emitLocked(line outputLine) {
+	r.lastEmitAt = time.Now()
+	r.emit(line)
+}
+
+func (r *outputLineReader) resetTimerLocked() {
+	r.idleAt = time.Now().Add(r.timeout)
+	if r.timer == nil {
+		r.timer = time.AfterFunc(r.timeout, r.flushIfIdle)
+		return
+	}
+	r.timer.Reset(r.timeout)
+}
+
+func (r *outputLineReader) flushIfIdle() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.dirty || time.Now().Before(r.idleAt) {
+		return
+	}
+	r.previewLocked()
+}
+
+// close emits the last line once the output ends, if it did not end with a newline.
+func (r *outputLineReader) close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.timer != nil {
+		r.timer.Stop()
+	}
+	if r.dirty || r.shown {
+		r.endLineLocked()
+	}
+}
@@ -0,0 +1,124 @@
+package backend
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// lineRecorder collects the lines emitted by an outputLineReader.
+type lineRecorder struct {
+	mu    sync.Mutex
+	lines []outputLine
+}
+
+func (l *lineRecorder) emit(line outputLine) {
+	l.mu.Lock()
+	l.lines = append(l.lines, line)
+	l.mu.Unlock()
+}
+
+func (l *lineRecorder) get() []outputLine {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]outputLine(nil), l.lines...)
+}
+
+func TestReadOutputLines(t *testing.T) {
+	t.Parallel()
+
+	longLine := strings.Repeat("a", maxOutputLineBytes) + "bc"
+	cases := []struct {
+		name     string
+		output   string
+		expected []outputLine
+	}{
+		{
+			name:     "lines",
+			output:   "first\n\nsecond\r\n",
+			expected: []outputLine{{text: "first"}, {text: ""}, {text: "second"}},
+		},
+		{
+			name:     "missing newline",
+			output:   "first\nprompt> ",
+			expected: []outputLine{{text: "first"}, {text: "prompt> "}},
+		},
+		{
+			name:   "carriage return",
+			output: "Update 1\rUpdate 2\rUpdate 3\n",
+			// The first overwrite is shown, the next ones only once output goes idle
+			expected: []outputLine{{text: "Update 1", partial: true}, {text: "Update 3", replace: true}},
+		},
+		{
+			name:     "oversized line",
+			output:   longLine + "\n",
+			expected: []outputLine{{text: longLine[:maxOutputLineBytes]}, {text: "bc"}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			recorder := &lineRecorder{}
+			readOutputLines(strings.NewReader(tc.output), time.Minute, recorder.emit)
+			got := recorder.get()
+			if len(got) != len(tc.expected) {
+				t.Fatalf("expected %d lines, got %d", len(tc.expected), len(got))
+			}
+			for i := range got {
+				if got[i] != tc.expected[i] {
+					t.Errorf("line %d: expected %+v, got %+v", i, tc.expected[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReadOutputLines_SplitsOnRuneBoundary(t *testing.T) {
+	t.Parallel()
+	output := strings.Repeat("a", maxOutputLineBytes-1) + "é\n"
+	recorder := &lineRecorder{}
+	readOutputLines(strings.NewReader(output), time.Minute, recorder.emit)
+
+	got := recorder.get()
+	if len(got) != 2 || got[1].text != "é" {
+		t.Errorf("expected the multi-byte character to start the second line, got %d lines", len(got))
+	}
+}
+
+func TestOutputLineReader_FlushesPartialLines(t *testing.T) {
+	t.Parallel()
+	recorder := &lineRecorder{}
+	reader := &outputLineReader{emit: recorder.emit, timeout: 20 * time.Millisecond}
+
+	reader.write([]byte("Name: "))
+	deadline := time.Now().Add(2 * time.Second)
+	for len(recorder.get()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	reader.write([]byte("bob\n"))
+	reader.close()
+
+	expected := []outputLine{{text: "Name: ", partial: true}, {text: "Name: bob", replace: true}}
+	got := recorder.get()
+	if len(got) != len(expected) || got[0] != expected[0] || got[1] != expected[1] {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+}
+
+func TestOutputLineReader_CarriageReturnAcrossWrites(t *testing.T) {
+	t.Parallel()
+	recorder := &lineRecorder{}
+	reader := &outputLineReader{emit: recorder.emit, timeout: time.Minute}
+
+	reader.write([]byte("done\r"))
+	reader.write([]byte("\nnext\n"))
+	reader.close()
+
+	expected := []outputLine{{text: "done"}, {text: "next"}}
+	got := recorder.get()
+	if len(got) != len(expected) || got[0] != expected[0] || got[1] != expected[1] {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+}
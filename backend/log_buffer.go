@@ -0,0 +1,70 @@
+package backend
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+)
+
+// Log entries kept in memory per process, matching the default log buffer size of the UI
+const logBufferCapacity = 10_000
+
+// logRingBuffer keeps the most recent log entries of a process, oldest first.
+// It grows as entries are pushed, up to its capacity, after which the oldest entries are evicted.
+type logRingBuffer struct {
+	name     string
+	entries  []ProcessLogData
+	capacity int
+	start    int // index of the oldest entry once the buffer is full
+}
+
+func newLogRingBuffer(name string, capacity int) *logRingBuffer {
+	return &logRingBuffer{name: name, capacity: capacity}
+}
+
+func (b *logRingBuffer) push(entry ProcessLogData) {
+	if len(b.entries) < b.capacity {
+		b.entries = append(b.entries, entry)
+		return
+	}
+	b.entries[b.start] = entry
+	b.start = (b.start + 1) % len(b.entries)
+}
+
+// at returns the i-th oldest entry.
+func (b *logRingBuffer) at(i int) ProcessLogData {
+	return b.entries[(b.start+i)%len(b.entries)]
+}
+
+// since returns up to limit entries (all when limit <= 0) whose cursor is after sinceCursor,
+// and whether more entries follow them.
+func (b *logRingBuffer) since(sinceCursor int64, limit int) ([]ProcessLogData, bool) {
+	// Cursors are increasing, so the first entry after sinceCursor can be binary searched
+	first := sort.Search(len(b.entries), func(i int) bool {
+		return b.at(i).Cursor > sinceCursor
+	})
+	count := len(b.entries) - first
+	if limit > 0 && count > limit {
+		count = limit
+	}
+	logs := make([]ProcessLogData, count)
+	for i := range logs {
+		logs[i] = b.at(first + i)
+	}
+	return logs, first+count < len(b.entries)
+}
+
+// search returns the entries whose output matches pattern, restricted to types when not empty.
+func (b *logRingBuffer) search(pattern *regexp.Regexp, types []string) []ProcessLogData {
+	logs := []ProcessLogData{}
+	for i := range b.entries {
+		entry := b.at(i)
+		if len(types) > 0 && !slices.Contains(types, entry.Type) {
+			continue
+		}
+		if pattern.MatchString(entry.Output) {
+			logs = append(logs, entry)
+		}
+	}
+	return logs
+}
@@ -0,0 +1,88 @@
+package backend
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func newFilledLogRingBuffer(capacity int, count int) *logRingBuffer {
+	b := newLogRingBuffer("api", capacity)
+	for i := 1; i <= count; i++ {
+		logType := "stdout"
+		if i%2 == 0 {
+			logType = "stderr"
+		}
+		b.push(ProcessLogData{Cursor: int64(i), Type: logType, Output: "line " + strconv.Itoa(i) + "\n"})
+	}
+	return b
+}
+
+func logCursors(logs []ProcessLogData) []int64 {
+	cursors := make([]int64, len(logs))
+	for i, entry := range logs {
+		cursors[i] = entry.Cursor
+	}
+	return cursors
+}
+
+func TestLogRingBuffer_Since(t *testing.T) {
+	t.Parallel()
+	b := newFilledLogRingBuffer(5, 8)
+
+	cases := []struct {
+		name        string
+		sinceCursor int64
+		limit       int
+		expected    []int64
+		hasMore     bool
+	}{
+		{"evicted entries are skipped", 0, 0, []int64{4, 5, 6, 7, 8}, false},
+		{"after a cursor", 6, 0, []int64{7, 8}, false},
+		{"limited", 4, 2, []int64{5, 6}, true},
+		{"limit larger than the rest", 6, 10, []int64{7, 8}, false},
+		{"caught up", 8, 0, []int64{}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			logs, hasMore := b.since(tc.sinceCursor, tc.limit)
+			if got := logCursors(logs); !slices.Equal(got, tc.expected) {
+				t.Errorf("expected cursors %v, got %v", tc.expected, got)
+			}
+			if hasMore != tc.hasMore {
+				t.Errorf("expected hasMore %v, got %v", tc.hasMore, hasMore)
+			}
+		})
+	}
+}
+
+func TestLogRingBuffer_Search(t *testing.T) {
+	t.Parallel()
+	b := newFilledLogRingBuffer(10, 12)
+
+	if got := logCursors(b.search(regexp.MustCompile(`line 1\d`), nil)); !slices.Equal(got, []int64{10, 11, 12}) {
+		t.Errorf("expected lines 10 to 12, got %v", got)
+	}
+	if got := logCursors(b.search(regexp.MustCompile(`line`), []string{"stderr"})); !slices.Equal(got, []int64{4, 6, 8, 10, 12}) {
+		t.Errorf("expected the stderr lines only, got %v", got)
+	}
+	if got := b.search(regexp.MustCompile(`nothing`), nil); got == nil || len(got) != 0 {
+		t.Errorf("expected an empty result, got %v", got)
+	}
+}
+
+func TestLogRingBuffer_GrowsLazily(t *testing.T) {
+	t.Parallel()
+	b := newFilledLogRingBuffer(logBufferCapacity, 3)
+	if cap(b.entries) >= logBufferCapacity {
+		t.Errorf("expected the buffer to grow with its entries, got a capacity of %d", cap(b.entries))
+	}
+
+	b = newFilledLogRingBuffer(5, 5)
+	b.push(ProcessLogData{Cursor: 6})
+	if len(b.entries) != 5 || b.at(0).Cursor != 2 || b.at(4).Cursor != 6 {
+		t.Errorf("expected the oldest entry to be evicted once full, got %v", logCursors(b.entries))
+	}
+}
@@ -0,0 +1,146 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const (
+	defaultLogMaxSizeMB = 10
+	defaultLogMaxFiles  = 5
+	// Log files default to <config dir>/logs/click-launch/<process>.log, next to exported logs
+	defaultLogDirectory = "logs/click-launch"
+)
+
+// Characters replaced when turning a process name into a file name.
+var logFileUnsafePattern = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// rotatingLogFile appends log entries to a file, rotating it once it exceeds maxSize:
+// api.log becomes api.log.1, api.log.1 becomes api.log.2, and so on up to maxFiles.
+type rotatingLogFile struct {
+	mu       sync.Mutex
+	path     string
+	maxSize  int64
+	maxFiles int
+	file     *os.File
+	size     int64
+}
+
+// openRotatingLogFile opens (or creates) a log file and its directory, appending to existing content.
+func openRotatingLogFile(path string, maxSizeMB int, maxFiles int) (*rotatingLogFile, error) {
+	f := &rotatingLogFile{path: path, maxSize: int64(maxSizeMB) * 1024 * 1024, maxFiles: maxFiles}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("creating log directory: %w", err)
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *rotatingLogFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening log file: %w", err)
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("opening log file: %w", err)
+	}
+	f.file = file
+	f.size = stat.Size()
+	return nil
+}
+
+// write appends a log entry as a single line. A nil file discards it.
+func (f *rotatingLogFile) write(entry ProcessLogData) error {
+	if f == nil {
+		return nil
+	}
+	line := formatLogFileLine(entry)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return os.ErrClosed
+	}
+	if f.size > 0 && f.size+int64(len(line)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := f.file.WriteString(line)
+	f.size += int64(n)
+	return err
+}
+
+// rotate shifts the rotated files by one, dropping the oldest, and starts a new file.
+func (f *rotatingLogFile) rotate() error {
+	_ = f.file.Close()
+	f.file = nil
+	_ = os.Remove(fmt.Sprintf("%s.%d", f.path, f.maxFiles))
+	for i := f.maxFiles - 1; i >= 1; i-- {
+		_ = os.Rename(fmt.Sprintf("%s.%d", f.path, i), fmt.Sprintf("%s.%d", f.path, i+1))
+	}
+	if err := os.Rename(f.path, f.path+".1"); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("rotating log file: %w", err)
+	}
+	return f.open()
+}
+
+func (f *rotatingLogFile) close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+// formatLogFileLine renders a log entry as "<timestamp> [<type>] <output>".
+// Structured entries are rendered as "<timestamp> [<type>] <LEVEL> <message> <fields>".
+func formatLogFileLine(entry ProcessLogData) string {
+	output := strings.TrimSuffix(entry.Output, "\n")
+	if entry.Level != "" {
+		output = strings.ToUpper(entry.Level) + " " + output
+	}
+	if len(entry.Fields) > 0 {
+		output += " " + formatLogFields(entry.Fields)
+	}
+	if entry.Type == "exit" {
+		switch {
+		case entry.Signal != nil:
+			output = "exited with signal " + *entry.Signal
+		case entry.Code != nil:
+			output = fmt.Sprintf("exited with code %d", *entry.Code)
+		default:
+			output = "exited"
+		}
+	}
+	return fmt.Sprintf("%s [%s] %s\n", entry.Timestamp, entry.Type, output)
+}
+
+// resolveLogsConfig resolves the log file of a process relative to the config directory,
+// defaulting to logs/click-launch/<process>.log. Returns nil when logs is not set.
+func resolveLogsConfig(rootDirectory string, processName string, logs *LogsConfig) *LogsConfig {
+	if logs == nil {
+		return nil
+	}
+	file := filepath.Join(defaultLogDirectory, logFileUnsafePattern.ReplaceAllString(processName, "-")+".log")
+	if logs.File != nil && *logs.File != "" {
+		file = *logs.File
+	}
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(rootDirectory, file)
+	}
+	resolved := *logs
+	resolved.File = &file
+	return &resolved
+}
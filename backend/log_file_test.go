@@ -0,0 +1,120 @@
+package backend
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRotatingLogFile_Rotates(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "nested", "api.log")
+	f, err := openRotatingLogFile(path, 1, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = f.close() })
+	f.maxSize = 64
+
+	for _, output := range []string{"first", "second", "third", "fourth"} {
+		entry := ProcessLogData{Type: "stdout", Output: strings.Repeat(output[:1], 30) + "\n", Timestamp: "ts"}
+		if err := f.write(entry); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	expected := map[string]string{
+		path:        "ts [stdout] " + strings.Repeat("f", 30) + "\n",
+		path + ".1": "ts [stdout] " + strings.Repeat("t", 30) + "\n",
+		path + ".2": "ts [stdout] " + strings.Repeat("s", 30) + "\n",
+	}
+	for file, content := range expected {
+		got, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("expected %s to exist: %v", file, err)
+		}
+		if string(got) != content {
+			t.Errorf("%s: expected %q, got %q", file, content, string(got))
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Error("expected rotated files beyond max_files to be dropped")
+	}
+}
+
+func TestRotatingLogFile_Appends(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "api.log")
+	if err := os.WriteFile(path, []byte("previous run\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := openRotatingLogFile(path, 1, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := f.write(ProcessLogData{Type: "stderr", Output: "boom\n", Timestamp: "ts"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = f.close()
+
+	got, _ := os.ReadFile(path)
+	if string(got) != "previous run\nts [stderr] boom\n" {
+		t.Errorf("expected entries appended to the existing file, got %q", string(got))
+	}
+	var nilFile *rotatingLogFile
+	if err := nilFile.write(ProcessLogData{}); err != nil {
+		t.Errorf("expected a nil file to discard entries, got %v", err)
+	}
+}
+
+func TestFormatLogFileLine(t *testing.T) {
+	t.Parallel()
+
+	code := 1
+	signal := "SIGTERM"
+	cases := []struct {
+		entry    ProcessLogData
+		expected string
+	}{
+		{ProcessLogData{Type: "stdout", Output: "hello\n", Timestamp: "ts"}, "ts [stdout] hello\n"},
+		{ProcessLogData{Type: "exit", Code: &code, Timestamp: "ts"}, "ts [exit] exited with code 1\n"},
+		{ProcessLogData{Type: "exit", Signal: &signal, Timestamp: "ts"}, "ts [exit] exited with signal SIGTERM\n"},
+		{ProcessLogData{Type: "stderr", Output: "failed\n", Level: "error", Fields: map[string]any{"path": "/a b", "status": 500}, Timestamp: "ts"}, `ts [stderr] ERROR failed path="/a b" status=500` + "\n"},
+	}
+	for _, tc := range cases {
+		if got := formatLogFileLine(tc.entry); got != tc.expected {
+			t.Errorf("expected %q, got %q", tc.expected, got)
+		}
+	}
+}
+
+func TestResolveLogsConfig(t *testing.T) {
+	t.Parallel()
+
+	relative := "./out/api.log"
+	absolute := "/var/log/api.log"
+	maxFiles := 2
+	cases := []struct {
+		logs     *LogsConfig
+		expected string
+	}{
+		{&LogsConfig{}, "/project/logs/click-launch/My-API.log"},
+		{&LogsConfig{File: &relative, MaxFiles: &maxFiles}, "/project/out/api.log"},
+		{&LogsConfig{File: &absolute}, "/var/log/api.log"},
+	}
+	for _, tc := range cases {
+		resolved := resolveLogsConfig("/project", "My API", tc.logs)
+		if resolved == nil || resolved.File == nil || *resolved.File != tc.expected {
+			t.Errorf("expected %q, got %+v", tc.expected, resolved)
+			continue
+		}
+		if resolved.MaxFiles != tc.logs.MaxFiles {
+			t.Error("expected the other settings to be kept")
+		}
+	}
+	if resolveLogsConfig("/project", "api", nil) != nil {
+		t.Error("expected no logs config when logs is not set")
+	}
+}
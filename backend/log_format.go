@@ -0,0 +1,267 @@
+package backend
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Supported values of log_format. Text lines are kept as they are.
+const (
+	logFormatText   = "text"
+	logFormatJSON   = "json"
+	logFormatLogfmt = "logfmt"
+)
+
+// Keys read from structured lines, in order of preference (zap, pino, logrus, slog, ...)
+var (
+	logLevelKeys     = []string{"level", "lvl", "severity"}
+	logMessageKeys   = []string{"msg", "message"}
+	logTimestampKeys = []string{"time", "ts", "timestamp"}
+)
+
+// Levels reported in ProcessLogData.Level, from least to most severe.
+var logLevels = []string{"trace", "debug", "info", "warn", "error", "fatal"}
+
+// Aliases of the levels used by common loggers.
+var logLevelAliases = map[string]string{
+	"warning":  "warn",
+	"err":      "error",
+	"critical": "fatal",
+	"panic":    "fatal",
+	"dpanic":   "fatal",
+}
+
+// structuredLogLine is a log line parsed according to its process log_format.
+type structuredLogLine struct {
+	level     string
+	message   string
+	timestamp string
+	fields    map[string]any
+}
+
+// parseLogLine parses a JSON or logfmt line. Returns false for text lines and lines
+// that do not match the format, which are then logged as they are.
+func parseLogLine(format string, line string) (structuredLogLine, bool) {
+	var values map[string]any
+	switch format {
+	case logFormatJSON:
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "{") {
+			return structuredLogLine{}, false
+		}
+		decoder := json.NewDecoder(strings.NewReader(trimmed))
+		decoder.UseNumber()
+		if err := decoder.Decode(&values); err != nil || decoder.More() {
+			return structuredLogLine{}, false
+		}
+	case logFormatLogfmt:
+		values = parseLogfmt(line)
+		if len(values) == 0 {
+			return structuredLogLine{}, false
+		}
+	default:
+		return structuredLogLine{}, false
+	}
+
+	parsed := structuredLogLine{fields: values}
+	if key, value, ok := takeLogField(values, logLevelKeys); ok {
+		parsed.level = normalizeLogLevel(value)
+		if parsed.level == "" {
+			values[key] = value
+		}
+	}
+	if _, value, ok := takeLogField(values, logMessageKeys); ok {
+		if message, isString := value.(string); isString {
+			parsed.message = message
+		} else {
+			parsed.message = formatLogFieldValue(value)
+		}
+	}
+	if key, value, ok := takeLogField(values, logTimestampKeys); ok {
+		parsed.timestamp = normalizeLogTimestamp(value)
+		if parsed.timestamp == "" {
+			values[key] = value
+		}
+	}
+	// Logfmt lines without any known key are most likely plain text with an "=" in it
+	if format == logFormatLogfmt && parsed.level == "" && parsed.message == "" {
+		return structuredLogLine{}, false
+	}
+	if len(parsed.fields) == 0 {
+		parsed.fields = nil
+	}
+	return parsed, true
+}
+
+// apply sets the level, fields, message (as output) and timestamp of a log entry, when found.
+func (p structuredLogLine) apply(entry *ProcessLogData) {
+	entry.Level = p.level
+	entry.Fields = p.fields
+	if p.message != "" {
+		entry.Output = p.message + "\n"
+	}
+	if p.timestamp != "" {
+		entry.Timestamp = p.timestamp
+	}
+}
+
+// takeLogField removes and returns the first of keys found in values.
+func takeLogField(values map[string]any, keys []string) (string, any, bool) {
+	for _, key := range keys {
+		if value, exists := values[key]; exists {
+			delete(values, key)
+			return key, value, true
+		}
+	}
+	return "", nil, false
+}
+
+// normalizeLogLevel maps level names and pino's numeric levels to logLevels.
+// Returns "" for values that are not a level.
+func normalizeLogLevel(value any) string {
+	switch v := value.(type) {
+	case string:
+		level := strings.ToLower(strings.TrimSpace(v))
+		if alias, exists := logLevelAliases[level]; exists {
+			return alias
+		}
+		for _, known := range logLevels {
+			if level == known {
+				return level
+			}
+		}
+		if n, err := strconv.Atoi(level); err == nil {
+			return normalizeLogLevel(json.Number(strconv.Itoa(n)))
+		}
+	case json.Number:
+		// pino: 10 trace, 20 debug, 30 info, 40 warn, 50 error, 60 fatal
+		n, err := v.Int64()
+		if err != nil || n < 10 {
+			return ""
+		}
+		return logLevels[min(int(n/10)-1, len(logLevels)-1)]
+	}
+	return ""
+}
+
+// normalizeLogTimestamp converts RFC 3339 strings and epoch numbers (seconds for zap,
+// milliseconds for pino) to RFC 3339 in UTC. Returns "" for values that are not a timestamp.
+func normalizeLogTimestamp(value any) string {
+	switch v := value.(type) {
+	case string:
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return t.UTC().Format(time.RFC3339Nano)
+		}
+		// Logfmt values are strings, even for epoch timestamps
+		if _, err := strconv.ParseFloat(v, 64); err == nil {
+			return normalizeLogTimestamp(json.Number(v))
+		}
+	case json.Number:
+		// Anything past the year 33658 in seconds is a timestamp in milliseconds
+		if n, err := v.Int64(); err == nil && n >= 1e12 {
+			return time.UnixMilli(n).UTC().Format(time.RFC3339Nano)
+		}
+		f, err := v.Float64()
+		if err != nil || f <= 0 {
+			return ""
+		}
+		sec, frac := math.Modf(f)
+		// Float seconds are only precise to the microsecond
+		return time.Unix(int64(sec), int64(math.Round(frac*1e6))*1000).UTC().Format(time.RFC3339Nano)
+	}
+	return ""
+}
+
+// parseLogfmt parses key=value pairs, where values may be double-quoted.
+// Returns nil when the line is not a sequence of pairs.
+func parseLogfmt(line string) map[string]any {
+	values := make(map[string]any)
+	i := 0
+	for i < len(line) {
+		for i < len(line) && line[i] == ' ' {
+			i++
+		}
+		if i == len(line) {
+			break
+		}
+		start := i
+		for i < len(line) && line[i] != '=' && line[i] != ' ' {
+			i++
+		}
+		key := line[start:i]
+		if key == "" || i == len(line) || line[i] != '=' || !isLogfmtKey(key) {
+			return nil
+		}
+		i++
+
+		if i < len(line) && line[i] == '"' {
+			end := i + 1
+			for end < len(line) && line[end] != '"' {
+				if line[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(line) {
+				return nil
+			}
+			value, err := strconv.Unquote(line[i : end+1])
+			if err != nil {
+				return nil
+			}
+			values[key] = value
+			i = end + 1
+			continue
+		}
+		start = i
+		for i < len(line) && line[i] != ' ' {
+			i++
+		}
+		values[key] = line[start:i]
+	}
+	return values
+}
+
+func isLogfmtKey(key string) bool {
+	for _, r := range key {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '.' && r != '-' && r != '@' {
+			return false
+		}
+	}
+	return true
+}
+
+// formatLogFields renders fields as sorted logfmt pairs, quoting values where needed.
+func formatLogFields(fields map[string]any) string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		value := formatLogFieldValue(fields[key])
+		if value == "" || strings.ContainsAny(value, " \"=\n") {
+			value = strconv.Quote(value)
+		}
+		pairs[i] = key + "=" + value
+	}
+	return strings.Join(pairs, " ")
+}
+
+// formatLogFieldValue renders a field value as in logfmt: strings as is, anything else as JSON.
+func formatLogFieldValue(value any) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
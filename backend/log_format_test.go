@@ -0,0 +1,129 @@
+package backend
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseLogLine(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		format    string
+		line      string
+		ok        bool
+		level     string
+		message   string
+		timestamp string
+		fields    string // as rendered by formatLogFields
+	}{
+		{
+			name:      "zap JSON",
+			format:    logFormatJSON,
+			line:      `{"level":"error","ts":1700000000.5,"caller":"api/main.go:42","msg":"request failed","status":500}`,
+			ok:        true,
+			level:     "error",
+			message:   "request failed",
+			timestamp: "2023-11-14T22:13:20.5Z",
+			fields:    `caller=api/main.go:42 status=500`,
+		},
+		{
+			name:      "pino JSON",
+			format:    logFormatJSON,
+			line:      `{"level":40,"time":1700000000123,"pid":7,"msg":"slow query"}`,
+			ok:        true,
+			level:     "warn",
+			message:   "slow query",
+			timestamp: "2023-11-14T22:13:20.123Z",
+			fields:    `pid=7`,
+		},
+		{
+			name:      "RFC 3339 time and nested fields",
+			format:    logFormatJSON,
+			line:      `{"severity":"WARNING","message":"retrying","time":"2024-01-02T03:04:05+02:00","req":{"id":"abc"}}`,
+			ok:        true,
+			level:     "warn",
+			message:   "retrying",
+			timestamp: "2024-01-02T01:04:05Z",
+			fields:    `req="{\"id\":\"abc\"}"`,
+		},
+		{
+			name:    "unknown level is kept as a field",
+			format:  logFormatJSON,
+			line:    `{"level":"loud","msg":"hi"}`,
+			ok:      true,
+			message: "hi",
+			fields:  `level=loud`,
+		},
+		{name: "plain text in JSON mode", format: logFormatJSON, line: "Server listening on :3000", ok: false},
+		{name: "JSON with trailing text", format: logFormatJSON, line: `{"msg":"hi"} trailing`, ok: false},
+		{
+			name:      "logfmt",
+			format:    logFormatLogfmt,
+			line:      `time=2024-01-02T03:04:05Z level=info msg="user logged in" user_id=42 path=/login`,
+			ok:        true,
+			level:     "info",
+			message:   "user logged in",
+			timestamp: "2024-01-02T03:04:05Z",
+			fields:    `path=/login user_id=42`,
+		},
+		{name: "logfmt without known keys", format: logFormatLogfmt, line: "a=1 b=2", ok: false},
+		{name: "plain text in logfmt mode", format: logFormatLogfmt, line: "Compiled successfully in 2.1s", ok: false},
+		{name: "text", format: logFormatText, line: `{"msg":"hi"}`, ok: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			parsed, ok := parseLogLine(tc.format, tc.line)
+			if ok != tc.ok {
+				t.Fatalf("expected ok=%v, got %v (%+v)", tc.ok, ok, parsed)
+			}
+			if !ok {
+				return
+			}
+			if parsed.level != tc.level || parsed.message != tc.message || parsed.timestamp != tc.timestamp {
+				t.Errorf("expected %q %q %q, got %q %q %q", tc.level, tc.message, tc.timestamp, parsed.level, parsed.message, parsed.timestamp)
+			}
+			if fields := formatLogFields(parsed.fields); fields != tc.fields {
+				t.Errorf("expected fields %q, got %q", tc.fields, fields)
+			}
+		})
+	}
+}
+
+func TestNormalizeLogLevel(t *testing.T) {
+	t.Parallel()
+
+	cases := map[any]string{
+		"DEBUG":           "debug",
+		"warning":         "warn",
+		"dpanic":          "fatal",
+		"30":              "info",
+		json.Number("10"): "trace",
+		json.Number("60"): "fatal",
+		json.Number("5"):  "",
+		"verbose":         "",
+		true:              "",
+	}
+	for value, expected := range cases {
+		if got := normalizeLogLevel(value); got != expected {
+			t.Errorf("%v: expected %q, got %q", value, expected, got)
+		}
+	}
+}
+
+func TestParseLogfmt(t *testing.T) {
+	t.Parallel()
+
+	values := parseLogfmt(`msg="say \"hi\"" empty= k=v`)
+	if values["msg"] != `say "hi"` || values["empty"] != "" || values["k"] != "v" {
+		t.Errorf("unexpected values: %v", values)
+	}
+	for _, line := range []string{"no pairs here", `msg="unterminated`, "=value"} {
+		if values := parseLogfmt(line); values != nil {
+			t.Errorf("%q: expected nil, got %v", line, values)
+		}
+	}
+}
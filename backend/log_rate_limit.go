@@ -0,0 +1,88 @@
+package backend
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// Log entries a process may emit per second (and in a burst) before lines are suppressed
+	defaultLogRateLimit = 1000
+	// Log entries of a process emitted in a single batch at most
+	maxLogBatchSize = 5000
+	// Minimum time between two "lines suppressed" entries of a process while it keeps flooding
+	logSuppressedReportMs = 1000
+)
+
+// logRateLimiter is a token bucket limiting the log entries of a process, counting the suppressed ones.
+// It is only used with logMu held.
+type logRateLimiter struct {
+	rate       float64
+	tokens     float64
+	lastRefill time.Time
+	// suppressed entries not reported yet, for processID
+	suppressed int
+	processID  string
+	name       string
+	lastReport time.Time
+	// entries queued in the pending batch of the service, numbered batch
+	batch   uint64
+	batched int
+}
+
+// newLogRateLimiter returns a limiter allowing rate entries per second, defaulting to defaultLogRateLimit.
+func newLogRateLimiter(rate *int) *logRateLimiter {
+	limit := defaultLogRateLimit
+	if rate != nil {
+		limit = *rate
+	}
+	return &logRateLimiter{rate: float64(limit), tokens: float64(limit)}
+}
+
+// allow reports whether an entry may be emitted at now, consuming a token if so.
+func (l *logRateLimiter) allow(now time.Time) bool {
+	if !l.lastRefill.IsZero() {
+		l.tokens = min(l.rate, l.tokens+now.Sub(l.lastRefill).Seconds()*l.rate)
+	}
+	l.lastRefill = now
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// batchFull reports whether the entries queued in the batch numbered batch reached maxLogBatchSize.
+func (l *logRateLimiter) batchFull(batch uint64) bool {
+	if l.batch != batch {
+		l.batch = batch
+		l.batched = 0
+	}
+	return l.batched >= maxLogBatchSize
+}
+
+// suppress counts an entry that was not emitted.
+func (l *logRateLimiter) suppress(log ProcessLogData) {
+	l.suppressed++
+	l.processID = log.ProcessID
+	l.name = log.processName
+}
+
+// report returns a "N lines suppressed" entry for the entries suppressed since the last report,
+// or false when there are none or the last report is too recent (unless force is set).
+func (l *logRateLimiter) report(now time.Time, force bool) (ProcessLogData, bool) {
+	if l.suppressed == 0 || (!force && now.Sub(l.lastReport) < logSuppressedReportMs*time.Millisecond) {
+		return ProcessLogData{}, false
+	}
+	entry := ProcessLogData{
+		ProcessID:   l.processID,
+		Type:        "stderr",
+		Output:      fmt.Sprintf("%d lines suppressed\n", l.suppressed),
+		Timestamp:   now.UTC().Format(time.RFC3339Nano),
+		Suppressed:  l.suppressed,
+		processName: l.name,
+	}
+	l.suppressed = 0
+	l.lastReport = now
+	return entry, true
+}
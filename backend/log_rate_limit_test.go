@@ -0,0 +1,55 @@
+package backend
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLogRateLimiter_Allow(t *testing.T) {
+	t.Parallel()
+	rate := 2
+	limiter := newLogRateLimiter(&rate)
+	now := time.Now()
+
+	if !limiter.allow(now) || !limiter.allow(now) {
+		t.Fatal("expected a burst of rate entries to be allowed")
+	}
+	if limiter.allow(now) {
+		t.Error("expected entries over the rate to be refused")
+	}
+	if !limiter.allow(now.Add(500 * time.Millisecond)) {
+		t.Error("expected an entry to be allowed once tokens are refilled")
+	}
+	if limiter.allow(now.Add(500 * time.Millisecond)) {
+		t.Error("expected the refill to be proportional to the elapsed time")
+	}
+
+	if defaults := newLogRateLimiter(nil); defaults.rate != defaultLogRateLimit {
+		t.Errorf("expected a default rate of %d, got %v", defaultLogRateLimit, defaults.rate)
+	}
+}
+
+func TestLogRateLimiter_Report(t *testing.T) {
+	t.Parallel()
+	limiter := newLogRateLimiter(nil)
+	now := time.Now()
+
+	if _, ok := limiter.report(now, true); ok {
+		t.Error("expected no report without suppressed entries")
+	}
+
+	limiter.suppress(ProcessLogData{ProcessID: "id", processName: "api"})
+	limiter.suppress(ProcessLogData{ProcessID: "id", processName: "api"})
+	entry, ok := limiter.report(now, false)
+	if !ok || entry.ProcessID != "id" || entry.Suppressed != 2 || entry.Output != "2 lines suppressed\n" || entry.processName != "api" {
+		t.Errorf("unexpected report: %+v", entry)
+	}
+
+	limiter.suppress(ProcessLogData{ProcessID: "id"})
+	if _, ok := limiter.report(now.Add(time.Millisecond), false); ok {
+		t.Error("expected reports to be throttled")
+	}
+	if entry, ok := limiter.report(now.Add(time.Millisecond), true); !ok || entry.Suppressed != 1 {
+		t.Errorf("expected a forced report, got %+v", entry)
+	}
+}
@@ -0,0 +1,128 @@
+package backend
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMultilineTimeoutMs = 200
+	// Lines grouped into a single entry at most, so that a runaway trace cannot grow forever
+	maxMultilineLines = 1000
+)
+
+// multilineRule decides which lines continue the previous log entry, for multiline config.
+// Without a start pattern, indented lines (and Java's "Caused by:") are continuations.
+type multilineRule struct {
+	startPattern *regexp.Regexp
+	timeout      time.Duration
+}
+
+// newMultilineRule compiles a rule from config. Returns nil when no multiline config is set.
+func newMultilineRule(cfg *MultilineConfig) (*multilineRule, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	rule := &multilineRule{timeout: defaultMultilineTimeoutMs * time.Millisecond}
+	if cfg.StartPattern != nil {
+		pattern, err := regexp.Compile(*cfg.StartPattern)
+		if err != nil {
+			return nil, fmt.Errorf("compiling multiline.start_pattern: %w", err)
+		}
+		rule.startPattern = pattern
+	}
+	if cfg.TimeoutMs != nil {
+		rule.timeout = time.Duration(*cfg.TimeoutMs) * time.Millisecond
+	}
+	return rule, nil
+}
+
+// isContinuation reports whether a line belongs to the entry before it.
+func (r *multilineRule) isContinuation(line string) bool {
+	if r.startPattern != nil {
+		return !r.startPattern.MatchString(line)
+	}
+	return strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") || strings.HasPrefix(line, "Caused by:")
+}
+
+// logLineGrouper coalesces the continuation lines of a stream into the entry they follow.
+// An entry is emitted once the next one starts, or once no line has arrived for the rule timeout.
+type logLineGrouper struct {
+	rule  *multilineRule
+	emit  func(ProcessLogData)
+	mu    sync.Mutex
+	entry *ProcessLogData
+	lines int
+	timer *time.Timer
+	// idleAt is when the pending entry times out, as the timer may fire just before being reset
+	idleAt time.Time
+}
+
+// newGrouper returns a grouper emitting to emit. A nil rule emits every line as it comes.
+func (r *multilineRule) newGrouper(emit func(ProcessLogData)) *logLineGrouper {
+	return &logLineGrouper{rule: r, emit: emit}
+}
+
+// add groups a log entry holding a single line. Structured entries always start a new entry.
+func (g *logLineGrouper) add(line string, entry ProcessLogData) {
+	if g.rule == nil {
+		g.emit(entry)
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	structured := entry.Level != "" || entry.Fields != nil
+	if g.entry != nil && !structured && g.lines < maxMultilineLines && g.rule.isContinuation(line) {
+		g.entry.Output += entry.Output
+		g.lines++
+		g.resetTimerLocked()
+		return
+	}
+
+	g.flushLocked()
+	g.entry = &entry
+	g.lines = 1
+	g.resetTimerLocked()
+}
+
+func (g *logLineGrouper) resetTimerLocked() {
+	g.idleAt = time.Now().Add(g.rule.timeout)
+	if g.timer == nil {
+		g.timer = time.AfterFunc(g.rule.timeout, g.flushIfIdle)
+		return
+	}
+	g.timer.Reset(g.rule.timeout)
+}
+
+func (g *logLineGrouper) flushIfIdle() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if time.Now().Before(g.idleAt) {
+		return
+	}
+	g.flushLocked()
+}
+
+// flush emits the pending entry, if any. Called once the stream ends.
+func (g *logLineGrouper) flush() {
+	if g.rule == nil {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.flushLocked()
+}
+
+func (g *logLineGrouper) flushLocked() {
+	if g.entry == nil {
+		return
+	}
+	g.timer.Stop()
+	g.emit(*g.entry)
+	g.entry = nil
+	g.lines = 0
+}
@@ -0,0 +1,140 @@
+package backend
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// groupLines feeds lines to a grouper and returns the emitted outputs once the stream ends.
+func groupLines(t *testing.T, cfg *MultilineConfig, lines []string) []string {
+	t.Helper()
+	rule, err := newMultilineRule(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var outputs []string
+	grouper := rule.newGrouper(func(entry ProcessLogData) { outputs = append(outputs, entry.Output) })
+	for _, line := range lines {
+		grouper.add(line, ProcessLogData{Output: line + "\n"})
+	}
+	grouper.flush()
+	return outputs
+}
+
+func TestLogLineGrouper(t *testing.T) {
+	t.Parallel()
+
+	javaTrace := []string{
+		"Exception in thread \"main\" java.lang.IllegalStateException: boom",
+		"\tat com.example.App.run(App.java:42)",
+		"\tat com.example.App.main(App.java:10)",
+		"Caused by: java.io.IOException: disk full",
+		"\t... 2 more",
+		"Server stopped",
+	}
+	timestamped := []string{
+		"2024-01-02 10:00:00 ERROR request failed",
+		"Traceback (most recent call last):",
+		"ValueError: boom",
+		"2024-01-02 10:00:01 INFO retrying",
+	}
+	startPattern := `^\d{4}-`
+
+	cases := []struct {
+		name     string
+		cfg      *MultilineConfig
+		lines    []string
+		expected []string
+	}{
+		{
+			name:     "disabled",
+			cfg:      nil,
+			lines:    javaTrace[:2],
+			expected: []string{javaTrace[0] + "\n", javaTrace[1] + "\n"},
+		},
+		{
+			name:     "indentation",
+			cfg:      &MultilineConfig{},
+			lines:    javaTrace,
+			expected: []string{strings.Join(javaTrace[:5], "\n") + "\n", "Server stopped\n"},
+		},
+		{
+			name:     "start pattern",
+			cfg:      &MultilineConfig{StartPattern: &startPattern},
+			lines:    timestamped,
+			expected: []string{strings.Join(timestamped[:3], "\n") + "\n", timestamped[3] + "\n"},
+		},
+		{
+			name:     "leading continuation lines",
+			cfg:      &MultilineConfig{StartPattern: &startPattern},
+			lines:    []string{"orphan", "2024-01-02 start"},
+			expected: []string{"orphan\n", "2024-01-02 start\n"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			outputs := groupLines(t, tc.cfg, tc.lines)
+			if strings.Join(outputs, "|") != strings.Join(tc.expected, "|") {
+				t.Errorf("expected %q, got %q", tc.expected, outputs)
+			}
+		})
+	}
+}
+
+func TestLogLineGrouper_StructuredLinesStartEntries(t *testing.T) {
+	t.Parallel()
+	rule, _ := newMultilineRule(&MultilineConfig{})
+	var outputs []string
+	grouper := rule.newGrouper(func(entry ProcessLogData) { outputs = append(outputs, entry.Output) })
+
+	grouper.add("first", ProcessLogData{Output: "first\n"})
+	grouper.add(`  {"msg":"indented json"}`, ProcessLogData{Output: "indented json\n", Level: "info"})
+	grouper.flush()
+
+	if len(outputs) != 2 {
+		t.Errorf("expected structured lines to never be continuations, got %q", outputs)
+	}
+}
+
+func TestLogLineGrouper_FlushesAfterTimeout(t *testing.T) {
+	t.Parallel()
+	timeout := 50
+	rule, _ := newMultilineRule(&MultilineConfig{TimeoutMs: &timeout})
+	var mu sync.Mutex
+	var outputs []string
+	grouper := rule.newGrouper(func(entry ProcessLogData) {
+		mu.Lock()
+		defer mu.Unlock()
+		outputs = append(outputs, entry.Output)
+	})
+
+	grouper.add("Traceback", ProcessLogData{Output: "Traceback\n"})
+	grouper.add("  File", ProcessLogData{Output: "  File\n"})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		count := len(outputs)
+		mu.Unlock()
+		if count > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(outputs) != 1 || outputs[0] != "Traceback\n  File\n" {
+		t.Errorf("expected the pending entry to be flushed after the timeout, got %q", outputs)
+	}
+}
+
+func TestNewMultilineRule_InvalidPattern(t *testing.T) {
+	t.Parallel()
+	pattern := "("
+	if _, err := newMultilineRule(&MultilineConfig{StartPattern: &pattern}); err == nil {
+		t.Error("expected an error for an invalid start pattern")
+	}
+}
@@ -1,25 +1,40 @@
 package backend
 
 import (
-	"bufio"
 	"fmt"
 	"io"
+	"maps"
+	"math"
 	"os"
 	"os/exec"
+	"regexp"
 	"sync"
 	"syscall"
 	"time"
 
+	"github.com/creack/pty"
 	"github.com/google/uuid"
 	"github.com/wailsapp/wails/v3/pkg/application"
 )
 
 const (
+	// Default time given to a process to exit after the stop signal, before it is killed
 	processKillTimeoutMs = 10_000
 	defaultMaxRetries    = 3
 	defaultDelayMs       = 1000
 	defaultResetAfterMs  = 30000
 	logBatchIntervalMs   = 100
+	// Terminal size used until the log drawer reports its own
+	defaultTTYCols = 120
+	defaultTTYRows = 30
+)
+
+// Process statuses reported by listProcesses.
+const (
+	processStatusStopped    = "stopped"
+	processStatusStarting   = "starting"
+	processStatusRunning    = "running"
+	processStatusRestarting = "restarting"
 )
 
 // eventEmitter abstracts Wails event emission for testing.
@@ -34,19 +49,52 @@ func (w *wailsEmitter) Emit(name string, data ...any) {
 	application.Get().Event.Emit(name, data...)
 }
 
+// launchConfig holds everything needed to (re)spawn a process.
+type launchConfig struct {
+	name         string
+	cwd          string
+	command      string
+	shell        string
+	tty          bool
+	ttySize      pty.Winsize
+	stdin        bool
+	customEnv    map[string]string
+	restartCfg   *RestartConfig
+	stopCfg      *StopConfig
+	logsCfg      *LogsConfig
+	logFile      *rotatingLogFile // opened by startProcess from logsCfg
+	logFormat    string
+	logRateLimit *int
+	logLimiter   *logRateLimiter // created by startProcess from logRateLimit
+	multilineCfg *MultilineConfig
+	alertCfgs    []AlertConfig
+	readyCfg     *ReadyConfig
+	healthCfg    *HealthConfig
+}
+
 // processState holds the runtime state of a managed process.
 type processState struct {
 	cmd           *exec.Cmd
+	pty           *os.File       // master side of the terminal, for tty processes
+	stdin         io.WriteCloser // stdin pipe, for stdin processes without a terminal
 	pid           int
-	cwd           string
-	command       string
-	customEnv     map[string]string
-	restartCfg    *RestartConfig
+	launch        launchConfig
 	retryCount    int
 	lastStartTime time.Time
 	manualStop    bool
 	exited        bool
 	restartTimer  *time.Timer
+	done          chan struct{}
+	// ready is set once readiness checks pass (immediately when none are configured).
+	// readySettled is closed once the outcome is known: ready, timed out, or exited.
+	ready        bool
+	readySettled chan struct{}
+	// unhealthy is set when the healthcheck killed the process, so the exit is reported as such.
+	unhealthy bool
+	// stopping is set once the stop sequence has begun, so that it only runs once.
+	// stopped is closed once the stop sequence is over (after the stop command, if any).
+	stopping bool
+	stopped  chan struct{}
 }
 
 // ProcessService manages child processes: spawning, stopping, restarting, and log streaming.
@@ -54,10 +102,30 @@ type ProcessService struct {
 	mu        sync.RWMutex
 	processes map[string]*processState
 
+	// startMu serializes the spawning of named processes so shared dependencies are only spawned once.
+	// It is never held while waiting for a process to become ready or to stop.
+	startMu sync.Mutex
+
 	logMu       sync.Mutex
 	pendingLogs []ProcessLogData
-	batchTicker *time.Ticker
-	batchDone   chan struct{}
+	// pendingBatch numbers the pending batch, for the per-process batch size of rate limiters
+	pendingBatch uint64
+	batchTicker  *time.Ticker
+	batchDone    chan struct{}
+	logSubs      map[int]chan []ProcessLogData
+	nextSubID    int
+	// logBuffers keep the recent logs of each process by ID, for clients catching up. Named processes
+	// keep the logs of their latest run once exited; unnamed ones are dropped when they exit.
+	logBuffers    map[string]*logRingBuffer
+	lastLogCursor int64
+	// suppressing holds the rate limiters with suppressed lines to report
+	suppressing map[*logRateLimiter]struct{}
+
+	// logFiles are shared by every process writing to the same path, and stay open
+	logFilesMu sync.Mutex
+	logFiles   map[string]*rotatingLogFile
+	// outputSink (if any) receives the complete output of named processes, including rate-limited lines
+	outputSink func(name string, output string)
 
 	emitter eventEmitter
 }
@@ -70,6 +138,16 @@ func NewProcessService() *ProcessService {
 	}
 }
 
+// newHeadlessProcessService creates a ProcessService that reports events to a custom emitter
+// instead of the Wails runtime, and the raw output of processes to outputSink (if any). Used by the CLI.
+func newHeadlessProcessService(emitter eventEmitter, outputSink func(name string, output string)) *ProcessService {
+	return &ProcessService{
+		processes:  make(map[string]*processState),
+		emitter:    emitter,
+		outputSink: outputSink,
+	}
+}
+
 // --- Log batching ---
 
 // startBatchTicker starts the log batching goroutine if not already running.
@@ -111,75 +189,193 @@ func (s *ProcessService) stopBatchTicker() {
 	s.batchDone = nil
 }
 
-// flushLogs emits all pending logs as a single batch event.
+// flushLogs emits all pending logs as a single batch event, reporting the lines suppressed since the last one.
 func (s *ProcessService) flushLogs() {
 	s.logMu.Lock()
+	now := time.Now()
+	for limiter := range s.suppressing {
+		s.reportSuppressedLocked(limiter, now, false)
+	}
 	if len(s.pendingLogs) == 0 {
 		s.logMu.Unlock()
 		return
 	}
 	batch := s.pendingLogs
 	s.pendingLogs = nil
+	s.pendingBatch++
+	for _, sub := range s.logSubs {
+		// Never block the batcher on a slow subscriber
+		select {
+		case sub <- batch:
+		default:
+		}
+	}
 	s.logMu.Unlock()
 
 	s.emitter.Emit("process-log:batch", batch)
 }
 
-// queueLog adds a log entry to the pending batch.
-func (s *ProcessService) queueLog(log ProcessLogData) {
+// subscribeLogs returns a channel receiving every flushed log batch, and a function to unsubscribe.
+// Batches are dropped for subscribers that fall behind.
+func (s *ProcessService) subscribeLogs() (<-chan []ProcessLogData, func()) {
+	s.logMu.Lock()
+	defer s.logMu.Unlock()
+	if s.logSubs == nil {
+		s.logSubs = make(map[int]chan []ProcessLogData)
+	}
+	id := s.nextSubID
+	s.nextSubID++
+	ch := make(chan []ProcessLogData, 64)
+	s.logSubs[id] = ch
+	return ch, func() {
+		s.logMu.Lock()
+		defer s.logMu.Unlock()
+		delete(s.logSubs, id)
+	}
+}
+
+// queueLog adds a log entry to the pending batch and the process buffer, and appends it to logFile (if any).
+// Entries over the rate of limiter (if any) or its share of the batch are suppressed, but still written to logFile
+// and outputSink.
+// Returns false when the entry was suppressed.
+func (s *ProcessService) queueLog(log ProcessLogData, logFile *rotatingLogFile, limiter *logRateLimiter) bool {
+	// Previews are followed by their complete line, which is the one persisted
+	if !log.Partial {
+		_ = logFile.write(log)
+		if s.outputSink != nil && log.processName != "" && log.Output != "" {
+			s.outputSink(log.processName, log.Output)
+		}
+	}
 	s.logMu.Lock()
+	defer s.logMu.Unlock()
+	now := time.Now()
+	if log.Type == "exit" {
+		// Lines suppressed during a run are reported before its exit
+		s.reportSuppressedLocked(limiter, now, true)
+	} else if limiter != nil {
+		if limiter.batchFull(s.pendingBatch) || !limiter.allow(now) {
+			limiter.suppress(log)
+			if s.suppressing == nil {
+				s.suppressing = make(map[*logRateLimiter]struct{})
+			}
+			s.suppressing[limiter] = struct{}{}
+			return false
+		}
+		limiter.batched++
+	}
+	s.bufferLog(&log)
 	s.pendingLogs = append(s.pendingLogs, log)
-	s.logMu.Unlock()
+	return true
+}
+
+// reportSuppressedLocked queues a "N lines suppressed" entry for limiter, if it has any to report.
+// Must be called with logMu held.
+func (s *ProcessService) reportSuppressedLocked(limiter *logRateLimiter, now time.Time, force bool) {
+	if limiter == nil {
+		return
+	}
+	if entry, ok := limiter.report(now, force); ok {
+		s.bufferLog(&entry)
+		s.pendingLogs = append(s.pendingLogs, entry)
+	}
+	if limiter.suppressed == 0 {
+		delete(s.suppressing, limiter)
+	}
+}
+
+// bufferLog assigns the next cursor to a log entry and stores it in its process buffer.
+// Must be called with logMu held.
+func (s *ProcessService) bufferLog(log *ProcessLogData) {
+	s.lastLogCursor++
+	log.Cursor = s.lastLogCursor
+	if s.logBuffers == nil {
+		s.logBuffers = make(map[string]*logRingBuffer)
+	}
+	buffer, exists := s.logBuffers[log.ProcessID]
+	if !exists {
+		buffer = newLogRingBuffer(log.processName, logBufferCapacity)
+		s.logBuffers[log.ProcessID] = buffer
+	}
+	buffer.push(*log)
 }
 
 // --- Process spawning ---
 
 // spawnProcess creates and starts a child process, wiring up stdout/stderr capture and exit handling.
-func (s *ProcessService) spawnProcess(
-	processID string,
-	cwd string,
-	command string,
-	restartCfg *RestartConfig,
-	retryCount int,
-	customEnv map[string]string,
-) error {
-	cmd := exec.Command("sh", "-c", command) //nolint:gosec // user-configured command
-	cmd.Dir = cwd
-
-	// Build environment: system env + color vars + custom env
-	env := os.Environ()
-	env = append(env, "FORCE_COLOR=1", "TERM=xterm-256color", "COLORTERM=truecolor")
-	for k, v := range customEnv {
-		env = append(env, fmt.Sprintf("%s=%s", k, v))
+func (s *ProcessService) spawnProcess(processID string, launch launchConfig, retryCount int) error {
+	cmd, err := newProcessCommand(launch.command, launch.shell)
+	if err != nil {
+		return err
 	}
-	cmd.Env = env
-
-	// Create new process group for clean shutdown
-	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Dir = launch.cwd
+	cmd.Env = buildProcessEnv(launch.customEnv)
 
-	stdout, err := cmd.StdoutPipe()
+	probe, err := newReadinessProbe(launch.readyCfg, launch.cwd, cmd.Env)
 	if err != nil {
-		return fmt.Errorf("creating stdout pipe: %w", err)
+		return err
 	}
-	stderr, err := cmd.StderrPipe()
+	multiline, err := newMultilineRule(launch.multilineCfg)
 	if err != nil {
-		return fmt.Errorf("creating stderr pipe: %w", err)
+		return err
 	}
+	alerts, err := newAlertRules(launch.alertCfgs)
+	if err != nil {
+		return err
+	}
+
+	// Each output stream is read by its own goroutine, keyed by log type
+	streams := make(map[string]io.Reader, 2)
+	var ptmx *os.File
+	var stdin io.WriteCloser
+	if launch.tty {
+		// Setsid also creates a new process group, and conflicts with Setpgid
+		ptmx, err = pty.StartWithAttrs(cmd, ttyWinsize(launch.ttySize), &syscall.SysProcAttr{Setsid: true, Setctty: true})
+		if err != nil {
+			return fmt.Errorf("starting command in a terminal: %w", err)
+		}
+		streams["stdout"] = ptmx
+	} else {
+		// Create new process group for clean shutdown
+		cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return fmt.Errorf("creating stdout pipe: %w", err)
+		}
+		stderr, err := cmd.StderrPipe()
+		if err != nil {
+			return fmt.Errorf("creating stderr pipe: %w", err)
+		}
+		// Without a pipe, stdin is /dev/null
+		if launch.stdin {
+			stdin, err = cmd.StdinPipe()
+			if err != nil {
+				return fmt.Errorf("creating stdin pipe: %w", err)
+			}
+		}
 
-	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("starting command: %w", err)
+		if err := cmd.Start(); err != nil {
+			return fmt.Errorf("starting command: %w", err)
+		}
+		streams["stdout"] = stdout
+		streams["stderr"] = stderr
 	}
 
 	state := &processState{
 		cmd:           cmd,
+		pty:           ptmx,
+		stdin:         stdin,
 		pid:           cmd.Process.Pid,
-		cwd:           cwd,
-		command:       command,
-		customEnv:     customEnv,
-		restartCfg:    restartCfg,
+		launch:        launch,
 		retryCount:    retryCount,
 		lastStartTime: time.Now(),
 		manualStop:    false,
+		done:          make(chan struct{}),
+		ready:         probe == nil,
+		readySettled:  make(chan struct{}),
+	}
+	if probe == nil {
+		close(state.readySettled)
 	}
 
 	s.mu.Lock()
@@ -190,29 +386,247 @@ func (s *ProcessService) spawnProcess(
 
 	// Stream goroutines must finish reading before cmd.Wait() closes the pipes.
 	var streamWg sync.WaitGroup
-	streamWg.Add(2)
-	go func() { defer streamWg.Done(); s.streamOutput(processID, stdout, "stdout") }()
-	go func() { defer streamWg.Done(); s.streamOutput(processID, stderr, "stderr") }()
+	streamWg.Add(len(streams))
+	for logType, stream := range streams {
+		go func() {
+			defer streamWg.Done()
+			s.streamOutput(processID, launch, stream, logType, probe, multiline, alerts)
+		}()
+	}
 	go s.waitForExit(processID, cmd, &streamWg)
+	if ptmx != nil {
+		// Unlike pipes, the terminal is not closed by cmd.Wait()
+		go func() {
+			streamWg.Wait()
+			s.mu.Lock()
+			state.pty = nil
+			s.mu.Unlock()
+			_ = ptmx.Close()
+		}()
+	}
+	if probe != nil {
+		go s.watchReadiness(processID, state, probe)
+	}
+	if health := newHealthChecker(launch.healthCfg, launch.cwd, cmd.Env); health != nil {
+		go s.watchHealth(processID, state, health)
+	}
 
 	return nil
 }
 
-// streamOutput reads lines from a pipe and queues them as log entries.
-// The pipe is closed by cmd.Wait(), so the scanner loop terminates naturally on process exit.
-func (s *ProcessService) streamOutput(processID string, pipe io.Reader, logType string) {
-	scanner := bufio.NewScanner(pipe)
-	scanner.Buffer(make([]byte, 0, 1024*1024), 1024*1024) // 1MB buffer for long lines
-	for scanner.Scan() {
-		s.queueLog(ProcessLogData{
-			ProcessID: processID,
-			Type:      logType,
-			Output:    scanner.Text() + "\n",
-			Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
-		})
+// newProcessCommand runs command through the given shell (sh when empty),
+// or splits it into words and executes it directly when shell is none.
+func newProcessCommand(command string, shell string) (*exec.Cmd, error) {
+	switch shell {
+	case "", shellSh, "bash", "zsh":
+		if shell == "" {
+			shell = shellSh
+		}
+		return exec.Command(shell, "-c", command), nil //nolint:gosec // user-configured command
+	case shellNone:
+		words, err := splitShellWords(command)
+		if err != nil {
+			return nil, fmt.Errorf("parsing command: %w", err)
+		}
+		if len(words) == 0 {
+			return nil, fmt.Errorf("parsing command: empty command")
+		}
+		return exec.Command(words[0], words[1:]...), nil //nolint:gosec // user-configured command
+	default:
+		return nil, fmt.Errorf("unsupported shell: %s", shell)
 	}
 }
 
+// ttyWinsize returns the terminal size to start a tty process with.
+func ttyWinsize(size pty.Winsize) *pty.Winsize {
+	if size.Cols == 0 || size.Rows == 0 {
+		return &pty.Winsize{Cols: defaultTTYCols, Rows: defaultTTYRows}
+	}
+	return &size
+}
+
+// buildProcessEnv returns the system env + color vars + custom env.
+func buildProcessEnv(customEnv map[string]string) []string {
+	env := os.Environ()
+	env = append(env, "FORCE_COLOR=1", "TERM=xterm-256color", "COLORTERM=truecolor")
+	for k, v := range customEnv {
+		env = append(env, fmt.Sprintf("%s=%s", k, v))
+	}
+	return env
+}
+
+// streamOutput reads lines from a pipe (or terminal) and queues them as log entries,
+// grouping continuation lines when a multiline rule is set, and matching them against alerts.
+// The pipe is closed by cmd.Wait(), so the read loop terminates naturally on process exit.
+// A terminal fails with EIO once every process using it has exited, which also ends the loop.
+func (s *ProcessService) streamOutput(processID string, launch launchConfig, pipe io.Reader, logType string, probe *readinessProbe, multiline *multilineRule, alerts []*alertRule) {
+	emit := func(entry ProcessLogData) bool {
+		queued := s.queueLog(entry, launch.logFile, launch.logLimiter)
+		if !entry.Partial {
+			s.matchAlerts(processID, launch.name, alerts, entry)
+		}
+		return queued
+	}
+	grouper := multiline.newGrouper(func(entry ProcessLogData) { emit(entry) })
+	// Whether the current line of this stream has a preview displayed, for its replace entries
+	previewShown := false
+	defer grouper.flush()
+	readOutputLines(pipe, partialLineFlushMs*time.Millisecond, func(line outputLine) {
+		probe.observeLine(line.text)
+		entry := ProcessLogData{
+			ProcessID:   processID,
+			Type:        logType,
+			Output:      line.text + "\n",
+			Timestamp:   time.Now().UTC().Format(time.RFC3339Nano),
+			Partial:     line.partial,
+			Replace:     line.replace,
+			processName: launch.name,
+		}
+		if parsed, ok := parseLogLine(launch.logFormat, line.text); ok {
+			parsed.apply(&entry)
+		}
+		if line.partial || line.replace {
+			// Entries replacing each other are never grouped, so the pending group goes first
+			grouper.flush()
+			// A replace entry whose preview was suppressed has nothing to replace, so it is a new line
+			entry.Replace = entry.Replace && previewShown
+			queued := emit(entry)
+			previewShown = line.partial && (queued || entry.Replace)
+			return
+		}
+		grouper.add(line.text, entry)
+	})
+}
+
+// --- Readiness ---
+
+// watchReadiness waits for the probe to pass, then flags the process as ready and emits process-ready.
+// Emits process-ready-timeout if the checks never pass while the process is still running.
+func (s *ProcessService) watchReadiness(processID string, state *processState, probe *readinessProbe) {
+	ready := probe.waitUntilReady(state.done)
+
+	s.mu.Lock()
+	current, exists := s.processes[processID]
+	isCurrent := exists && current == state && !state.exited
+	if ready && isCurrent {
+		state.ready = true
+	}
+	close(state.readySettled)
+	s.mu.Unlock()
+
+	if !isCurrent {
+		return
+	}
+	event := "process-ready"
+	if !ready {
+		event = "process-ready-timeout"
+	}
+	s.emitter.Emit(event, ProcessReadyData{
+		ProcessID: processID,
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+	})
+}
+
+// waitForReady blocks until the process's readiness outcome is known. Returns true if it is ready.
+// Restart-pending placeholders are not considered ready.
+func (s *ProcessService) waitForReady(id string) bool {
+	s.mu.RLock()
+	state, exists := s.processes[id]
+	s.mu.RUnlock()
+	if !exists || state.readySettled == nil {
+		return false
+	}
+	<-state.readySettled
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return state.ready && !state.exited
+}
+
+// --- Liveness ---
+
+// watchHealth runs the healthcheck once the process is ready. After too many consecutive failures,
+// it emits process-unhealthy and terminates the process group; waitForExit then applies the restart policy.
+func (s *ProcessService) watchHealth(processID string, state *processState, health *healthChecker) {
+	select {
+	case <-state.readySettled:
+	case <-state.done:
+		return
+	}
+
+	failures := health.waitUntilUnhealthy(state.done)
+	if failures == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	current, exists := s.processes[processID]
+	if !exists || current != state || state.exited || state.manualStop {
+		s.mu.Unlock()
+		return
+	}
+	state.unhealthy = true
+	s.mu.Unlock()
+
+	s.emitter.Emit("process-unhealthy", ProcessUnhealthyData{
+		ProcessID: processID,
+		Failures:  failures,
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+	})
+	s.terminateProcessGroup(processID, state)
+}
+
+// terminateProcessGroup stops a process according to its stop policy: the stop command (if any),
+// then the stop signal to the process group, then SIGKILL if it has not exited in time.
+func (s *ProcessService) terminateProcessGroup(processID string, state *processState) {
+	s.mu.Lock()
+	if state.stopping {
+		s.mu.Unlock()
+		return
+	}
+	state.stopping = true
+	stopped := make(chan struct{})
+	state.stopped = stopped
+	s.mu.Unlock()
+
+	policy := newStopPolicy(state.launch.stopCfg)
+	pid := state.pid
+
+	// Capture the state (not the ID) so a restarted process under the same ID is never signaled
+	signalIfRunning := func(sig syscall.Signal) {
+		s.mu.RLock()
+		exited := state.exited
+		s.mu.RUnlock()
+		if !exited {
+			_ = syscall.Kill(-pid, sig)
+		}
+	}
+	time.AfterFunc(policy.timeout, func() { signalIfRunning(syscall.SIGKILL) })
+
+	if policy.command == "" {
+		signalIfRunning(policy.signal)
+		close(stopped)
+		return
+	}
+	go func() {
+		defer close(stopped)
+		lines, err := policy.runCommand(state.launch.cwd, state.cmd.Env)
+		if err != nil {
+			lines = append(lines, fmt.Sprintf("stop command failed: %s", err))
+		}
+		for _, line := range lines {
+			s.queueLog(ProcessLogData{
+				ProcessID:   processID,
+				Type:        "stdout",
+				Output:      line + "\n",
+				Timestamp:   time.Now().UTC().Format(time.RFC3339Nano),
+				processName: state.launch.name,
+			}, state.launch.logFile, state.launch.logLimiter)
+		}
+		signalIfRunning(policy.signal)
+	}()
+}
+
 // --- Exit handling and restart ---
 
 // waitForExit waits for the process to exit and handles restart logic.
@@ -236,33 +650,37 @@ func (s *ProcessService) waitForExit(processID string, cmd *exec.Cmd, streamWg *
 	state.exited = true
 
 	manualStop := state.manualStop
-	restartCfg := state.restartCfg
+	unhealthy := state.unhealthy
+	launch := state.launch
+	restartCfg := launch.restartCfg
 	lastStartTime := state.lastStartTime
 	retryCount := state.retryCount
-	cwd := state.cwd
-	command := state.command
-	customEnv := state.customEnv
 
 	delete(s.processes, processID)
 	s.mu.Unlock()
+	close(state.done)
 
 	s.queueLog(ProcessLogData{
-		ProcessID: processID,
-		Type:      "exit",
-		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
-		Code:      exitCode,
-		Signal:    signal,
-	})
+		ProcessID:   processID,
+		Type:        "exit",
+		Timestamp:   time.Now().UTC().Format(time.RFC3339Nano),
+		Code:        exitCode,
+		Signal:      signal,
+		processName: launch.name,
+	}, launch.logFile, launch.logLimiter)
 	s.flushLogs()
 
-	isCleanExit := exitCode != nil && *exitCode == 0
+	// A process killed for failing its healthcheck never counts as a clean exit, whatever its exit code
+	isCleanExit := exitCode != nil && *exitCode == 0 && !unhealthy
 	if manualStop || isCleanExit || restartCfg == nil || !restartCfg.Enabled {
+		s.dropExitedLogBuffer(processID, launch.name)
 		if !manualStop && !isCleanExit {
 			s.emitter.Emit("process-crash", ProcessCrashData{
 				ProcessID:   processID,
 				ExitCode:    exitCode,
 				Signal:      signal,
 				WillRestart: false,
+				Unhealthy:   unhealthy,
 				Timestamp:   time.Now().UTC().Format(time.RFC3339Nano),
 			})
 		}
@@ -289,11 +707,13 @@ func (s *ProcessService) waitForExit(processID string, cmd *exec.Cmd, streamWg *
 	}
 
 	if effectiveRetryCount >= maxRetries {
+		s.dropExitedLogBuffer(processID, launch.name)
 		s.emitter.Emit("process-crash", ProcessCrashData{
 			ProcessID:   processID,
 			ExitCode:    exitCode,
 			Signal:      signal,
 			WillRestart: false,
+			Unhealthy:   unhealthy,
 			Timestamp:   time.Now().UTC().Format(time.RFC3339Nano),
 		})
 		return
@@ -304,6 +724,7 @@ func (s *ProcessService) waitForExit(processID string, cmd *exec.Cmd, streamWg *
 		ExitCode:    exitCode,
 		Signal:      signal,
 		WillRestart: true,
+		Unhealthy:   unhealthy,
 		Timestamp:   time.Now().UTC().Format(time.RFC3339Nano),
 	})
 
@@ -315,7 +736,8 @@ func (s *ProcessService) waitForExit(processID string, cmd *exec.Cmd, streamWg *
 			MaxRetries: maxRetries,
 			Timestamp:  time.Now().UTC().Format(time.RFC3339Nano),
 		})
-		if err := s.spawnProcess(processID, cwd, command, restartCfg, newRetryCount, customEnv); err != nil {
+		if err := s.spawnProcess(processID, launch, newRetryCount); err != nil {
+			s.dropExitedLogBuffer(processID, launch.name)
 			s.emitter.Emit("process-crash", ProcessCrashData{
 				ProcessID:   processID,
 				WillRestart: false,
@@ -327,10 +749,7 @@ func (s *ProcessService) waitForExit(processID string, cmd *exec.Cmd, streamWg *
 	// Store placeholder state for the pending restart timer
 	s.mu.Lock()
 	s.processes[processID] = &processState{
-		cwd:          cwd,
-		command:      command,
-		customEnv:    customEnv,
-		restartCfg:   restartCfg,
+		launch:       launch,
 		retryCount:   newRetryCount,
 		manualStop:   false,
 		restartTimer: restartTimer,
@@ -355,10 +774,11 @@ func extractExitInfo(cmd *exec.Cmd) (*int, *string) {
 	return &code, nil
 }
 
-// --- Exported methods (Wails bindings) ---
+// --- Start helpers ---
 
-// Start spawns a new process and returns its ID.
-func (s *ProcessService) Start(cwd string, command string, restartConfig *RestartConfig, env map[string]string, envFile string) ProcessStartResult {
+// startProcess resolves the env file, merges env, interpolates the command, and spawns the process under a new ID.
+func (s *ProcessService) startProcess(launch launchConfig, env map[string]string, envFiles []string) ProcessStartResult {
+	cwd := launch.cwd
 	if _, err := os.Stat(cwd); os.IsNotExist(err) {
 		return ProcessStartResult{
 			Success: false,
@@ -366,39 +786,360 @@ func (s *ProcessService) Start(cwd string, command string, restartConfig *Restar
 		}
 	}
 
-	// Load env file vars (if specified) and merge with explicit env
-	mergedEnv := make(map[string]string)
-	if envFile != "" {
-		resolvedPath := resolveEnvFilePath(envFile, cwd)
-		fileEnv, err := parseEnvFile(resolvedPath)
-		if err != nil {
-			return ProcessStartResult{
-				Success: false,
-				Error:   fmt.Sprintf("Failed to load env file %q: %s", envFile, err.Error()),
-			}
+	// Load env file vars (if specified) and merge with explicit env, which overrides them
+	fileEnv, err := loadEnvFiles(envFiles, cwd)
+	if err != nil {
+		return ProcessStartResult{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to load env file %s", err.Error()),
 		}
-		for k, v := range fileEnv {
-			mergedEnv[k] = v
+	}
+	launch.customEnv = mergeProcessEnv(env, fileEnv)
+	launch.command, err = resolveCommand(launch.command, launch.shell, launch.customEnv)
+	if err != nil {
+		return ProcessStartResult{
+			Success: false,
+			Error:   err.Error(),
 		}
 	}
-	// Explicit env overrides file env
-	for k, v := range env {
-		mergedEnv[k] = v
+	return s.launchProcess(launch)
+}
+
+// launchProcess opens the log file of a resolved launch config and spawns the process under a new ID.
+func (s *ProcessService) launchProcess(launch launchConfig) ProcessStartResult {
+	logFile, err := s.openLogFile(launch.logsCfg)
+	if err != nil {
+		return ProcessStartResult{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to open log file: %s", err.Error()),
+		}
 	}
 
+	launch.logFile = logFile
+	launch.logLimiter = newLogRateLimiter(launch.logRateLimit)
 	processID := uuid.New().String()
-	if err := s.spawnProcess(processID, cwd, command, restartConfig, 0, mergedEnv); err != nil {
+	s.dropLogBuffers(launch.name)
+	if err := s.spawnProcess(processID, launch, 0); err != nil {
 		return ProcessStartResult{
 			Success: false,
 			Error:   err.Error(),
 		}
 	}
+	if launch.name != "" {
+		s.emitter.Emit("process-started", ProcessStartedData{
+			ProcessID: processID,
+			Name:      launch.name,
+			Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+		})
+	}
 	return ProcessStartResult{
 		Success:   true,
 		ProcessID: processID,
 	}
 }
 
+// openLogFile returns the log file of a logs config (nil when there is none).
+// Files are opened once and shared by every process writing to the same path.
+func (s *ProcessService) openLogFile(cfg *LogsConfig) (*rotatingLogFile, error) {
+	if cfg == nil || cfg.File == nil {
+		return nil, nil
+	}
+	s.logFilesMu.Lock()
+	defer s.logFilesMu.Unlock()
+	if file, exists := s.logFiles[*cfg.File]; exists {
+		return file, nil
+	}
+
+	maxSizeMB := defaultLogMaxSizeMB
+	if cfg.MaxSizeMB != nil {
+		maxSizeMB = *cfg.MaxSizeMB
+	}
+	maxFiles := defaultLogMaxFiles
+	if cfg.MaxFiles != nil {
+		maxFiles = *cfg.MaxFiles
+	}
+	file, err := openRotatingLogFile(*cfg.File, maxSizeMB, maxFiles)
+	if err != nil {
+		return nil, err
+	}
+	if s.logFiles == nil {
+		s.logFiles = make(map[string]*rotatingLogFile)
+	}
+	s.logFiles[*cfg.File] = file
+	return file, nil
+}
+
+// dropLogBuffers forgets the history of previous runs of a named process, so that only the
+// latest run of each process is kept once it has exited.
+func (s *ProcessService) dropLogBuffers(name string) {
+	if name == "" {
+		return
+	}
+	s.logMu.Lock()
+	defer s.logMu.Unlock()
+	for id, buffer := range s.logBuffers {
+		if buffer.name == name {
+			delete(s.logBuffers, id)
+		}
+	}
+}
+
+// dropExitedLogBuffer forgets the logs of an unnamed process once it has exited for good, as they
+// can no longer be looked up by name. Named processes keep theirs until their next run.
+func (s *ProcessService) dropExitedLogBuffer(processID string, name string) {
+	if name != "" {
+		return
+	}
+	s.logMu.Lock()
+	defer s.logMu.Unlock()
+	delete(s.logBuffers, processID)
+}
+
+// latestLogBufferID returns the ID of the latest run of a named process that has logs.
+func (s *ProcessService) latestLogBufferID(name string) string {
+	s.logMu.Lock()
+	defer s.logMu.Unlock()
+	latestID := ""
+	var latestCursor int64
+	for id, buffer := range s.logBuffers {
+		if buffer.name != name || len(buffer.entries) == 0 {
+			continue
+		}
+		if cursor := buffer.at(len(buffer.entries) - 1).Cursor; cursor > latestCursor {
+			latestID, latestCursor = id, cursor
+		}
+	}
+	return latestID
+}
+
+// closeLogFiles closes every open log file. Later starts open them again.
+func (s *ProcessService) closeLogFiles() {
+	s.logFilesMu.Lock()
+	defer s.logFilesMu.Unlock()
+	for _, file := range s.logFiles {
+		_ = file.close()
+	}
+	s.logFiles = nil
+}
+
+// findActiveByName returns the ID of the running or restart-pending process with the given name, if any.
+// Processes being stopped are not active, even before they exit.
+func (s *ProcessService) findActiveByName(name string) string {
+	if name == "" {
+		return ""
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for id, state := range s.processes {
+		if state.launch.name == name && !state.exited && !state.manualStop && !state.stopping {
+			return id
+		}
+	}
+	return ""
+}
+
+// waitForStoppingByName waits for the processes with the given name that are being stopped to exit,
+// so that a new run does not compete with them for resources (ports, files).
+func (s *ProcessService) waitForStoppingByName(name string) {
+	s.mu.RLock()
+	var ids []string
+	for id, state := range s.processes {
+		if state.launch.name == name && !state.exited && (state.manualStop || state.stopping) {
+			ids = append(ids, id)
+		}
+	}
+	s.mu.RUnlock()
+	for _, id := range ids {
+		s.stopAndWait(id)
+	}
+}
+
+// stopAndWait stops a process and waits for it to exit, so its resources (ports, files) are released.
+func (s *ProcessService) stopAndWait(id string) ProcessStopResult {
+	var done chan struct{}
+	wait := newStopPolicy(nil).waitTimeout()
+	s.mu.RLock()
+	if state, exists := s.processes[id]; exists {
+		done = state.done
+		wait = newStopPolicy(state.launch.stopCfg).waitTimeout()
+	}
+	s.mu.RUnlock()
+
+	result := s.Stop(id)
+	if done != nil {
+		select {
+		case <-done:
+		case <-time.After(wait):
+		}
+	}
+	return result
+}
+
+// restartProcess stops a process and starts it again with the same launch config, under a new ID.
+func (s *ProcessService) restartProcess(id string) ProcessStartResult {
+	s.mu.RLock()
+	state, exists := s.processes[id]
+	var launch launchConfig
+	if exists {
+		launch = state.launch
+	}
+	s.mu.RUnlock()
+	if !exists {
+		return ProcessStartResult{Success: false, Error: "Process not found"}
+	}
+
+	s.stopAndWait(id)
+	return s.launchProcess(launch)
+}
+
+// listProcesses returns a snapshot of every running or restart-pending process.
+func (s *ProcessService) listProcesses() []ProcessInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	infos := make([]ProcessInfo, 0, len(s.processes))
+	for id, state := range s.processes {
+		if state.exited {
+			continue
+		}
+		info := ProcessInfo{
+			ProcessID:  id,
+			Name:       state.launch.name,
+			Command:    state.launch.command,
+			Cwd:        state.launch.cwd,
+			RetryCount: state.retryCount,
+			Ready:      state.cmd != nil && state.ready,
+		}
+		switch {
+		case state.cmd == nil:
+			info.Status = processStatusRestarting
+		case !state.ready:
+			info.Status = processStatusStarting
+		default:
+			info.Status = processStatusRunning
+		}
+		if state.cmd != nil {
+			info.Pid = state.pid
+			info.StartedAt = state.lastStartTime.UTC().Format(time.RFC3339Nano)
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// --- Exported methods (Wails bindings) ---
+
+// Start spawns a new process and returns its ID.
+func (s *ProcessService) Start(cwd string, command string, restartConfig *RestartConfig, env map[string]string, envFiles []string) ProcessStartResult {
+	return s.startProcess(launchConfig{cwd: cwd, command: command, restartCfg: restartConfig}, env, envFiles)
+}
+
+// StartWithDependencies starts a named process after its transitive dependencies, in topological order.
+// specs must describe every process in the dependency chain. Dependencies that are already active are
+// reused as-is, and processes still being stopped are waited for before starting again.
+// Returns one result per process in the chain, keyed by name.
+func (s *ProcessService) StartWithDependencies(name string, specs []ProcessSpec) map[string]ProcessStartResult {
+	results := make(map[string]ProcessStartResult)
+	order, err := newSpecDependencyGraph(specs).startOrder(name)
+	if err != nil {
+		results[name] = ProcessStartResult{Success: false, Error: err.Error()}
+		return results
+	}
+
+	specsByName := make(map[string]ProcessSpec, len(specs))
+	for _, spec := range specs {
+		specsByName[spec.Name] = spec
+	}
+
+	abort := func(from int, reason string) {
+		for _, remaining := range order[from:] {
+			results[remaining] = ProcessStartResult{Success: false, Error: reason}
+		}
+	}
+
+	for i, n := range order {
+		result := s.startSpec(specsByName[n])
+		results[n] = result
+		if !result.Success {
+			abort(i+1, fmt.Sprintf("Dependency %q failed to start", n))
+			break
+		}
+
+		// Dependents only start once this dependency is actually ready
+		if n != name && !s.waitForReady(result.ProcessID) {
+			abort(i+1, fmt.Sprintf("Dependency %q did not become ready", n))
+			break
+		}
+	}
+	return results
+}
+
+// startSpec starts the process of a spec, or returns the ID of its active process.
+func (s *ProcessService) startSpec(spec ProcessSpec) ProcessStartResult {
+	s.waitForStoppingByName(spec.Name)
+
+	s.startMu.Lock()
+	defer s.startMu.Unlock()
+	if id := s.findActiveByName(spec.Name); id != "" {
+		return ProcessStartResult{Success: true, ProcessID: id}
+	}
+	return s.startProcess(launchConfig{
+		name:         spec.Name,
+		cwd:          spec.Cwd,
+		command:      spec.Command,
+		shell:        spec.Shell,
+		tty:          spec.TTY,
+		stdin:        spec.Stdin,
+		restartCfg:   spec.Restart,
+		stopCfg:      spec.Stop,
+		logsCfg:      spec.Logs,
+		logFormat:    spec.LogFormat,
+		logRateLimit: spec.LogRateLimit,
+		multilineCfg: spec.Multiline,
+		alertCfgs:    spec.Alerts,
+		readyCfg:     spec.ReadyWhen,
+		healthCfg:    spec.Healthcheck,
+	}, spec.Env, spec.EnvFiles)
+}
+
+// StartByName loads a config file and starts one of its processes after its dependencies, exactly like the UI.
+// argValues (arg name → value) and env apply to that process only; missing args use their default and
+// env is merged over the config env. Dependencies use their defaults. Returns one result per process in the chain.
+func (s *ProcessService) StartByName(configPath string, name string, argValues map[string]any, env map[string]string) map[string]ProcessStartResult {
+	config, rootDirectory, err := loadConfigFile(configPath)
+	if err != nil {
+		return map[string]ProcessStartResult{name: {Success: false, Error: err.Error()}}
+	}
+	specs := make([]ProcessSpec, 0, len(config.Processes))
+	for _, p := range config.Processes {
+		if p.Name == name {
+			specs = append(specs, buildProcessSpec(p, rootDirectory, argValues, env))
+		} else {
+			specs = append(specs, buildProcessSpec(p, rootDirectory, nil, nil))
+		}
+	}
+	return s.StartWithDependencies(name, specs)
+}
+
+// StartProfile loads a config file and starts the processes of one of its profiles after their dependencies,
+// exactly like the UI. The profile arg values and env apply to every process they name, dependencies included.
+// Returns one result per process started, keyed by name.
+func (s *ProcessService) StartProfile(configPath string, profile string) map[string]ProcessStartResult {
+	config, rootDirectory, err := loadConfigFile(configPath)
+	if err != nil {
+		return map[string]ProcessStartResult{profile: {Success: false, Error: err.Error()}}
+	}
+	selected, exists := config.Profiles[profile]
+	if !exists {
+		return map[string]ProcessStartResult{profile: {Success: false, Error: fmt.Sprintf("unknown profile: %s", profile)}}
+	}
+	specs := buildProfileSpecs(config, rootDirectory, selected)
+	results := make(map[string]ProcessStartResult)
+	for _, name := range selected.Processes {
+		maps.Copy(results, s.StartWithDependencies(name, specs))
+	}
+	return results
+}
+
 // Stop terminates a process by ID. Idempotent — returns success for unknown IDs.
 func (s *ProcessService) Stop(id string) ProcessStopResult {
 	s.mu.Lock()
@@ -419,26 +1160,132 @@ func (s *ProcessService) Stop(id string) ProcessStopResult {
 	if state.cmd == nil {
 		delete(s.processes, id)
 		s.mu.Unlock()
+		s.dropExitedLogBuffer(id, state.launch.name)
 		return ProcessStopResult{Success: true}
 	}
 
-	pid := state.pid
 	s.mu.Unlock()
 
-	// Send SIGTERM to process group
-	_ = syscall.Kill(-pid, syscall.SIGTERM)
+	s.terminateProcessGroup(id, state)
 
-	// Schedule force kill after timeout (capture pid to avoid re-reading from map)
-	time.AfterFunc(processKillTimeoutMs*time.Millisecond, func() {
-		s.mu.RLock()
-		currentState, stillExists := s.processes[id]
+	return ProcessStopResult{Success: true}
+}
+
+// StopWithDependents stops every active process that transitively depends on name, deepest first,
+// then name itself. Each process is given time to exit before its dependencies are stopped.
+// specs must describe every process in the config. Returns one result per stopped process, keyed by name.
+func (s *ProcessService) StopWithDependents(name string, specs []ProcessSpec) map[string]ProcessStopResult {
+	order := append(newSpecDependencyGraph(specs).dependents(name), name)
+	results := make(map[string]ProcessStopResult)
+	for _, n := range order {
+		if id := s.findActiveByName(n); id != "" {
+			results[n] = s.stopAndWait(id)
+		}
+	}
+	return results
+}
+
+// WriteStdin sends data to the stdin of a running process started with stdin: true.
+// Line-based programs only see a line once it ends with "\n".
+func (s *ProcessService) WriteStdin(id string, data string) ProcessStdinResult {
+	s.mu.RLock()
+	state, exists := s.processes[id]
+	var writer io.Writer
+	switch {
+	case !exists || state.cmd == nil || state.exited:
+	case !state.launch.stdin:
 		s.mu.RUnlock()
-		if stillExists && !currentState.exited {
-			_ = syscall.Kill(-pid, syscall.SIGKILL)
+		return ProcessStdinResult{Success: false, Error: "Process does not accept input: set stdin: true in the config"}
+	case state.pty != nil:
+		writer = state.pty
+	case state.stdin != nil:
+		writer = state.stdin
+	}
+	s.mu.RUnlock()
+	if writer == nil {
+		return ProcessStdinResult{Success: false, Error: "Process is not running"}
+	}
+
+	// Written outside the lock: a process that does not read its input blocks the write
+	if _, err := io.WriteString(writer, data); err != nil {
+		return ProcessStdinResult{Success: false, Error: fmt.Sprintf("Failed to write to stdin: %s", err.Error())}
+	}
+	return ProcessStdinResult{Success: true}
+}
+
+// ResizeTerminal sets the window size of a tty process, e.g. when the log drawer is resized.
+// The size is kept for restarts. Returns false if the process is not running in a terminal.
+func (s *ProcessService) ResizeTerminal(id string, cols int, rows int) bool {
+	if cols <= 0 || rows <= 0 {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, exists := s.processes[id]
+	if !exists || !state.launch.tty {
+		return false
+	}
+	state.launch.ttySize = pty.Winsize{Cols: uint16(min(cols, math.MaxUint16)), Rows: uint16(min(rows, math.MaxUint16))}
+	// Restart-pending placeholder: the size is applied when it respawns
+	if state.pty == nil {
+		return true
+	}
+	// The kernel notifies the process with SIGWINCH
+	return pty.Setsize(state.pty, &state.launch.ttySize) == nil
+}
+
+// GetLogs returns the buffered logs of a process after sinceCursor (0 for the oldest), up to limit
+// entries (0 for all). Pass the returned cursor back to get the entries that follow.
+func (s *ProcessService) GetLogs(id string, sinceCursor int64, limit int) ProcessLogsResult {
+	s.logMu.Lock()
+	defer s.logMu.Unlock()
+	buffer, exists := s.logBuffers[id]
+	if !exists {
+		return ProcessLogsResult{Logs: []ProcessLogData{}, Cursor: sinceCursor}
+	}
+	logs, hasMore := buffer.since(sinceCursor, limit)
+	cursor := sinceCursor
+	if len(logs) > 0 {
+		cursor = logs[len(logs)-1].Cursor
+	}
+	return ProcessLogsResult{Logs: logs, Cursor: cursor, HasMore: hasMore}
+}
+
+// SearchLogs returns the buffered logs of a process whose output matches a regular expression,
+// optionally restricted to some log types (stdout, stderr, exit).
+func (s *ProcessService) SearchLogs(id string, pattern string, types []string) ProcessLogSearchResult {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return ProcessLogSearchResult{
+			Logs:  []ProcessLogData{},
+			Error: fmt.Sprintf("Invalid regular expression: %s", err.Error()),
 		}
-	})
+	}
+	s.logMu.Lock()
+	defer s.logMu.Unlock()
+	buffer, exists := s.logBuffers[id]
+	if !exists {
+		return ProcessLogSearchResult{Logs: []ProcessLogData{}}
+	}
+	return ProcessLogSearchResult{Logs: buffer.search(re, types)}
+}
 
-	return ProcessStopResult{Success: true}
+// maxStopWaitTimeout returns the longest time an active process may take to stop.
+func (s *ProcessService) maxStopWaitTimeout() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	longest := newStopPolicy(nil).waitTimeout()
+	for _, state := range s.processes {
+		longest = max(longest, newStopPolicy(state.launch.stopCfg).waitTimeout())
+	}
+	return longest
+}
+
+// activeProcessCount returns the number of running or restart-pending processes.
+func (s *ProcessService) activeProcessCount() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.processes)
 }
 
 // IsRunning returns whether a process is currently active.
@@ -456,6 +1303,27 @@ func (s *ProcessService) IsRunning(id string) bool {
 	return true
 }
 
+// IsReady returns whether a process is running and has passed its readiness checks.
+// Processes without ready_when are ready as soon as they are running.
+func (s *ProcessService) IsReady(id string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	state, exists := s.processes[id]
+	return exists && state.cmd != nil && !state.exited && state.ready
+}
+
+// BulkReadyStatus returns readiness for multiple process IDs.
+func (s *ProcessService) BulkReadyStatus(ids []string) map[string]bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		state, exists := s.processes[id]
+		result[id] = exists && state.cmd != nil && !state.exited && state.ready
+	}
+	return result
+}
+
 // BulkStatus returns running status for multiple process IDs.
 func (s *ProcessService) BulkStatus(ids []string) map[string]bool {
 	s.mu.RLock()
@@ -483,22 +1351,43 @@ func (s *ProcessService) GetRunningProcessPids(ids []string) map[string]int {
 	return result
 }
 
-// StopAll terminates all managed processes. Called on app shutdown.
+// StopAll terminates all managed processes and waits for them to exit, so that no process outlives
+// the app. Stop commands are waited for, and stubborn processes are killed after their stop timeout.
+// Called on app shutdown.
 func (s *ProcessService) StopAll() {
 	s.flushLogs()
+	deadline := time.Now().Add(s.maxStopWaitTimeout())
 
 	s.mu.RLock()
-	ids := make([]string, 0, len(s.processes))
-	for id := range s.processes {
-		ids = append(ids, id)
-	}
+	states := make(map[string]*processState, len(s.processes))
+	maps.Copy(states, s.processes)
 	s.mu.RUnlock()
 
-	for _, id := range ids {
+	for id := range states {
 		s.Stop(id)
 	}
 
+	// Restart-pending placeholders have nothing to wait for
+	var waits []chan struct{}
+	s.mu.RLock()
+	for _, state := range states {
+		if state.cmd != nil {
+			waits = append(waits, state.done, state.stopped)
+		}
+	}
+	s.mu.RUnlock()
+	for _, wait := range waits {
+		if wait == nil {
+			continue
+		}
+		select {
+		case <-wait:
+		case <-time.After(time.Until(deadline)):
+		}
+	}
+
 	s.stopBatchTicker()
+	s.closeLogFiles()
 }
 
 // ServiceShutdown is called by Wails when the application is shutting down.
@@ -1,9 +1,12 @@
 package backend
 
 import (
+	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
+	"syscall"
 	"testing"
 	"time"
 )
@@ -74,6 +77,23 @@ func (m *mockEmitter) waitForLogContaining(logType string) bool {
 	return false
 }
 
+// logOutputs returns the output of every emitted log entry of the given type, in order.
+func (m *mockEmitter) logOutputs(logType string) []string {
+	var outputs []string
+	for _, e := range m.getEvents() {
+		if e.name != eventProcessLogBatch || len(e.data) == 0 {
+			continue
+		}
+		batch, _ := e.data[0].([]ProcessLogData)
+		for _, log := range batch {
+			if log.Type == logType {
+				outputs = append(outputs, log.Output)
+			}
+		}
+	}
+	return outputs
+}
+
 func (m *mockEmitter) countEvents(name string) int {
 	count := 0
 	for _, e := range m.getEvents() {
@@ -100,7 +120,7 @@ func TestStart_Success(t *testing.T) {
 	svc, _ := newTestProcessService()
 	t.Cleanup(svc.StopAll)
 
-	result := svc.Start(t.TempDir(), "echo hello", nil, nil, "")
+	result := svc.Start(t.TempDir(), "echo hello", nil, nil, nil)
 
 	if !result.Success {
 		t.Fatalf("expected success, got error: %s", result.Error)
@@ -118,7 +138,7 @@ func TestStart_InvalidCwd(t *testing.T) {
 	svc, _ := newTestProcessService()
 	t.Cleanup(svc.StopAll)
 
-	result := svc.Start("/nonexistent/path/that/does/not/exist", "echo hello", nil, nil, "")
+	result := svc.Start("/nonexistent/path/that/does/not/exist", "echo hello", nil, nil, nil)
 
 	if result.Success {
 		t.Fatal("expected failure for non-existent cwd")
@@ -136,7 +156,7 @@ func TestStop_ExistingProcess(t *testing.T) {
 	svc, _ := newTestProcessService()
 	t.Cleanup(svc.StopAll)
 
-	start := svc.Start(t.TempDir(), "sleep 30", nil, nil, "")
+	start := svc.Start(t.TempDir(), "sleep 30", nil, nil, nil)
 	if !start.Success {
 		t.Fatalf("start failed: %s", start.Error)
 	}
@@ -163,7 +183,7 @@ func TestIsRunning_Running(t *testing.T) {
 	svc, _ := newTestProcessService()
 	t.Cleanup(svc.StopAll)
 
-	start := svc.Start(t.TempDir(), "sleep 30", nil, nil, "")
+	start := svc.Start(t.TempDir(), "sleep 30", nil, nil, nil)
 	if !start.Success {
 		t.Fatalf("start failed: %s", start.Error)
 	}
@@ -187,7 +207,7 @@ func TestIsRunning_AfterStop(t *testing.T) {
 	svc, _ := newTestProcessService()
 	t.Cleanup(svc.StopAll)
 
-	start := svc.Start(t.TempDir(), "sleep 30", nil, nil, "")
+	start := svc.Start(t.TempDir(), "sleep 30", nil, nil, nil)
 	if !start.Success {
 		t.Fatalf("start failed: %s", start.Error)
 	}
@@ -207,8 +227,8 @@ func TestBulkStatus(t *testing.T) {
 	t.Cleanup(svc.StopAll)
 
 	dir := t.TempDir()
-	start1 := svc.Start(dir, "sleep 30", nil, nil, "")
-	start2 := svc.Start(dir, "sleep 30", nil, nil, "")
+	start1 := svc.Start(dir, "sleep 30", nil, nil, nil)
+	start2 := svc.Start(dir, "sleep 30", nil, nil, nil)
 	if !start1.Success || !start2.Success {
 		t.Fatal("start failed")
 	}
@@ -231,7 +251,7 @@ func TestGetRunningProcessPids(t *testing.T) {
 	svc, _ := newTestProcessService()
 	t.Cleanup(svc.StopAll)
 
-	start := svc.Start(t.TempDir(), "sleep 30", nil, nil, "")
+	start := svc.Start(t.TempDir(), "sleep 30", nil, nil, nil)
 	if !start.Success {
 		t.Fatalf("start failed: %s", start.Error)
 	}
@@ -259,8 +279,8 @@ func TestStopAll_StopsProcesses(t *testing.T) {
 	svc, _ := newTestProcessService()
 
 	dir := t.TempDir()
-	start1 := svc.Start(dir, "sleep 30", nil, nil, "")
-	start2 := svc.Start(dir, "sleep 30", nil, nil, "")
+	start1 := svc.Start(dir, "sleep 30", nil, nil, nil)
+	start2 := svc.Start(dir, "sleep 30", nil, nil, nil)
 	if !start1.Success || !start2.Success {
 		t.Fatal("start failed")
 	}
@@ -281,7 +301,7 @@ func TestLogBatching_EmitsEvents(t *testing.T) {
 	svc, emitter := newTestProcessService()
 	t.Cleanup(svc.StopAll)
 
-	svc.Start(t.TempDir(), "echo hello", nil, nil, "")
+	svc.Start(t.TempDir(), "echo hello", nil, nil, nil)
 
 	if !emitter.waitForEvent(eventProcessLogBatch) {
 		t.Fatal("expected process-log:batch event to be emitted")
@@ -293,7 +313,7 @@ func TestExitLog_EmittedOnExit(t *testing.T) {
 	svc, emitter := newTestProcessService()
 	t.Cleanup(svc.StopAll)
 
-	svc.Start(t.TempDir(), "echo hello", nil, nil, "")
+	svc.Start(t.TempDir(), "echo hello", nil, nil, nil)
 
 	// Wait for process to exit and logs to flush
 	time.Sleep(500 * time.Millisecond)
@@ -332,7 +352,7 @@ func TestCrashEvent_NonZeroExit(t *testing.T) {
 	svc, emitter := newTestProcessService()
 	t.Cleanup(svc.StopAll)
 
-	svc.Start(t.TempDir(), "sh -c 'exit 1'", nil, nil, "")
+	svc.Start(t.TempDir(), "sh -c 'exit 1'", nil, nil, nil)
 
 	if !emitter.waitForEvent(eventProcessCrash) {
 		t.Fatal("expected process-crash event")
@@ -369,7 +389,7 @@ func TestRestart_TriggeredOnCrash(t *testing.T) {
 		DelayMs:    intPtr(100),
 	}
 
-	svc.Start(t.TempDir(), "sh -c 'exit 1'", restartCfg, nil, "")
+	svc.Start(t.TempDir(), "sh -c 'exit 1'", restartCfg, nil, nil)
 
 	// Wait for crash + restart cycle
 	if !emitter.waitForEvent(eventProcessCrash) {
@@ -410,7 +430,7 @@ func TestRestart_MaxRetriesExceeded(t *testing.T) {
 		DelayMs:    intPtr(10),
 	}
 
-	svc.Start(t.TempDir(), "sh -c 'exit 1'", restartCfg, nil, "")
+	svc.Start(t.TempDir(), "sh -c 'exit 1'", restartCfg, nil, nil)
 
 	// Wait for all retries to exhaust (initial crash + 1 retry + final crash)
 	time.Sleep(1 * time.Second)
@@ -443,7 +463,7 @@ func TestStart_CustomEnvPassedToProcess(t *testing.T) {
 	t.Cleanup(svc.StopAll)
 
 	env := map[string]string{"CLICK_LAUNCH_TEST_VAR": "hello_from_test"}
-	svc.Start(t.TempDir(), "echo $CLICK_LAUNCH_TEST_VAR", nil, env, "")
+	svc.Start(t.TempDir(), "echo $CLICK_LAUNCH_TEST_VAR", nil, env, nil)
 
 	// Wait for process to fully exit (ensures all logs are flushed)
 	if !emitter.waitForLogContaining("exit") {
@@ -482,7 +502,7 @@ func TestStop_CancelsPendingRestart(t *testing.T) {
 		DelayMs:    intPtr(5000), // Long delay so we can cancel
 	}
 
-	start := svc.Start(t.TempDir(), "sh -c 'exit 1'", restartCfg, nil, "")
+	start := svc.Start(t.TempDir(), "sh -c 'exit 1'", restartCfg, nil, nil)
 	if !start.Success {
 		t.Fatalf("start failed: %s", start.Error)
 	}
@@ -515,7 +535,7 @@ func TestStartWithEnvFile(t *testing.T) {
 	}
 
 	// sample.env has DB_HOST=localhost, APP_ENV=development, etc.
-	result := svc.Start(cwd, "echo $DB_HOST $APP_ENV $OVERRIDE", nil, map[string]string{"OVERRIDE": "custom"}, "sample.env")
+	result := svc.Start(cwd, "echo $DB_HOST $APP_ENV $OVERRIDE", nil, map[string]string{"OVERRIDE": "custom"}, []string{"sample.env"})
 	if !result.Success {
 		t.Fatalf("Start failed: %s", result.Error)
 	}
@@ -545,6 +565,112 @@ func TestStartWithEnvFile(t *testing.T) {
 	}
 }
 
+func TestStartInterpolatesEnv(t *testing.T) {
+	t.Parallel()
+	svc, emitter := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	cwd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// sample.env has DB_HOST=localhost and DB_PORT=5432; single quotes keep the shell from expanding the unescaped reference
+	env := map[string]string{"DB_URL": "postgres://${DB_HOST}:${DB_PORT:-1}"}
+	result := svc.Start(cwd, `echo "${DB_URL}" '$${DB_URL}'`, nil, env, []string{"sample.env"})
+	if !result.Success {
+		t.Fatalf("Start failed: %s", result.Error)
+	}
+	if !emitter.waitForLogContaining("exit") {
+		t.Fatal("process did not exit in time")
+	}
+
+	found := false
+	for _, e := range emitter.getEvents() {
+		if e.name != eventProcessLogBatch || len(e.data) == 0 {
+			continue
+		}
+		batch, ok := e.data[0].([]ProcessLogData)
+		if !ok {
+			continue
+		}
+		for _, log := range batch {
+			if log.Type == "stdout" && strings.Contains(log.Output, "postgres://localhost:5432 ${DB_URL}") {
+				found = true
+			}
+		}
+	}
+	if !found {
+		t.Error("expected the command to be interpolated from the resolved env, and escapes to be kept literally")
+	}
+}
+
+func TestStart_CommandVariables(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		command  string
+		shell    string
+		env      map[string]string
+		expected string
+	}{
+		{
+			name:     "shell values are not run as code",
+			command:  "echo ${MSG}",
+			env:      map[string]string{"MSG": "it's here; touch pwned"},
+			expected: "it's here; touch pwned\n",
+		},
+		{
+			name:     "shell-local references are left to the shell",
+			command:  `for f in a b; do echo "item ${f}"; done`,
+			expected: "item a\nitem b\n",
+		},
+		{
+			name:     "shell defaults are left to the shell",
+			command:  `echo "${CLICK_LAUNCH_UNSET:-fallback}"`,
+			expected: "fallback\n",
+		},
+		{
+			name:     "argv values stay within their argument",
+			command:  buildCommand(ProcessConfig{Command: []string{"echo", "${MSG}", "${CLICK_LAUNCH_UNSET:-x}", "${CLICK_LAUNCH_UNSET}"}}, nil),
+			shell:    "none",
+			env:      map[string]string{"MSG": "it's here; $(touch pwned)"},
+			expected: "it's here; $(touch pwned) x ${CLICK_LAUNCH_UNSET}\n",
+		},
+		{
+			name:     "argv references are expanded by the shell",
+			command:  buildCommand(ProcessConfig{Command: []string{"echo", "msg: ${MSG}", "$${MSG}"}}, nil),
+			shell:    "sh",
+			env:      map[string]string{"MSG": "it's; touch pwned"},
+			expected: "msg: it's; touch pwned ${MSG}\n",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			svc, emitter := newTestProcessService()
+			t.Cleanup(svc.StopAll)
+
+			cwd := t.TempDir()
+			results := svc.StartWithDependencies("test", []ProcessSpec{{Name: "test", Cwd: cwd, Command: tc.command, Shell: tc.shell, Env: tc.env}})
+			if !results["test"].Success {
+				t.Fatalf("Start failed: %+v", results)
+			}
+			if !emitter.waitForLogContaining("exit") {
+				t.Fatal("process did not exit in time")
+			}
+
+			if output := strings.Join(emitter.logOutputs("stdout"), ""); output != tc.expected {
+				t.Errorf("expected output %q, got %q", tc.expected, output)
+			}
+			if _, err := os.Stat(filepath.Join(cwd, "pwned")); err == nil {
+				t.Error("expected the value not to be run as shell code")
+			}
+		})
+	}
+}
+
 func TestStartWithEnvFileMissing(t *testing.T) {
 	t.Parallel()
 	svc, _ := newTestProcessService()
@@ -555,7 +681,7 @@ func TestStartWithEnvFileMissing(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	result := svc.Start(cwd, "echo hello", nil, nil, "nonexistent.env")
+	result := svc.Start(cwd, "echo hello", nil, nil, []string{"nonexistent.env"})
 	if result.Success {
 		t.Fatal("expected Start to fail for missing env file")
 	}
@@ -564,14 +690,1335 @@ func TestStartWithEnvFileMissing(t *testing.T) {
 	}
 }
 
+func TestStartWithOptionalEnvFiles(t *testing.T) {
+	t.Parallel()
+	svc, emitter := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	cwd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := svc.Start(cwd, "echo $DB_HOST", nil, nil, []string{"nonexistent.env?", "sample.env", "nonexistent.local.env?"})
+	if !result.Success {
+		t.Fatalf("expected missing optional env files to be skipped, got: %s", result.Error)
+	}
+	if !emitter.waitForLogContaining("exit") {
+		t.Fatal("process did not exit in time")
+	}
+
+	found := false
+	for _, e := range emitter.getEvents() {
+		if e.name != eventProcessLogBatch || len(e.data) == 0 {
+			continue
+		}
+		batch, ok := e.data[0].([]ProcessLogData)
+		if !ok {
+			continue
+		}
+		for _, log := range batch {
+			if log.Type == "stdout" && strings.Contains(log.Output, "localhost") {
+				found = true
+			}
+		}
+	}
+	if !found {
+		t.Error("expected stdout to contain the env file vars")
+	}
+}
+
 func TestStartWithEmptyEnvFile(t *testing.T) {
 	t.Parallel()
 	svc, _ := newTestProcessService()
 	t.Cleanup(svc.StopAll)
 
-	// Empty string means no env file — should work normally
-	result := svc.Start(t.TempDir(), "echo hello", nil, nil, "")
+	// No env files — should work normally
+	result := svc.Start(t.TempDir(), "echo hello", nil, nil, nil)
 	if !result.Success {
 		t.Fatalf("Start failed: %s", result.Error)
 	}
 }
+
+func TestStartWithDependencies_StartsInOrder(t *testing.T) {
+	t.Parallel()
+	svc, _ := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	dir := t.TempDir()
+	specs := []ProcessSpec{
+		{Name: "web", Cwd: dir, Command: "sleep 30", DependsOn: []string{"api"}},
+		{Name: "api", Cwd: dir, Command: "sleep 30", DependsOn: []string{"db"}},
+		{Name: "db", Cwd: dir, Command: "sleep 30"},
+		{Name: "unrelated", Cwd: dir, Command: "sleep 30"},
+	}
+
+	results := svc.StartWithDependencies("web", specs)
+
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d: %v", len(results), results)
+	}
+	for _, name := range []string{"db", "api", "web"} {
+		result, ok := results[name]
+		if !ok || !result.Success {
+			t.Fatalf("expected %s to start, got %+v", name, result)
+		}
+		if !svc.IsRunning(result.ProcessID) {
+			t.Errorf("expected %s to be running", name)
+		}
+	}
+	if svc.findActiveByName("unrelated") != "" {
+		t.Error("expected unrelated process to not be started")
+	}
+}
+
+func TestStartWithDependencies_ReusesActiveDependency(t *testing.T) {
+	t.Parallel()
+	svc, _ := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	dir := t.TempDir()
+	specs := []ProcessSpec{
+		{Name: "db", Cwd: dir, Command: "sleep 30"},
+		{Name: "api", Cwd: dir, Command: "sleep 30", DependsOn: []string{"db"}},
+	}
+
+	first := svc.StartWithDependencies("db", specs)
+	second := svc.StartWithDependencies("api", specs)
+
+	if first["db"].ProcessID == "" || first["db"].ProcessID != second["db"].ProcessID {
+		t.Fatalf("expected db to be reused, got %q then %q", first["db"].ProcessID, second["db"].ProcessID)
+	}
+}
+
+func TestStartWithDependencies_FailedDependencyAbortsChain(t *testing.T) {
+	t.Parallel()
+	svc, _ := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	specs := []ProcessSpec{
+		{Name: "db", Cwd: "/nonexistent/path/that/does/not/exist", Command: "sleep 30"},
+		{Name: "api", Cwd: t.TempDir(), Command: "sleep 30", DependsOn: []string{"db"}},
+	}
+
+	results := svc.StartWithDependencies("api", specs)
+
+	if results["db"].Success {
+		t.Fatal("expected db to fail")
+	}
+	if results["api"].Success || !strings.Contains(results["api"].Error, "db") {
+		t.Fatalf("expected api to fail because of db, got %+v", results["api"])
+	}
+	if svc.findActiveByName("api") != "" {
+		t.Error("expected api to not be started")
+	}
+}
+
+func TestStartWithDependencies_Cycle(t *testing.T) {
+	t.Parallel()
+	svc, _ := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	dir := t.TempDir()
+	specs := []ProcessSpec{
+		{Name: "a", Cwd: dir, Command: "sleep 30", DependsOn: []string{"b"}},
+		{Name: "b", Cwd: dir, Command: "sleep 30", DependsOn: []string{"a"}},
+	}
+
+	results := svc.StartWithDependencies("a", specs)
+
+	if results["a"].Success || !strings.Contains(results["a"].Error, "cycle") {
+		t.Fatalf("expected cycle error, got %+v", results["a"])
+	}
+}
+
+func TestStopWithDependents_StopsDependentsFirst(t *testing.T) {
+	t.Parallel()
+	svc, _ := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	dir := t.TempDir()
+	specs := []ProcessSpec{
+		{Name: "db", Cwd: dir, Command: "sleep 30"},
+		{Name: "api", Cwd: dir, Command: "sleep 30", DependsOn: []string{"db"}},
+		{Name: "web", Cwd: dir, Command: "sleep 30", DependsOn: []string{"api"}},
+		{Name: "unrelated", Cwd: dir, Command: "sleep 30"},
+	}
+	started := svc.StartWithDependencies("web", specs)
+	unrelated := svc.StartWithDependencies("unrelated", specs)
+
+	results := svc.StopWithDependents("db", specs)
+
+	if len(results) != 3 {
+		t.Fatalf("expected 3 stop results, got %d: %v", len(results), results)
+	}
+	for _, name := range []string{"db", "api", "web"} {
+		if svc.IsRunning(started[name].ProcessID) {
+			t.Errorf("expected %s to be stopped", name)
+		}
+	}
+	if !svc.IsRunning(unrelated["unrelated"].ProcessID) {
+		t.Error("expected unrelated process to keep running")
+	}
+}
+
+func TestReadiness_EmitsReadyEvent(t *testing.T) {
+	t.Parallel()
+	svc, emitter := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	specs := []ProcessSpec{{
+		Name:      "server",
+		Cwd:       t.TempDir(),
+		Command:   "sleep 0.2; echo 'listening on 3000'; sleep 30",
+		ReadyWhen: &ReadyConfig{LogMatch: strPtr("listening on"), IntervalMs: intPtr(20)},
+	}}
+	results := svc.StartWithDependencies("server", specs)
+	id := results["server"].ProcessID
+
+	if svc.IsReady(id) {
+		t.Fatal("expected process to not be ready before the log line")
+	}
+	if !svc.IsRunning(id) {
+		t.Fatal("expected process to be running while not ready")
+	}
+	if !emitter.waitForEvent("process-ready") {
+		t.Fatal("expected process-ready event")
+	}
+	if !svc.IsReady(id) || !svc.BulkReadyStatus([]string{id})[id] {
+		t.Fatal("expected process to be ready")
+	}
+}
+
+func TestReadiness_ReadyImmediatelyWithoutProbe(t *testing.T) {
+	t.Parallel()
+	svc, emitter := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	start := svc.Start(t.TempDir(), "sleep 30", nil, nil, nil)
+
+	if !svc.IsReady(start.ProcessID) {
+		t.Fatal("expected process without ready_when to be ready")
+	}
+	if emitter.countEvents("process-ready") != 0 {
+		t.Fatal("expected no process-ready event without ready_when")
+	}
+}
+
+func TestReadiness_TimeoutEvent(t *testing.T) {
+	t.Parallel()
+	svc, emitter := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	specs := []ProcessSpec{{
+		Name:      "server",
+		Cwd:       t.TempDir(),
+		Command:   "sleep 30",
+		ReadyWhen: &ReadyConfig{Command: strPtr("false"), TimeoutMs: intPtr(100), IntervalMs: intPtr(20)},
+	}}
+	results := svc.StartWithDependencies("server", specs)
+
+	if !emitter.waitForEvent("process-ready-timeout") {
+		t.Fatal("expected process-ready-timeout event")
+	}
+	if svc.IsReady(results["server"].ProcessID) {
+		t.Fatal("expected process to not be ready after timeout")
+	}
+}
+
+func TestStartWithDependencies_WaitsForReadiness(t *testing.T) {
+	t.Parallel()
+	svc, _ := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	dir := t.TempDir()
+	specs := []ProcessSpec{
+		{
+			Name:      "db",
+			Cwd:       dir,
+			Command:   "sleep 0.3; echo ready; sleep 30",
+			ReadyWhen: &ReadyConfig{LogMatch: strPtr("^ready$"), IntervalMs: intPtr(20)},
+		},
+		{Name: "api", Cwd: dir, Command: "sleep 30", DependsOn: []string{"db"}},
+	}
+
+	results := svc.StartWithDependencies("api", specs)
+
+	if !results["api"].Success {
+		t.Fatalf("expected api to start, got %+v", results["api"])
+	}
+	if !svc.IsReady(results["db"].ProcessID) {
+		t.Fatal("expected db to be ready before api started")
+	}
+}
+
+func TestStartWithDependencies_DependencyNeverReady(t *testing.T) {
+	t.Parallel()
+	svc, _ := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	dir := t.TempDir()
+	specs := []ProcessSpec{
+		{
+			Name:      "db",
+			Cwd:       dir,
+			Command:   "sleep 30",
+			ReadyWhen: &ReadyConfig{Command: strPtr("false"), TimeoutMs: intPtr(100), IntervalMs: intPtr(20)},
+		},
+		{Name: "api", Cwd: dir, Command: "sleep 30", DependsOn: []string{"db"}},
+	}
+
+	results := svc.StartWithDependencies("api", specs)
+
+	if results["api"].Success || !strings.Contains(results["api"].Error, "did not become ready") {
+		t.Fatalf("expected api to fail on unready dependency, got %+v", results["api"])
+	}
+	if svc.findActiveByName("api") != "" {
+		t.Error("expected api to not be started")
+	}
+}
+
+func TestStartWithDependencies_StartsAgainWhileStopping(t *testing.T) {
+	t.Parallel()
+	svc, emitter := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	specs := []ProcessSpec{{
+		Name:    "api",
+		Cwd:     t.TempDir(),
+		Command: "trap 'sleep 0.3; exit 0' TERM; echo started; while true; do sleep 0.05; done",
+	}}
+	first := svc.StartWithDependencies("api", specs)["api"]
+	if !emitter.waitForLogContaining("stdout") {
+		t.Fatal("expected the process to start")
+	}
+
+	// Like a restart from the UI: stop, then start straight away
+	svc.Stop(first.ProcessID)
+	second := svc.StartWithDependencies("api", specs)["api"]
+
+	if !second.Success || second.ProcessID == first.ProcessID {
+		t.Fatalf("expected a new process, got %+v (previous ID %s)", second, first.ProcessID)
+	}
+	if svc.IsRunning(first.ProcessID) {
+		t.Error("expected the stopping process to have exited before the new one started")
+	}
+	if !svc.IsRunning(second.ProcessID) || svc.activeProcessCount() != 1 {
+		t.Errorf("expected only the new process to be active, got %d", svc.activeProcessCount())
+	}
+}
+
+func TestStartWithDependencies_DoesNotBlockWhileWaitingForReadiness(t *testing.T) {
+	t.Parallel()
+	svc, _ := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	dir := t.TempDir()
+	specs := []ProcessSpec{
+		{
+			Name:      "db",
+			Cwd:       dir,
+			Command:   "sleep 1; echo ready; sleep 30",
+			ReadyWhen: &ReadyConfig{LogMatch: strPtr("^ready$"), IntervalMs: intPtr(20)},
+		},
+		{Name: "api", Cwd: dir, Command: "sleep 30", DependsOn: []string{"db"}},
+		{Name: "unrelated", Cwd: dir, Command: "sleep 30"},
+	}
+
+	done := make(chan map[string]ProcessStartResult)
+	go func() { done <- svc.StartWithDependencies("api", specs) }()
+	time.Sleep(200 * time.Millisecond)
+
+	start := time.Now()
+	if result := svc.StartWithDependencies("unrelated", specs)["unrelated"]; !result.Success {
+		t.Fatalf("expected unrelated to start, got %+v", result)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected unrelated to start while db is not ready, took %v", elapsed)
+	}
+	if results := <-done; !results["api"].Success {
+		t.Fatalf("expected api to start, got %+v", results["api"])
+	}
+}
+
+func TestHealthcheck_UnhealthyTriggersRestart(t *testing.T) {
+	t.Parallel()
+	svc, emitter := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	specs := []ProcessSpec{{
+		Name:    "server",
+		Cwd:     t.TempDir(),
+		Command: "sleep 30",
+		Restart: &RestartConfig{Enabled: true, MaxRetries: intPtr(1), DelayMs: intPtr(10)},
+		Healthcheck: &HealthConfig{
+			Command:          strPtr("false"),
+			IntervalMs:       intPtr(20),
+			FailureThreshold: intPtr(2),
+		},
+	}}
+	svc.StartWithDependencies("server", specs)
+
+	if !emitter.waitForEvent("process-unhealthy") {
+		t.Fatal("expected process-unhealthy event")
+	}
+	if !emitter.waitForEvent("process-restart") {
+		t.Fatal("expected process-restart event after unhealthy kill")
+	}
+
+	for _, e := range emitter.getEvents() {
+		if e.name != eventProcessCrash || len(e.data) == 0 {
+			continue
+		}
+		if crash, ok := e.data[0].(ProcessCrashData); ok && crash.Unhealthy && crash.WillRestart {
+			return
+		}
+	}
+	t.Fatal("expected process-crash with unhealthy=true and willRestart=true")
+}
+
+func TestHealthcheck_HealthyProcessKeepsRunning(t *testing.T) {
+	t.Parallel()
+	svc, emitter := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	specs := []ProcessSpec{{
+		Name:    "server",
+		Cwd:     t.TempDir(),
+		Command: "sleep 30",
+		Healthcheck: &HealthConfig{
+			Command:          strPtr("true"),
+			IntervalMs:       intPtr(20),
+			FailureThreshold: intPtr(1),
+		},
+	}}
+	results := svc.StartWithDependencies("server", specs)
+
+	time.Sleep(200 * time.Millisecond)
+
+	if emitter.countEvents("process-unhealthy") != 0 {
+		t.Fatal("expected no process-unhealthy event")
+	}
+	if !svc.IsRunning(results["server"].ProcessID) {
+		t.Fatal("expected healthy process to keep running")
+	}
+}
+
+func TestStartWithDependencies_EmitsStartedEvent(t *testing.T) {
+	t.Parallel()
+	svc, emitter := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	results := svc.StartWithDependencies("api", []ProcessSpec{{Name: "api", Cwd: t.TempDir(), Command: "sleep 30"}})
+
+	for _, e := range emitter.getEvents() {
+		if e.name != "process-started" {
+			continue
+		}
+		data, ok := e.data[0].(ProcessStartedData)
+		if ok && data.Name == "api" && data.ProcessID == results["api"].ProcessID {
+			return
+		}
+	}
+	t.Fatal("expected process-started event with the process name and ID")
+}
+
+func TestRestartProcess_ReusesLaunchConfig(t *testing.T) {
+	t.Parallel()
+	svc, _ := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	results := svc.StartWithDependencies("api", []ProcessSpec{{
+		Name:    "api",
+		Cwd:     t.TempDir(),
+		Command: "sleep 30",
+		Env:     map[string]string{"PORT": "4000"},
+	}})
+	oldID := results["api"].ProcessID
+
+	restarted := svc.restartProcess(oldID)
+
+	if !restarted.Success {
+		t.Fatalf("expected restart to succeed, got %+v", restarted)
+	}
+	if restarted.ProcessID == oldID {
+		t.Error("expected a new process ID")
+	}
+	if svc.IsRunning(oldID) || !svc.IsRunning(restarted.ProcessID) {
+		t.Error("expected only the new process to be running")
+	}
+	infos := svc.listProcesses()
+	if len(infos) != 1 || infos[0].Name != "api" || infos[0].Command != "sleep 30" {
+		t.Errorf("expected the restarted process to keep its launch config, got %+v", infos)
+	}
+	if svc.restartProcess("unknown").Success {
+		t.Error("expected restart of an unknown process to fail")
+	}
+}
+
+func TestListProcesses(t *testing.T) {
+	t.Parallel()
+	svc, _ := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	dir := t.TempDir()
+	svc.StartWithDependencies("api", []ProcessSpec{
+		{Name: "api", Cwd: dir, Command: "sleep 30"},
+		{Name: "worker", Cwd: dir, Command: "sleep 30"},
+	})
+
+	infos := svc.listProcesses()
+
+	if len(infos) != 1 {
+		t.Fatalf("expected 1 process, got %+v", infos)
+	}
+	info := infos[0]
+	if info.Name != "api" || info.Status != processStatusRunning || !info.Ready || info.Pid <= 0 || info.Cwd != dir {
+		t.Errorf("unexpected process info: %+v", info)
+	}
+}
+
+func TestSubscribeLogs(t *testing.T) {
+	t.Parallel()
+	svc, _ := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	batches, unsubscribe := svc.subscribeLogs()
+	defer unsubscribe()
+
+	result := svc.Start(t.TempDir(), "echo subscribed", nil, nil, nil)
+
+	deadline := time.After(2 * time.Second)
+	for {
+		select {
+		case batch := <-batches:
+			for _, log := range batch {
+				if log.ProcessID == result.ProcessID && log.Output == "subscribed\n" {
+					return
+				}
+			}
+		case <-deadline:
+			t.Fatal("expected the subscriber to receive the log line")
+		}
+	}
+}
+
+func TestStartByName(t *testing.T) {
+	t.Parallel()
+	svc, emitter := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	configPath := writeTestConfig(t, `project_name: "Start By Name"
+processes:
+  - name: "db"
+    base_command: "echo db"
+  - name: "api"
+    base_command: "echo"
+    depends_on: ["db"]
+    env:
+      GREETING: "hello"
+      TARGET: "world"
+    args:
+      - type: "input"
+        name: "Message"
+        default: "default"
+        output_prefix: "message:"
+`)
+
+	results := svc.StartByName(configPath, "api", map[string]any{"Message": "$GREETING $TARGET's"}, map[string]string{"TARGET": "you"})
+
+	if !results["db"].Success || !results["api"].Success {
+		t.Fatalf("expected db and api to start, got %+v", results)
+	}
+	apiID := results["api"].ProcessID
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, e := range emitter.getEvents() {
+			if e.name != eventProcessLogBatch {
+				continue
+			}
+			for _, log := range e.data[0].([]ProcessLogData) {
+				if log.ProcessID == apiID && log.Type == "stdout" {
+					if log.Output != "message: $GREETING $TARGET's\n" {
+						t.Fatalf("expected the input value to be passed literally, got %q", log.Output)
+					}
+					return
+				}
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("expected api output")
+}
+
+func TestStartByName_Errors(t *testing.T) {
+	t.Parallel()
+	svc, _ := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	results := svc.StartByName(filepath.Join(t.TempDir(), "missing.yml"), "api", nil, nil)
+	if results["api"].Success || !strings.Contains(results["api"].Error, "Failed to read file") {
+		t.Errorf("expected a read error, got %+v", results)
+	}
+
+	configPath := writeTestConfig(t, `project_name: "Start By Name"
+processes:
+  - name: "db"
+    base_command: "sleep 30"
+`)
+	results = svc.StartByName(configPath, "api", nil, nil)
+	if results["api"].Success || !strings.Contains(results["api"].Error, "unknown process") {
+		t.Errorf("expected an unknown process error, got %+v", results)
+	}
+}
+
+func TestStartProfile(t *testing.T) {
+	t.Parallel()
+	svc, emitter := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	configPath := writeTestConfig(t, `project_name: "Start Profile"
+processes:
+  - name: "db"
+    base_command: "echo db"
+  - name: "api"
+    base_command: "echo api $BACKEND_URL"
+    depends_on: ["db"]
+    args:
+      - type: "select"
+        name: "Mode"
+        default: "local"
+        values:
+          - value: "local"
+            output: "local"
+          - value: "staging"
+            output: "staging"
+  - name: "worker"
+    base_command: "echo worker"
+profiles:
+  staging:
+    processes: ["api"]
+    args:
+      api:
+        Mode: "staging"
+    env:
+      api:
+        BACKEND_URL: "https://staging"
+`)
+
+	results := svc.StartProfile(configPath, "staging")
+	if len(results) != 2 || !results["db"].Success || !results["api"].Success {
+		t.Fatalf("expected api to start after db, got %+v", results)
+	}
+	apiID := results["api"].ProcessID
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, e := range emitter.getEvents() {
+			if e.name != eventProcessLogBatch {
+				continue
+			}
+			for _, log := range e.data[0].([]ProcessLogData) {
+				if log.ProcessID == apiID && log.Type == "stdout" {
+					if log.Output != "api https://staging staging\n" {
+						t.Fatalf("expected the profile args and env to apply, got %q", log.Output)
+					}
+					return
+				}
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("expected api output")
+}
+
+func TestStartProfile_Errors(t *testing.T) {
+	t.Parallel()
+	svc, _ := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	configPath := writeTestConfig(t, `project_name: "Start Profile"
+processes:
+  - name: "db"
+    base_command: "sleep 30"
+`)
+	results := svc.StartProfile(configPath, "staging")
+	if results["staging"].Success || !strings.Contains(results["staging"].Error, "unknown profile") {
+		t.Errorf("expected an unknown profile error, got %+v", results)
+	}
+}
+
+func TestStartWithDependencies_ShellNoneExecutesDirectly(t *testing.T) {
+	t.Parallel()
+	svc, emitter := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	results := svc.StartWithDependencies("echo", []ProcessSpec{{
+		Name:    "echo",
+		Cwd:     t.TempDir(),
+		Command: `echo '$HOME' "a  b" | cat`,
+		Shell:   "none",
+	}})
+	if !results["echo"].Success {
+		t.Fatalf("expected start to succeed, got %+v", results)
+	}
+
+	if !emitter.waitForLogContaining("stdout") {
+		t.Fatal("expected stdout output")
+	}
+	for _, e := range emitter.getEvents() {
+		if e.name != eventProcessLogBatch {
+			continue
+		}
+		for _, log := range e.data[0].([]ProcessLogData) {
+			if log.Type == "stdout" && log.Output != "$HOME a  b | cat\n" {
+				t.Errorf("expected arguments to be passed without shell expansion, got %q", log.Output)
+			}
+		}
+	}
+}
+
+func TestNewProcessCommand(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		shell    string
+		expected []string
+	}{
+		{"", []string{"sh", "-c", "echo $HOME"}},
+		{"bash", []string{"bash", "-c", "echo $HOME"}},
+		{"zsh", []string{"zsh", "-c", "echo $HOME"}},
+		{"none", []string{"echo", "$HOME"}},
+	}
+	for _, tc := range cases {
+		cmd, err := newProcessCommand("echo $HOME", tc.shell)
+		if err != nil {
+			t.Fatalf("shell %q: unexpected error: %v", tc.shell, err)
+		}
+		if strings.Join(cmd.Args, "|") != strings.Join(tc.expected, "|") {
+			t.Errorf("shell %q: expected %q, got %q", tc.shell, tc.expected, cmd.Args)
+		}
+	}
+
+	if _, err := newProcessCommand("echo 'open", "none"); err == nil {
+		t.Error("expected an error for an unterminated quote")
+	}
+	if _, err := newProcessCommand("   ", "none"); err == nil {
+		t.Error("expected an error for an empty command")
+	}
+	if _, err := newProcessCommand("echo", "fish"); err == nil {
+		t.Error("expected an error for an unsupported shell")
+	}
+}
+
+func TestStartWithDependencies_TTY(t *testing.T) {
+	t.Parallel()
+	svc, emitter := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	results := svc.StartWithDependencies("term", []ProcessSpec{{
+		Name:    "term",
+		Cwd:     t.TempDir(),
+		Command: "test -t 1 && echo is-a-tty; stty size; echo to-stderr >&2",
+		TTY:     true,
+	}})
+	if !results["term"].Success {
+		t.Fatalf("expected start to succeed, got %+v", results)
+	}
+	if !emitter.waitForLogContaining("exit") {
+		t.Fatal("expected the process to exit")
+	}
+
+	outputs := strings.Join(emitter.logOutputs("stdout"), "")
+	expected := "is-a-tty\n30 120\nto-stderr\n"
+	if outputs != expected {
+		t.Errorf("expected %q (stderr merged, no \\r), got %q", expected, outputs)
+	}
+	if len(emitter.logOutputs("stderr")) != 0 {
+		t.Error("expected no separate stderr stream in a terminal")
+	}
+}
+
+func TestResizeTerminal(t *testing.T) {
+	t.Parallel()
+	svc, emitter := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+	dir := t.TempDir()
+
+	// Print the size on every SIGWINCH
+	results := svc.StartWithDependencies("term", []ProcessSpec{
+		{Name: "term", Cwd: dir, Command: "trap 'stty size' WINCH; echo started; while true; do sleep 0.05; done", TTY: true},
+	})
+	pipes := svc.StartWithDependencies("pipes", []ProcessSpec{{Name: "pipes", Cwd: dir, Command: "sleep 30"}})
+	id := results["term"].ProcessID
+	if !emitter.waitForLogContaining("stdout") {
+		t.Fatal("expected the process to start")
+	}
+
+	if !svc.ResizeTerminal(id, 100, 40) {
+		t.Fatal("expected the terminal to be resized")
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(strings.Join(emitter.logOutputs("stdout"), ""), "40 100") {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the process to see the new size, got %q", emitter.logOutputs("stdout"))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if svc.ResizeTerminal(pipes["pipes"].ProcessID, 100, 40) {
+		t.Error("expected processes without a terminal not to be resized")
+	}
+	if svc.ResizeTerminal(id, 0, 40) || svc.ResizeTerminal("unknown", 100, 40) {
+		t.Error("expected invalid sizes and unknown processes to be rejected")
+	}
+}
+
+func TestWriteStdin(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		tty  bool
+	}{
+		{"pipe", false},
+		{"tty", true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			svc, emitter := newTestProcessService()
+			t.Cleanup(svc.StopAll)
+
+			results := svc.StartWithDependencies("prompt", []ProcessSpec{{
+				Name:    "prompt",
+				Cwd:     t.TempDir(),
+				Command: `read answer; echo "got $answer"`,
+				TTY:     tc.tty,
+				Stdin:   true,
+			}})
+			if !results["prompt"].Success {
+				t.Fatalf("expected start to succeed, got %+v", results)
+			}
+
+			if result := svc.WriteStdin(results["prompt"].ProcessID, "hello\n"); !result.Success {
+				t.Fatalf("expected write to succeed, got %+v", result)
+			}
+			if !emitter.waitForLogContaining("exit") {
+				t.Fatal("expected the process to exit once it read its input")
+			}
+			if outputs := emitter.logOutputs("stdout"); !slices.Contains(outputs, "got hello\n") {
+				t.Errorf("expected the input to be read, got %q", outputs)
+			}
+		})
+	}
+}
+
+func TestWriteStdin_Errors(t *testing.T) {
+	t.Parallel()
+	svc, _ := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	results := svc.StartWithDependencies("closed", []ProcessSpec{{Name: "closed", Cwd: t.TempDir(), Command: "sleep 30"}})
+	result := svc.WriteStdin(results["closed"].ProcessID, "hello\n")
+	if result.Success || !strings.Contains(result.Error, "stdin: true") {
+		t.Errorf("expected processes without stdin to reject input, got %+v", result)
+	}
+	if result := svc.WriteStdin("unknown", "hello\n"); result.Success || result.Error != "Process is not running" {
+		t.Errorf("expected unknown processes to be rejected, got %+v", result)
+	}
+}
+
+func TestStop_CustomSignal(t *testing.T) {
+	t.Parallel()
+	svc, emitter := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	results := svc.StartWithDependencies("server", []ProcessSpec{{
+		Name:    "server",
+		Cwd:     t.TempDir(),
+		Command: "trap 'echo got-int; exit 0' INT; echo started; while true; do sleep 0.05; done",
+		Stop:    &StopConfig{Signal: strPtr("SIGINT")},
+	}})
+	if !emitter.waitForLogContaining("stdout") {
+		t.Fatal("expected the process to start")
+	}
+
+	if result := svc.stopAndWait(results["server"].ProcessID); !result.Success {
+		t.Fatalf("expected stop to succeed, got %+v", result)
+	}
+	if outputs := emitter.logOutputs("stdout"); !slices.Contains(outputs, "got-int\n") {
+		t.Errorf("expected the process to receive SIGINT, got %q", outputs)
+	}
+}
+
+func TestStop_RunsStopCommand(t *testing.T) {
+	t.Parallel()
+	svc, emitter := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+	dir := t.TempDir()
+
+	results := svc.StartWithDependencies("compose", []ProcessSpec{{
+		Name:    "compose",
+		Cwd:     dir,
+		Command: "sleep 30",
+		Stop:    &StopConfig{Command: strPtr("echo tearing down; touch down")},
+	}})
+
+	svc.stopAndWait(results["compose"].ProcessID)
+	if outputs := emitter.logOutputs("stdout"); !slices.Contains(outputs, "tearing down\n") {
+		t.Errorf("expected the stop command output in the logs, got %q", outputs)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "down")); err != nil {
+		t.Errorf("expected the stop command to run in the process cwd: %v", err)
+	}
+	if svc.IsRunning(results["compose"].ProcessID) {
+		t.Error("expected the process to be stopped after the stop command")
+	}
+}
+
+func TestStop_KillsAfterTimeout(t *testing.T) {
+	t.Parallel()
+	svc, emitter := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	results := svc.StartWithDependencies("stubborn", []ProcessSpec{{
+		Name:    "stubborn",
+		Cwd:     t.TempDir(),
+		Command: "trap '' TERM; echo started; while true; do sleep 0.05; done",
+		Stop:    &StopConfig{TimeoutMs: intPtr(200)},
+	}})
+	if !emitter.waitForLogContaining("stdout") {
+		t.Fatal("expected the process to start")
+	}
+
+	start := time.Now()
+	svc.stopAndWait(results["stubborn"].ProcessID)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected the process to be killed after the stop timeout, took %v", elapsed)
+	}
+	if svc.IsRunning(results["stubborn"].ProcessID) {
+		t.Error("expected the process to be killed")
+	}
+}
+
+func TestStopAll_WaitsForStopCommandsAndKills(t *testing.T) {
+	t.Parallel()
+	svc, emitter := newTestProcessService()
+	dir := t.TempDir()
+
+	svc.StartWithDependencies("compose", []ProcessSpec{{
+		Name:    "compose",
+		Cwd:     dir,
+		Command: "sleep 30",
+		Stop:    &StopConfig{Command: strPtr("sleep 0.3; touch down")},
+	}})
+	stubborn := svc.StartWithDependencies("stubborn", []ProcessSpec{{
+		Name:    "stubborn",
+		Cwd:     dir,
+		Command: "trap '' TERM; echo started; while true; do sleep 0.05; done",
+		Stop:    &StopConfig{TimeoutMs: intPtr(200)},
+	}})["stubborn"]
+	if !emitter.waitForLogContaining("stdout") {
+		t.Fatal("expected the process to start")
+	}
+	pid := svc.GetRunningProcessPids([]string{stubborn.ProcessID})[stubborn.ProcessID]
+
+	svc.StopAll()
+
+	if _, err := os.Stat(filepath.Join(dir, "down")); err != nil {
+		t.Errorf("expected StopAll to wait for the stop command: %v", err)
+	}
+	if err := syscall.Kill(pid, 0); err == nil {
+		t.Error("expected StopAll to kill the stubborn process before returning")
+	}
+	if count := svc.activeProcessCount(); count != 0 {
+		t.Errorf("expected no active process after StopAll, got %d", count)
+	}
+}
+
+func TestStartWithDependencies_LogFile(t *testing.T) {
+	t.Parallel()
+	svc, _ := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+	logPath := filepath.Join(t.TempDir(), "logs", "api.log")
+
+	results := svc.StartWithDependencies("api", []ProcessSpec{{
+		Name:    "api",
+		Cwd:     t.TempDir(),
+		Command: "echo to-stdout; echo to-stderr >&2; exit 3",
+		Logs:    &LogsConfig{File: &logPath},
+	}})
+	if !results["api"].Success {
+		t.Fatalf("expected the process to start: %s", results["api"].Error)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for svc.IsRunning(results["api"].ProcessID) && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("expected the log file to be written: %v", err)
+	}
+	for _, expected := range []string{"[stdout] to-stdout\n", "[stderr] to-stderr\n", "[exit] exited with code 3\n"} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("expected %q in the log file, got %q", expected, string(content))
+		}
+	}
+}
+
+func TestStartWithDependencies_LogFileError(t *testing.T) {
+	t.Parallel()
+	svc, _ := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+	blocker := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(blocker, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	logPath := filepath.Join(blocker, "api.log")
+
+	results := svc.StartWithDependencies("api", []ProcessSpec{{
+		Name:    "api",
+		Cwd:     t.TempDir(),
+		Command: "sleep 30",
+		Logs:    &LogsConfig{File: &logPath},
+	}})
+	if results["api"].Success || !strings.Contains(results["api"].Error, "Failed to open log file") {
+		t.Errorf("expected the start to fail on the log file, got %+v", results["api"])
+	}
+}
+
+func TestGetLogs(t *testing.T) {
+	t.Parallel()
+	svc, _ := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	results := svc.StartWithDependencies("api", []ProcessSpec{{Name: "api", Cwd: t.TempDir(), Command: "echo one; echo two >&2; echo three"}})
+	id := results["api"].ProcessID
+	deadline := time.Now().Add(5 * time.Second)
+	for svc.IsRunning(id) && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	all := svc.GetLogs(id, 0, 0)
+	if len(all.Logs) != 4 || all.Logs[3].Type != "exit" || all.HasMore {
+		t.Fatalf("expected 3 lines and the exit to outlive the process, got %+v", all)
+	}
+	page := svc.GetLogs(id, 0, 2)
+	if len(page.Logs) != 2 || !page.HasMore || page.Cursor != page.Logs[1].Cursor {
+		t.Fatalf("expected a first page of 2 entries, got %+v", page)
+	}
+	next := svc.GetLogs(id, page.Cursor, 0)
+	if len(next.Logs) != 2 || next.Logs[0].Cursor <= page.Cursor || next.Cursor != all.Cursor {
+		t.Errorf("expected the entries after the first page, got %+v", next)
+	}
+	if caughtUp := svc.GetLogs(id, all.Cursor, 0); len(caughtUp.Logs) != 0 || caughtUp.Cursor != all.Cursor {
+		t.Errorf("expected no entries after the last cursor, got %+v", caughtUp)
+	}
+	if unknown := svc.GetLogs("unknown", 0, 0); unknown.Logs == nil || len(unknown.Logs) != 0 {
+		t.Errorf("expected no entries for an unknown process, got %+v", unknown)
+	}
+
+	// A new run of the same process replaces the history of the previous one
+	svc.StartWithDependencies("api", []ProcessSpec{{Name: "api", Cwd: t.TempDir(), Command: "sleep 30"}})
+	if dropped := svc.GetLogs(id, 0, 0); len(dropped.Logs) != 0 {
+		t.Errorf("expected the previous run to be dropped, got %+v", dropped)
+	}
+}
+
+func TestGetLogs_DropsUnnamedProcessesOnExit(t *testing.T) {
+	t.Parallel()
+	svc, emitter := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	unnamed := svc.Start(t.TempDir(), "echo unnamed", nil, nil, nil)
+	named := svc.StartWithDependencies("api", []ProcessSpec{{Name: "api", Cwd: t.TempDir(), Command: "echo named"}})["api"]
+	deadline := time.Now().Add(5 * time.Second)
+	for len(emitter.logOutputs("exit")) < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	// The buffer is dropped right after the exit is emitted
+	for len(svc.GetLogs(unnamed.ProcessID, 0, 0).Logs) > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if logs := svc.GetLogs(unnamed.ProcessID, 0, 0).Logs; len(logs) != 0 {
+		t.Errorf("expected the logs of the exited unnamed process to be dropped, got %d", len(logs))
+	}
+	if logs := svc.GetLogs(named.ProcessID, 0, 0).Logs; len(logs) == 0 {
+		t.Error("expected the logs of the exited named process to be kept")
+	}
+}
+
+func TestSearchLogs(t *testing.T) {
+	t.Parallel()
+	svc, _ := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	results := svc.StartWithDependencies("api", []ProcessSpec{{Name: "api", Cwd: t.TempDir(), Command: "echo 'GET /users 200'; echo 'GET /posts 500' >&2; echo 'POST /users 201'; sleep 30"}})
+	id := results["api"].ProcessID
+	deadline := time.Now().Add(5 * time.Second)
+	for len(svc.GetLogs(id, 0, 0).Logs) < 3 && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if found := svc.SearchLogs(id, `/users \d+`, nil); len(found.Logs) != 2 || found.Error != "" {
+		t.Errorf("expected the two /users lines, got %+v", found)
+	}
+	if found := svc.SearchLogs(id, `GET`, []string{"stderr"}); len(found.Logs) != 1 || found.Logs[0].Output != "GET /posts 500\n" {
+		t.Errorf("expected the stderr GET line only, got %+v", found)
+	}
+	if found := svc.SearchLogs(id, `(`, nil); !strings.Contains(found.Error, "Invalid regular expression") {
+		t.Errorf("expected an invalid regex error, got %+v", found)
+	}
+}
+
+func TestStartWithDependencies_LogFormat(t *testing.T) {
+	t.Parallel()
+	svc, _ := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	results := svc.StartWithDependencies("api", []ProcessSpec{{
+		Name:      "api",
+		Cwd:       t.TempDir(),
+		Command:   `echo '{"level":"warn","msg":"disk almost full","used":0.93}'; echo 'not json'`,
+		LogFormat: "json",
+	}})
+	id := results["api"].ProcessID
+	deadline := time.Now().Add(5 * time.Second)
+	for svc.IsRunning(id) && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	logs := svc.GetLogs(id, 0, 0).Logs
+	if len(logs) < 2 {
+		t.Fatalf("expected 2 lines, got %+v", logs)
+	}
+	if logs[0].Level != "warn" || logs[0].Output != "disk almost full\n" || formatLogFields(logs[0].Fields) != "used=0.93" {
+		t.Errorf("expected the JSON line to be parsed, got %+v", logs[0])
+	}
+	if logs[1].Level != "" || logs[1].Output != "not json\n" || logs[1].Fields != nil {
+		t.Errorf("expected the text line to be kept as is, got %+v", logs[1])
+	}
+}
+
+func TestStartWithDependencies_Multiline(t *testing.T) {
+	t.Parallel()
+	svc, _ := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	results := svc.StartWithDependencies("api", []ProcessSpec{{
+		Name:      "api",
+		Cwd:       t.TempDir(),
+		Command:   `printf 'Traceback:\n  File "app.py"\nValueError: boom\n'`,
+		Multiline: &MultilineConfig{},
+	}})
+	id := results["api"].ProcessID
+	deadline := time.Now().Add(5 * time.Second)
+	for svc.IsRunning(id) && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	logs := svc.GetLogs(id, 0, 0).Logs
+	if len(logs) != 3 || logs[0].Output != "Traceback:\n  File \"app.py\"\n" || logs[1].Output != "ValueError: boom\n" || logs[2].Type != "exit" {
+		t.Errorf("expected the indented line to be grouped and flushed before the exit, got %+v", logs)
+	}
+}
+
+func TestStartWithDependencies_Alerts(t *testing.T) {
+	t.Parallel()
+	svc, emitter := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	notify := true
+	results := svc.StartWithDependencies("api", []ProcessSpec{{
+		Name:    "api",
+		Cwd:     t.TempDir(),
+		Command: `echo "starting"; echo "ERROR: database unreachable"; echo "ERROR: retrying"`,
+		Alerts:  []AlertConfig{{Pattern: "ERROR", Notify: &notify}},
+	}})
+	id := results["api"].ProcessID
+	deadline := time.Now().Add(5 * time.Second)
+	for svc.IsRunning(id) && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	var alerts []ProcessAlertData
+	for _, e := range emitter.getEvents() {
+		if e.name == "process-alert" {
+			alerts = append(alerts, e.data[0].(ProcessAlertData))
+		}
+	}
+	// The second match falls within the cooldown of the rule
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %+v", alerts)
+	}
+	alert := alerts[0]
+	if alert.ProcessID != id || alert.Name != "api" || alert.Level != "error" || !alert.Notify || alert.Line != "ERROR: database unreachable" {
+		t.Errorf("unexpected alert: %+v", alert)
+	}
+}
+
+func TestStartWithDependencies_PartialLines(t *testing.T) {
+	t.Parallel()
+	svc, _ := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	results := svc.StartWithDependencies("api", []ProcessSpec{{
+		Name:    "api",
+		Cwd:     t.TempDir(),
+		Command: `printf 'Continue? '; sleep 0.5; echo yes`,
+	}})
+	id := results["api"].ProcessID
+	deadline := time.Now().Add(5 * time.Second)
+	for svc.IsRunning(id) && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	logs := svc.GetLogs(id, 0, 0).Logs
+	if len(logs) != 3 {
+		t.Fatalf("expected a preview, the complete line and the exit, got %+v", logs)
+	}
+	if logs[0].Output != "Continue? \n" || !logs[0].Partial || logs[0].Replace {
+		t.Errorf("expected the prompt to be flushed before its newline, got %+v", logs[0])
+	}
+	if logs[1].Output != "Continue? yes\n" || logs[1].Partial || !logs[1].Replace {
+		t.Errorf("expected the complete line to replace the preview, got %+v", logs[1])
+	}
+}
+
+func TestStartWithDependencies_PartialLinesInterleaved(t *testing.T) {
+	t.Parallel()
+	svc, _ := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	results := svc.StartWithDependencies("api", []ProcessSpec{{
+		Name:    "api",
+		Cwd:     t.TempDir(),
+		Command: `printf 'progress'; sleep 0.3; echo warning >&2; sleep 0.1; echo ' done'`,
+	}})
+	id := results["api"].ProcessID
+	deadline := time.Now().Add(5 * time.Second)
+	for svc.IsRunning(id) && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	logs := svc.GetLogs(id, 0, 0).Logs
+	if len(logs) != 4 {
+		t.Fatalf("expected a preview, the stderr line, the complete line and the exit, got %+v", logs)
+	}
+	if logs[0].Type != "stdout" || !logs[0].Partial {
+		t.Errorf("expected the stdout preview first, got %+v", logs[0])
+	}
+	if logs[1].Type != "stderr" || logs[1].Output != "warning\n" || logs[1].Replace {
+		t.Errorf("expected the stderr line to be a new line, got %+v", logs[1])
+	}
+	// The replace entry is for the stdout preview, not the stderr line just before it
+	if logs[2].Type != "stdout" || logs[2].Output != "progress done\n" || !logs[2].Replace {
+		t.Errorf("expected the complete stdout line to replace its preview, got %+v", logs[2])
+	}
+}
+
+func TestStartWithDependencies_SuppressedPreview(t *testing.T) {
+	t.Parallel()
+	svc, _ := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	rate := 2
+	results := svc.StartWithDependencies("api", []ProcessSpec{{
+		Name:         "api",
+		Cwd:          t.TempDir(),
+		Command:      `echo one; echo two; printf 'loading'; sleep 0.8; echo ' done'`,
+		LogRateLimit: &rate,
+	}})
+	id := results["api"].ProcessID
+	deadline := time.Now().Add(5 * time.Second)
+	for svc.IsRunning(id) && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	var line *ProcessLogData
+	for _, log := range svc.GetLogs(id, 0, 0).Logs {
+		if log.Partial {
+			t.Fatalf("expected the preview to be suppressed by the rate limit, got %+v", log)
+		}
+		if log.Output == "loading done\n" {
+			line = &log
+		}
+	}
+	if line == nil {
+		t.Fatal("expected the complete line to be emitted")
+	}
+	if line.Replace {
+		t.Errorf("expected the line to not replace its suppressed preview, got %+v", *line)
+	}
+}
+
+func TestQueueLog_BatchSizePerProcess(t *testing.T) {
+	t.Parallel()
+	svc, _ := newTestProcessService()
+
+	rate := 2 * maxLogBatchSize
+	flooding := newLogRateLimiter(&rate)
+	quiet := newLogRateLimiter(nil)
+	for range maxLogBatchSize + 100 {
+		svc.queueLog(ProcessLogData{ProcessID: "flooding", Type: "stdout", Output: "flood\n"}, nil, flooding)
+	}
+	for range 10 {
+		if !svc.queueLog(ProcessLogData{ProcessID: "quiet", Type: "stdout", Output: "line\n"}, nil, quiet) {
+			t.Fatal("expected the lines of a quiet process to be queued while another one floods")
+		}
+	}
+
+	counts := map[string]int{}
+	for _, log := range svc.pendingLogs {
+		counts[log.ProcessID]++
+	}
+	if counts["flooding"] != maxLogBatchSize || counts["quiet"] != 10 {
+		t.Errorf("expected %d flooding and 10 quiet entries, got %v", maxLogBatchSize, counts)
+	}
+	if flooding.suppressed != 100 || quiet.suppressed != 0 {
+		t.Errorf("expected 100 suppressed flooding entries, got %d flooding and %d quiet", flooding.suppressed, quiet.suppressed)
+	}
+
+	svc.flushLogs()
+	if !svc.queueLog(ProcessLogData{ProcessID: "flooding", Type: "stdout", Output: "flood\n"}, nil, flooding) {
+		t.Error("expected the batch size to be reset once the batch is flushed")
+	}
+}
+
+func TestStartWithDependencies_LogRateLimit(t *testing.T) {
+	t.Parallel()
+	svc, _ := newTestProcessService()
+	t.Cleanup(svc.StopAll)
+
+	rate := 100
+	results := svc.StartWithDependencies("api", []ProcessSpec{{
+		Name:         "api",
+		Cwd:          t.TempDir(),
+		Command:      "seq 1 5000",
+		LogRateLimit: &rate,
+	}})
+	id := results["api"].ProcessID
+	deadline := time.Now().Add(5 * time.Second)
+	for svc.IsRunning(id) && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	logs := svc.GetLogs(id, 0, 0).Logs
+	lines, suppressed := 0, 0
+	for _, log := range logs {
+		switch {
+		case log.Suppressed > 0:
+			suppressed += log.Suppressed
+		case log.Type == "stdout":
+			lines++
+		}
+	}
+	if lines == 0 || lines >= 5000 || lines+suppressed != 5000 {
+		t.Errorf("expected every line to be either emitted or counted as suppressed, got %d and %d", lines, suppressed)
+	}
+	if len(logs) < 2 || logs[len(logs)-2].Suppressed == 0 || logs[len(logs)-1].Type != "exit" {
+		t.Errorf("expected the suppressed lines to be reported before the exit, got %+v", logs[max(len(logs)-2, 0):])
+	}
+}
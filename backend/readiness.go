@@ -0,0 +1,150 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultReadyTimeoutMs  = 60_000
+	defaultReadyIntervalMs = 500
+	probeAttemptTimeoutMs  = 2000
+)
+
+// readinessProbe tracks the readiness checks of a single spawned process.
+// Log matching is fed by streamOutput; the other checks are polled by waitUntilReady.
+type readinessProbe struct {
+	cfg        ReadyConfig
+	cwd        string
+	env        []string
+	logPattern *regexp.Regexp
+	logMatched chan struct{}
+	matchOnce  sync.Once
+}
+
+// newReadinessProbe compiles a probe from config. Returns nil when no readiness config is set.
+func newReadinessProbe(cfg *ReadyConfig, cwd string, env []string) (*readinessProbe, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	probe := &readinessProbe{cfg: *cfg, cwd: cwd, env: env}
+	if cfg.LogMatch != nil {
+		pattern, err := regexp.Compile(*cfg.LogMatch)
+		if err != nil {
+			return nil, fmt.Errorf("compiling ready_when.log_match: %w", err)
+		}
+		probe.logPattern = pattern
+		probe.logMatched = make(chan struct{})
+	}
+	return probe, nil
+}
+
+// observeLine records a log line for the log_match check.
+func (p *readinessProbe) observeLine(line string) {
+	if p == nil || p.logPattern == nil {
+		return
+	}
+	if p.logPattern.MatchString(line) {
+		p.matchOnce.Do(func() { close(p.logMatched) })
+	}
+}
+
+// check runs every configured check once. All of them must pass.
+func (p *readinessProbe) check(ctx context.Context) bool {
+	if p.logPattern != nil {
+		select {
+		case <-p.logMatched:
+		default:
+			return false
+		}
+	}
+	if p.cfg.TCP != nil && !checkTCP(ctx, *p.cfg.TCP) {
+		return false
+	}
+	if p.cfg.HTTP != nil && !checkHTTP(ctx, *p.cfg.HTTP) {
+		return false
+	}
+	if p.cfg.Command != nil && !checkCommand(ctx, *p.cfg.Command, p.cwd, p.env) {
+		return false
+	}
+	return true
+}
+
+// waitUntilReady polls the checks until they all pass, the timeout elapses, or exited is closed.
+// Returns true once the process is ready.
+func (p *readinessProbe) waitUntilReady(exited <-chan struct{}) bool {
+	timeoutMs := defaultReadyTimeoutMs
+	if p.cfg.TimeoutMs != nil {
+		timeoutMs = *p.cfg.TimeoutMs
+	}
+	intervalMs := defaultReadyIntervalMs
+	if p.cfg.IntervalMs != nil {
+		intervalMs = *p.cfg.IntervalMs
+	}
+
+	deadline := time.NewTimer(time.Duration(timeoutMs) * time.Millisecond)
+	defer deadline.Stop()
+	ticker := time.NewTicker(time.Duration(intervalMs) * time.Millisecond)
+	defer ticker.Stop()
+
+	// Wake up as soon as the log pattern matches, then fall back to the ticker.
+	// A nil channel never fires, which covers both "no log_match" and "already matched".
+	logMatched := p.logMatched
+	for {
+		if p.checkOnce() {
+			return true
+		}
+		select {
+		case <-exited:
+			return false
+		case <-deadline.C:
+			return false
+		case <-ticker.C:
+		case <-logMatched:
+			logMatched = nil
+		}
+	}
+}
+
+func (p *readinessProbe) checkOnce() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), probeAttemptTimeoutMs*time.Millisecond)
+	defer cancel()
+	return p.check(ctx)
+}
+
+func checkTCP(ctx context.Context, port int) bool {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
+
+func checkHTTP(ctx context.Context, url string) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false
+	}
+	resp, err := http.DefaultClient.Do(req) //nolint:gosec // user-configured probe URL
+	if err != nil {
+		return false
+	}
+	_ = resp.Body.Close()
+	return resp.StatusCode >= 200 && resp.StatusCode < 400
+}
+
+func checkCommand(ctx context.Context, command string, cwd string, env []string) bool {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command) //nolint:gosec // user-configured probe command
+	cmd.Dir = cwd
+	cmd.Env = env
+	return cmd.Run() == nil
+}
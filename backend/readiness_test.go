@@ -0,0 +1,139 @@
+package backend
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestNewReadinessProbe(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil config returns nil probe", func(t *testing.T) {
+		t.Parallel()
+		probe, err := newReadinessProbe(nil, "", nil)
+		if err != nil || probe != nil {
+			t.Fatalf("got (%v, %v), want (nil, nil)", probe, err)
+		}
+	})
+
+	t.Run("invalid log pattern returns error", func(t *testing.T) {
+		t.Parallel()
+		_, err := newReadinessProbe(&ReadyConfig{LogMatch: strPtr("(")}, "", nil)
+		if err == nil {
+			t.Fatal("expected error for invalid pattern")
+		}
+	})
+}
+
+func TestReadinessProbeChecks(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+	openPort := listener.Addr().(*net.TCPAddr).Port
+
+	closedListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedPort := closedListener.Addr().(*net.TCPAddr).Port
+	_ = closedListener.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		name string
+		cfg  ReadyConfig
+		want bool
+	}{
+		{name: "tcp open", cfg: ReadyConfig{TCP: intPtr(openPort)}, want: true},
+		{name: "tcp closed", cfg: ReadyConfig{TCP: intPtr(closedPort)}, want: false},
+		{name: "http ok", cfg: ReadyConfig{HTTP: strPtr(server.URL + "/health")}, want: true},
+		{name: "http unavailable", cfg: ReadyConfig{HTTP: strPtr(server.URL + "/other")}, want: false},
+		{name: "command success", cfg: ReadyConfig{Command: strPtr("true")}, want: true},
+		{name: "command failure", cfg: ReadyConfig{Command: strPtr("false")}, want: false},
+		{name: "all checks must pass", cfg: ReadyConfig{TCP: intPtr(openPort), Command: strPtr("false")}, want: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			probe, err := newReadinessProbe(&tc.cfg, t.TempDir(), os.Environ())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := probe.checkOnce(); got != tc.want {
+				t.Errorf("checkOnce() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestReadinessProbeLogMatch(t *testing.T) {
+	t.Parallel()
+
+	probe, err := newReadinessProbe(&ReadyConfig{LogMatch: strPtr(`listening on \d+`)}, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	probe.observeLine("starting up")
+	if probe.checkOnce() {
+		t.Fatal("expected probe to not be ready before a matching line")
+	}
+
+	probe.observeLine("listening on 3000")
+	probe.observeLine("listening on 3001") // second match must not panic on double close
+	if !probe.checkOnce() {
+		t.Fatal("expected probe to be ready after a matching line")
+	}
+}
+
+func TestReadinessProbeWaitUntilReady(t *testing.T) {
+	t.Parallel()
+
+	t.Run("times out", func(t *testing.T) {
+		t.Parallel()
+		probe, _ := newReadinessProbe(&ReadyConfig{
+			Command:    strPtr("false"),
+			TimeoutMs:  intPtr(100),
+			IntervalMs: intPtr(10),
+		}, t.TempDir(), os.Environ())
+
+		start := time.Now()
+		if probe.waitUntilReady(make(chan struct{})) {
+			t.Fatal("expected timeout")
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("waitUntilReady took %v, expected ~100ms", elapsed)
+		}
+	})
+
+	t.Run("stops when process exits", func(t *testing.T) {
+		t.Parallel()
+		probe, _ := newReadinessProbe(&ReadyConfig{Command: strPtr("false")}, t.TempDir(), os.Environ())
+		exited := make(chan struct{})
+		close(exited)
+		if probe.waitUntilReady(exited) {
+			t.Fatal("expected not ready after exit")
+		}
+	})
+}
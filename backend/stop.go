@@ -0,0 +1,93 @@
+package backend
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultStopSignal = "SIGTERM"
+	// Extra time to wait for a process after its SIGKILL, so that it is always reaped
+	processStopWaitMarginMs = 1000
+)
+
+// Signals accepted by stop.signal.
+var stopSignals = map[string]syscall.Signal{
+	"SIGINT":  syscall.SIGINT,
+	"SIGTERM": syscall.SIGTERM,
+	"SIGQUIT": syscall.SIGQUIT,
+	"SIGHUP":  syscall.SIGHUP,
+	"SIGKILL": syscall.SIGKILL,
+	"SIGUSR1": syscall.SIGUSR1,
+	"SIGUSR2": syscall.SIGUSR2,
+}
+
+// stopPolicy is how a process is stopped: an optional teardown command, then a signal to its
+// process group, then SIGKILL once timeout has elapsed since the stop began.
+type stopPolicy struct {
+	signal  syscall.Signal
+	timeout time.Duration
+	command string
+}
+
+// newStopPolicy applies defaults to a stop config (nil for defaults).
+func newStopPolicy(cfg *StopConfig) stopPolicy {
+	policy := stopPolicy{
+		signal:  stopSignals[defaultStopSignal],
+		timeout: processKillTimeoutMs * time.Millisecond,
+	}
+	if cfg == nil {
+		return policy
+	}
+	if cfg.Signal != nil {
+		if sig, ok := stopSignals[*cfg.Signal]; ok {
+			policy.signal = sig
+		}
+	}
+	if cfg.TimeoutMs != nil {
+		policy.timeout = time.Duration(*cfg.TimeoutMs) * time.Millisecond
+	}
+	if cfg.Command != nil {
+		policy.command = *cfg.Command
+	}
+	return policy
+}
+
+// waitTimeout is how long to wait for a stopped process to exit.
+func (p stopPolicy) waitTimeout() time.Duration {
+	return p.timeout + processStopWaitMarginMs*time.Millisecond
+}
+
+// runCommand runs the teardown command in the process cwd and env, within the stop timeout.
+// Returns its combined output, split into lines.
+func (p stopPolicy) runCommand(cwd string, env []string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "sh", "-c", p.command) //nolint:gosec // user-configured stop command
+	cmd.Dir = cwd
+	cmd.Env = env
+	// Kill the whole group on timeout, or children holding the output pipe would keep us waiting
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error { return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL) }
+	output, err := cmd.CombinedOutput()
+	trimmed := strings.TrimRight(string(output), "\n")
+	if trimmed == "" {
+		return nil, err
+	}
+	return strings.Split(trimmed, "\n"), err
+}
+
+// maxStopWaitTimeout returns the longest time any process of a config may take to stop.
+func maxStopWaitTimeout(config *YamlConfig) time.Duration {
+	longest := newStopPolicy(nil).waitTimeout()
+	if config == nil {
+		return longest
+	}
+	for _, p := range config.Processes {
+		longest = max(longest, newStopPolicy(p.Stop).waitTimeout())
+	}
+	return longest
+}
@@ -0,0 +1,61 @@
+package backend
+
+import (
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestNewStopPolicy(t *testing.T) {
+	t.Parallel()
+
+	defaults := newStopPolicy(nil)
+	if defaults.signal != syscall.SIGTERM || defaults.timeout != processKillTimeoutMs*time.Millisecond || defaults.command != "" {
+		t.Errorf("unexpected defaults: %+v", defaults)
+	}
+
+	policy := newStopPolicy(&StopConfig{Signal: strPtr("SIGINT"), TimeoutMs: intPtr(500), Command: strPtr("docker compose down")})
+	if policy.signal != syscall.SIGINT || policy.timeout != 500*time.Millisecond || policy.command != "docker compose down" {
+		t.Errorf("unexpected policy: %+v", policy)
+	}
+	if policy.waitTimeout() != 1500*time.Millisecond {
+		t.Errorf("waitTimeout() = %v, want 1.5s", policy.waitTimeout())
+	}
+}
+
+func TestStopPolicy_RunCommand(t *testing.T) {
+	t.Parallel()
+
+	policy := newStopPolicy(&StopConfig{Command: strPtr("echo \"down in $PWD\"; echo $STOP_ENV >&2")})
+	dir := t.TempDir()
+	lines, err := policy.runCommand(dir, append(os.Environ(), "STOP_ENV=from-env"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(lines) != 2 || !strings.HasSuffix(lines[0], dir) || lines[1] != "from-env" {
+		t.Errorf("expected the output of the command run in the process cwd and env, got %q", lines)
+	}
+
+	slow := newStopPolicy(&StopConfig{Command: strPtr("sleep 5"), TimeoutMs: intPtr(100)})
+	start := time.Now()
+	if _, err := slow.runCommand(dir, os.Environ()); err == nil || time.Since(start) > 2*time.Second {
+		t.Errorf("expected the command to be killed at the stop timeout, got %v after %v", err, time.Since(start))
+	}
+}
+
+func TestMaxStopWaitTimeout(t *testing.T) {
+	t.Parallel()
+
+	config := &YamlConfig{Processes: []ProcessConfig{
+		{Name: "fast", Stop: &StopConfig{TimeoutMs: intPtr(100)}},
+		{Name: "slow", Stop: &StopConfig{TimeoutMs: intPtr(60_000)}},
+	}}
+	if got := maxStopWaitTimeout(config); got != 61*time.Second {
+		t.Errorf("maxStopWaitTimeout() = %v, want 61s", got)
+	}
+	if got := maxStopWaitTimeout(nil); got != newStopPolicy(nil).waitTimeout() {
+		t.Errorf("expected the default wait timeout without a config, got %v", got)
+	}
+}
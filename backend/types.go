@@ -1,9 +1,17 @@
 package backend
 
 // ValidationError represents a single validation error with an optional path.
+// File is set for errors of an included file, relative to the opened config.
 type ValidationError struct {
 	Message string `json:"message"`
 	Path    string `json:"path,omitempty"`
+	File    string `json:"file,omitempty"`
+	// Line and Column locate the error in the config file (1-based), when known
+	Line   int `json:"line,omitempty"`
+	Column int `json:"column,omitempty"`
+	// field is the path of the value the error is about, when it is under Path (e.g. processes[0].tty
+	// for "tty must be a boolean" at processes[0]). Only used to locate the error.
+	field string
 }
 
 // ValidationResult represents the result of YAML config validation.
@@ -12,24 +20,76 @@ type ValidationResult struct {
 	Config        *YamlConfig       `json:"config"`
 	Errors        []ValidationError `json:"errors"`
 	RootDirectory string            `json:"rootDirectory,omitempty"`
+	// IncludedFiles are the absolute paths of the files included by the config
+	IncludedFiles []string `json:"includedFiles,omitempty"`
+}
+
+// ConfigDiff lists the processes added, removed and modified between two versions of a config, by name.
+type ConfigDiff struct {
+	Added    []string      `json:"added"`
+	Removed  []string      `json:"removed"`
+	Modified []ProcessDiff `json:"modified"`
+}
+
+// ProcessDiff lists the fields (by YAML key) of a process that changed.
+// RestartRequired is set when a running process must be restarted for them to apply.
+type ProcessDiff struct {
+	Name            string   `json:"name"`
+	Fields          []string `json:"fields"`
+	RestartRequired bool     `json:"restartRequired"`
+}
+
+// ConfigChangedData is emitted when the watched config or one of its env files changes.
+// The diff is empty when the new config is invalid, and then compares to the last valid one.
+type ConfigChangedData struct {
+	Result ValidationResult `json:"result"`
+	Diff   ConfigDiff       `json:"diff"`
 }
 
 // YamlConfig represents the parsed YAML configuration.
+// Include lists glob patterns of files whose processes are merged into Processes, relative to the config.
+// Defaults and Templates are already applied to Processes: every process is merged over Defaults,
+// and over the template it extends. Vars and the built-in variables are already interpolated in Processes.
+// EnvFile lists project-level env files (relative to the config), already prepended to the env files of every process.
 type YamlConfig struct {
-	ProjectName string          `json:"project_name" yaml:"project_name"`
-	Processes   []ProcessConfig `json:"processes" yaml:"processes"`
+	ProjectName string                   `json:"project_name" yaml:"project_name"`
+	Include     []string                 `json:"include,omitempty" yaml:"include,omitempty"`
+	EnvFile     EnvFiles                 `json:"env_file,omitempty" yaml:"env_file,omitempty"`
+	Vars        map[string]string        `json:"vars,omitempty" yaml:"vars,omitempty"`
+	Profiles    map[string]ProfileConfig `json:"profiles,omitempty" yaml:"profiles,omitempty"`
+	Defaults    *ProcessConfig           `json:"defaults,omitempty" yaml:"defaults,omitempty"`
+	Templates   map[string]ProcessConfig `json:"templates,omitempty" yaml:"templates,omitempty"`
+	Processes   []ProcessConfig          `json:"processes" yaml:"processes,omitempty"`
 }
 
+// EnvFiles lists the env files of a process, loaded in order: later files override earlier ones.
+// Paths are relative to the process cwd, and end with "?" for files that may not exist.
+type EnvFiles []string
+
 // ProcessConfig represents a single process definition.
 type ProcessConfig struct {
-	Name        string            `json:"name" yaml:"name"`
-	BaseCommand string            `json:"base_command" yaml:"base_command"`
-	Group       *string           `json:"group,omitempty" yaml:"group,omitempty"`
-	Cwd         *string           `json:"cwd,omitempty" yaml:"cwd,omitempty"`
-	EnvFile     *string           `json:"env_file,omitempty" yaml:"env_file,omitempty"`
-	Env         map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
-	Restart     *RestartConfig    `json:"restart,omitempty" yaml:"restart,omitempty"`
-	Args        []ArgConfig       `json:"args,omitempty" yaml:"args,omitempty"`
+	Name         string            `json:"name" yaml:"name"`
+	Extends      *string           `json:"extends,omitempty" yaml:"extends,omitempty"`
+	BaseCommand  string            `json:"base_command,omitempty" yaml:"base_command,omitempty"`
+	Command      []string          `json:"command,omitempty" yaml:"command,omitempty"`
+	Shell        *string           `json:"shell,omitempty" yaml:"shell,omitempty"`
+	TTY          *bool             `json:"tty,omitempty" yaml:"tty,omitempty"`
+	Stdin        *bool             `json:"stdin,omitempty" yaml:"stdin,omitempty"`
+	Group        *string           `json:"group,omitempty" yaml:"group,omitempty"`
+	Cwd          *string           `json:"cwd,omitempty" yaml:"cwd,omitempty"`
+	EnvFile      EnvFiles          `json:"env_file,omitempty" yaml:"env_file,omitempty"`
+	Env          map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
+	Restart      *RestartConfig    `json:"restart,omitempty" yaml:"restart,omitempty"`
+	Stop         *StopConfig       `json:"stop,omitempty" yaml:"stop,omitempty"`
+	Logs         *LogsConfig       `json:"logs,omitempty" yaml:"logs,omitempty"`
+	LogFormat    *string           `json:"log_format,omitempty" yaml:"log_format,omitempty"`
+	LogRateLimit *int              `json:"log_rate_limit,omitempty" yaml:"log_rate_limit,omitempty"`
+	Multiline    *MultilineConfig  `json:"multiline,omitempty" yaml:"multiline,omitempty"`
+	Alerts       []AlertConfig     `json:"alerts,omitempty" yaml:"alerts,omitempty"`
+	DependsOn    []string          `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
+	ReadyWhen    *ReadyConfig      `json:"ready_when,omitempty" yaml:"ready_when,omitempty"`
+	Healthcheck  *HealthConfig     `json:"healthcheck,omitempty" yaml:"healthcheck,omitempty"`
+	Args         []ArgConfig       `json:"args,omitempty" yaml:"args,omitempty"`
 }
 
 // RestartConfig defines auto-restart behavior.
@@ -40,6 +100,58 @@ type RestartConfig struct {
 	ResetAfterMs *int `json:"reset_after_ms,omitempty" yaml:"reset_after_ms,omitempty"`
 }
 
+// StopConfig defines how a process is stopped. Command runs first (e.g. "docker compose down"),
+// then Signal is sent to the process group, and SIGKILL once TimeoutMs has elapsed since the stop began.
+type StopConfig struct {
+	Signal    *string `json:"signal,omitempty" yaml:"signal,omitempty"`
+	TimeoutMs *int    `json:"timeout_ms,omitempty" yaml:"timeout_ms,omitempty"`
+	Command   *string `json:"command,omitempty" yaml:"command,omitempty"`
+}
+
+// MultilineConfig groups continuation lines (e.g. stack traces) into the log entry they follow.
+// Lines not matching StartPattern are continuations; without it, indented lines are.
+type MultilineConfig struct {
+	StartPattern *string `json:"start_pattern,omitempty" yaml:"start_pattern,omitempty"`
+	TimeoutMs    *int    `json:"timeout_ms,omitempty" yaml:"timeout_ms,omitempty"`
+}
+
+// AlertConfig raises a process-alert event when a log entry matches Pattern.
+// Level is info, warn or error (default); Notify asks the UI for a notification.
+type AlertConfig struct {
+	Pattern string  `json:"pattern" yaml:"pattern"`
+	Level   *string `json:"level,omitempty" yaml:"level,omitempty"`
+	Notify  *bool   `json:"notify,omitempty" yaml:"notify,omitempty"`
+}
+
+// LogsConfig defines where the logs of a process are persisted. The file is rotated once it
+// reaches MaxSizeMB, and MaxFiles rotated files are kept.
+type LogsConfig struct {
+	File      *string `json:"file,omitempty" yaml:"file,omitempty"`
+	MaxSizeMB *int    `json:"max_size_mb,omitempty" yaml:"max_size_mb,omitempty"`
+	MaxFiles  *int    `json:"max_files,omitempty" yaml:"max_files,omitempty"`
+}
+
+// ReadyConfig defines the checks that must all pass before a process is considered ready.
+type ReadyConfig struct {
+	TCP        *int    `json:"tcp,omitempty" yaml:"tcp,omitempty"`
+	HTTP       *string `json:"http,omitempty" yaml:"http,omitempty"`
+	LogMatch   *string `json:"log_match,omitempty" yaml:"log_match,omitempty"`
+	Command    *string `json:"command,omitempty" yaml:"command,omitempty"`
+	TimeoutMs  *int    `json:"timeout_ms,omitempty" yaml:"timeout_ms,omitempty"`
+	IntervalMs *int    `json:"interval_ms,omitempty" yaml:"interval_ms,omitempty"`
+}
+
+// HealthConfig defines a periodic liveness check. The process is killed, and goes through the
+// restart policy, after FailureThreshold consecutive failures.
+type HealthConfig struct {
+	TCP              *int    `json:"tcp,omitempty" yaml:"tcp,omitempty"`
+	HTTP             *string `json:"http,omitempty" yaml:"http,omitempty"`
+	Command          *string `json:"command,omitempty" yaml:"command,omitempty"`
+	IntervalMs       *int    `json:"interval_ms,omitempty" yaml:"interval_ms,omitempty"`
+	TimeoutMs        *int    `json:"timeout_ms,omitempty" yaml:"timeout_ms,omitempty"`
+	FailureThreshold *int    `json:"failure_threshold,omitempty" yaml:"failure_threshold,omitempty"`
+}
+
 // ArgConfig represents a configurable argument.
 type ArgConfig struct {
 	Type         string     `json:"type" yaml:"type"`
@@ -55,6 +167,52 @@ type ArgValue struct {
 	Output string `json:"output" yaml:"output"`
 }
 
+// ProfileConfig is a named set of processes launched together (with their dependencies).
+// Args (process name → arg name → value) and Env (process name → env) override the config values.
+type ProfileConfig struct {
+	Description *string                      `json:"description,omitempty" yaml:"description,omitempty"`
+	Processes   []string                     `json:"processes" yaml:"processes"`
+	Args        map[string]map[string]any    `json:"args,omitempty" yaml:"args,omitempty"`
+	Env         map[string]map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
+}
+
+// ProfileInfo describes a profile of the config, as listed by ListProfiles.
+type ProfileInfo struct {
+	Name        string                       `json:"name"`
+	Description string                       `json:"description,omitempty"`
+	Processes   []string                     `json:"processes"`
+	Args        map[string]map[string]any    `json:"args,omitempty"`
+	Env         map[string]map[string]string `json:"env,omitempty"`
+}
+
+// ProcessSpec is a fully resolved launch request for a named process.
+// Used by dependency-aware start/stop, where the backend needs every process in the chain.
+// Shell runs Command with sh (default), bash or zsh; "none" splits it into words and executes it directly.
+// TTY runs it in a pseudo-terminal instead of stdout/stderr pipes. Stdin accepts input from WriteStdin.
+// Logs.File is an absolute path. LogFormat parses each output line as json or logfmt (text by default).
+// LogRateLimit caps the log entries per second sent to the UI (1000 by default).
+// EnvFiles are loaded in order, relative to Cwd, and end with "?" when they may not exist.
+type ProcessSpec struct {
+	Name         string            `json:"name"`
+	Cwd          string            `json:"cwd"`
+	Command      string            `json:"command"`
+	Shell        string            `json:"shell,omitempty"`
+	TTY          bool              `json:"tty,omitempty"`
+	Stdin        bool              `json:"stdin,omitempty"`
+	Restart      *RestartConfig    `json:"restart,omitempty"`
+	Stop         *StopConfig       `json:"stop,omitempty"`
+	Logs         *LogsConfig       `json:"logs,omitempty"`
+	LogFormat    string            `json:"logFormat,omitempty"`
+	LogRateLimit *int              `json:"logRateLimit,omitempty"`
+	Multiline    *MultilineConfig  `json:"multiline,omitempty"`
+	Alerts       []AlertConfig     `json:"alerts,omitempty"`
+	Env          map[string]string `json:"env,omitempty"`
+	EnvFiles     []string          `json:"envFiles,omitempty"`
+	DependsOn    []string          `json:"dependsOn,omitempty"`
+	ReadyWhen    *ReadyConfig      `json:"readyWhen,omitempty"`
+	Healthcheck  *HealthConfig     `json:"healthcheck,omitempty"`
+}
+
 // ProcessStartResult is returned when starting a process.
 type ProcessStartResult struct {
 	Success   bool   `json:"success"`
@@ -68,6 +226,12 @@ type ProcessStopResult struct {
 	Error   string `json:"error,omitempty"`
 }
 
+// ProcessStdinResult is returned when writing to the stdin of a process.
+type ProcessStdinResult struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
 // ProcessResourceData holds CPU and memory data for a process.
 type ProcessResourceData struct {
 	CPU         float64 `json:"cpu"`
@@ -75,13 +239,39 @@ type ProcessResourceData struct {
 }
 
 // ProcessLogData represents a log entry from a process.
+// Cursor increases with every entry, across all processes.
+// For structured lines (log_format), Output is the message, and Level and Fields hold the rest.
 type ProcessLogData struct {
-	ProcessID string  `json:"processId"`
-	Timestamp string  `json:"timestamp"`
-	Type      string  `json:"type"`
-	Output    string  `json:"output,omitempty"`
-	Code      *int    `json:"code,omitempty"`
-	Signal    *string `json:"signal,omitempty"`
+	ProcessID string         `json:"processId"`
+	Cursor    int64          `json:"cursor"`
+	Timestamp string         `json:"timestamp"`
+	Type      string         `json:"type"`
+	Output    string         `json:"output,omitempty"`
+	Level     string         `json:"level,omitempty"`
+	Fields    map[string]any `json:"fields,omitempty"`
+	Code      *int           `json:"code,omitempty"`
+	Signal    *string        `json:"signal,omitempty"`
+	// Partial entries preview a line still missing its newline (a prompt, a progress bar).
+	// Replace entries replace the previous entry of the stream: a partial preview or a line overwritten with \r.
+	Partial bool `json:"partial,omitempty"`
+	Replace bool `json:"replace,omitempty"`
+	// Suppressed is set on the synthetic entry reporting lines dropped by the log rate limit
+	Suppressed int `json:"suppressed,omitempty"`
+	// processName is only used in-process (e.g. control API log streams), where IDs change on restart
+	processName string
+}
+
+// ProcessLogsResult is a page of buffered logs. Cursor is the cursor of the last entry returned.
+type ProcessLogsResult struct {
+	Logs    []ProcessLogData `json:"logs"`
+	Cursor  int64            `json:"cursor"`
+	HasMore bool             `json:"hasMore"`
+}
+
+// ProcessLogSearchResult holds the buffered logs matching a search.
+type ProcessLogSearchResult struct {
+	Logs  []ProcessLogData `json:"logs"`
+	Error string           `json:"error,omitempty"`
 }
 
 // ProcessRestartData is emitted when a process auto-restarts.
@@ -92,11 +282,57 @@ type ProcessRestartData struct {
 	Timestamp  string `json:"timestamp"`
 }
 
+// ProcessStartedData is emitted when a named process is started, so every client (UI, control API)
+// can track processes it did not start itself.
+type ProcessStartedData struct {
+	ProcessID string `json:"processId"`
+	Name      string `json:"name"`
+	Timestamp string `json:"timestamp"`
+}
+
+// ProcessInfo is a snapshot of a managed process, as reported by the control API and the CLI.
+type ProcessInfo struct {
+	ProcessID  string `json:"processId"`
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Pid        int    `json:"pid,omitempty"`
+	Ready      bool   `json:"ready"`
+	Command    string `json:"command"`
+	Cwd        string `json:"cwd"`
+	RetryCount int    `json:"retryCount"`
+	StartedAt  string `json:"startedAt,omitempty"`
+}
+
+// ProcessReadyData is emitted when a process passes (or times out on) its readiness checks.
+type ProcessReadyData struct {
+	ProcessID string `json:"processId"`
+	Timestamp string `json:"timestamp"`
+}
+
+// ProcessAlertData is emitted when a log entry of a process matches one of its alerts.
+type ProcessAlertData struct {
+	ProcessID string `json:"processId"`
+	Name      string `json:"name"`
+	Pattern   string `json:"pattern"`
+	Level     string `json:"level"`
+	Notify    bool   `json:"notify"`
+	Line      string `json:"line"`
+	Timestamp string `json:"timestamp"`
+}
+
+// ProcessUnhealthyData is emitted when a process fails its healthcheck too many times in a row.
+type ProcessUnhealthyData struct {
+	ProcessID string `json:"processId"`
+	Failures  int    `json:"failures"`
+	Timestamp string `json:"timestamp"`
+}
+
 // ProcessCrashData is emitted when a process crashes.
 type ProcessCrashData struct {
 	ProcessID   string  `json:"processId"`
 	ExitCode    *int    `json:"exitCode"`
 	Signal      *string `json:"signal"`
 	WillRestart bool    `json:"willRestart"`
+	Unhealthy   bool    `json:"unhealthy,omitempty"`
 	Timestamp   string  `json:"timestamp"`
 }
@@ -0,0 +1,130 @@
+package backend
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Line reported by yaml.v3 in syntax and type errors, e.g. "yaml: line 12: did not find expected key"
+var yamlErrorLinePattern = regexp.MustCompile(`line (\d+): (.*)`)
+
+// yamlPosition is the 1-based line and column of a YAML node.
+type yamlPosition struct {
+	line   int
+	column int
+}
+
+// indexYamlPositions maps the path of every node under node (in the ValidationError.Path format,
+// e.g. processes[0].args[1]) to its position. Mapping values are located at their key.
+func indexYamlPositions(node *yaml.Node, path string, positions map[string]yamlPosition) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			indexYamlPositions(child, path, positions)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			childPath := key.Value
+			if path != "" {
+				childPath = path + "." + key.Value
+			}
+			positions[childPath] = yamlPosition{line: key.Line, column: key.Column}
+			indexYamlPositions(value, childPath, positions)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			childPath := fmt.Sprintf("%s[%d]", path, i)
+			positions[childPath] = yamlPosition{line: item.Line, column: item.Column}
+			indexYamlPositions(item, childPath, positions)
+		}
+	case yaml.AliasNode:
+		if node.Alias != nil {
+			indexYamlPositions(node.Alias, path, positions)
+		}
+	}
+}
+
+// locateValidationErrors sets the position of each error from the field it is about, or from its path.
+// Paths of missing fields fall back to their closest existing parent.
+func locateValidationErrors(errors []ValidationError, positions map[string]yamlPosition) {
+	for i := range errors {
+		path := errors[i].Path
+		if errors[i].field != "" {
+			path = errors[i].field
+		}
+		errors[i].field = ""
+		for path != "" {
+			if position, exists := positions[path]; exists {
+				errors[i].Line = position.line
+				errors[i].Column = position.column
+				break
+			}
+			path = parentYamlPath(path)
+		}
+	}
+}
+
+// joinFieldPath returns the path of a field named relative to path. The name may repeat the end of path,
+// as validators report some errors at the field itself: "log_format" at processes[0].log_format, or
+// "command[1]" at processes[0].command.
+func joinFieldPath(path string, name string) string {
+	switch {
+	case path == "":
+		return name
+	case path == name || strings.HasSuffix(path, "."+name):
+		return path
+	}
+	last := path[strings.LastIndex(path, ".")+1:]
+	if rest, found := strings.CutPrefix(name, last); found && (rest == "" || rest[0] == '.' || rest[0] == '[') {
+		return path + rest
+	}
+	return path + "." + name
+}
+
+// parentYamlPath drops the last segment of a path: processes[0].args[1] → processes[0].args.
+func parentYamlPath(path string) string {
+	cut := max(strings.LastIndex(path, "."), strings.LastIndex(path, "["))
+	if cut < 0 {
+		return ""
+	}
+	return path[:cut]
+}
+
+// yamlParseError converts a yaml.v3 error to a ValidationError, keeping the parser's message and line.
+func yamlParseError(err error) ValidationError {
+	message := strings.TrimPrefix(err.Error(), "yaml: ")
+	match := yamlErrorLinePattern.FindStringSubmatch(message)
+	if match == nil {
+		return ValidationError{Message: "Invalid YAML file: " + message}
+	}
+	line, _ := strconv.Atoi(match[1])
+	return ValidationError{Message: "Invalid YAML file: " + match[2], Line: line}
+}
+
+// formatValidationError renders an error as "<file>: <path> (line L, column C): <message>", for terminal output.
+func formatValidationError(e ValidationError) string {
+	location := e.Path
+	if e.Line > 0 {
+		position := fmt.Sprintf("line %d, column %d", e.Line, e.Column)
+		if e.Column == 0 {
+			position = fmt.Sprintf("line %d", e.Line)
+		}
+		if location != "" {
+			location += " (" + position + ")"
+		} else {
+			location = position
+		}
+	}
+	if e.File != "" {
+		location = strings.TrimSuffix(e.File+": "+location, ": ")
+	}
+	if location == "" {
+		return e.Message
+	}
+	return location + ": " + e.Message
+}
@@ -0,0 +1,153 @@
+package backend
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLocateValidationErrors_FieldPositions(t *testing.T) {
+	t.Parallel()
+	content := `project_name: "Positions"
+processes:
+  - name: "api"
+    command: ["node", 42]
+    tty: "yes"
+    log_format: "xml"
+    env:
+      PORT: 3000
+    restart:
+      enabled: "no"
+    stop:
+      signal: "SIGFOO"
+    ready_when:
+      tcp: 99999
+    args:
+      - type: "input"
+        default: ""
+  - base_command: "pnpm dev"
+`
+
+	expected := []ValidationError{
+		{Message: "command[1] must be a non-empty string", Path: "processes[0].command", Line: 4, Column: 23},
+		{Message: "tty must be a boolean", Path: "processes[0]", Line: 5, Column: 5},
+		{Message: "env.PORT must be a string", Path: "processes[0].env", Line: 8, Column: 7},
+		{Message: "restart.enabled must be a boolean", Path: "processes[0].restart", Line: 10, Column: 7},
+		{Message: "stop.signal must be one of the following values: SIGINT, SIGTERM, SIGQUIT, SIGHUP, SIGKILL, SIGUSR1, SIGUSR2", Path: "processes[0].stop", Line: 12, Column: 7},
+		{Message: "log_format must be one of the following values: json, logfmt, text", Path: "processes[0].log_format", Line: 6, Column: 5},
+		{Message: "ready_when.tcp must be a port number between 1 and 65535", Path: "processes[0].ready_when", Line: 14, Column: 7},
+		// Missing fields fall back to their closest existing parent
+		{Message: "name must be a non-empty string", Path: "processes[0].args[0]", Line: 16, Column: 9},
+		{Message: "name must be a non-empty string", Path: "processes[1]", Line: 18, Column: 5},
+	}
+	errors := ExtractYamlConfig(content).Errors
+	for _, want := range expected {
+		found := false
+		for _, got := range errors {
+			if got.Message != want.Message || got.Path != want.Path {
+				continue
+			}
+			found = true
+			if got.Line != want.Line || got.Column != want.Column {
+				t.Errorf("%q at %s: position = %d:%d, want %d:%d", want.Message, want.Path, got.Line, got.Column, want.Line, want.Column)
+			}
+		}
+		if !found {
+			t.Errorf("expected error %q at %s, got %+v", want.Message, want.Path, errors)
+		}
+	}
+}
+
+func TestLocateValidationErrors_IncludedFiles(t *testing.T) {
+	t.Parallel()
+	path := writeIncludeProject(t, map[string]string{
+		"config.yml": "project_name: monorepo\ninclude: [services/api.yml]\nprocesses:\n  - name: web\n    base_command: pnpm dev\n",
+		"services/api.yml": "processes:\n  - name: api\n    base_command: pnpm start\n" +
+			"    restart:\n      enabled: true\n      max_retries: 0\n    depends_on: [web, db]\n",
+	})
+
+	expected := []ValidationError{
+		{Message: "restart.max_retries must be a positive number", Path: "processes[0].restart", File: filepath.Join("services", "api.yml"), Line: 6, Column: 7},
+		{Message: "depends_on references unknown process: db", Path: "processes[0].depends_on", File: filepath.Join("services", "api.yml"), Line: 7, Column: 23},
+	}
+	errors := validateConfigFile(path).Errors
+	if len(errors) != len(expected) {
+		t.Fatalf("got %d errors, want %d\ngot:  %+v\nwant: %+v", len(errors), len(expected), errors, expected)
+	}
+	for i, want := range expected {
+		if errors[i] != want {
+			t.Errorf("error[%d] = %+v, want %+v", i, errors[i], want)
+		}
+	}
+}
+
+func TestJoinFieldPath(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		path string
+		name string
+		want string
+	}{
+		{"", "project_name", "project_name"},
+		{"processes[0]", "tty", "processes[0].tty"},
+		{"processes[0].log_format", "log_format", "processes[0].log_format"},
+		{"processes[0].command", "command[1]", "processes[0].command[1]"},
+		{"processes[0].stop", "stop.signal", "processes[0].stop.signal"},
+		{"include", "include[0]", "include[0]"},
+		{"profiles.dev.args.api.Mode", "args.api.Mode", "profiles.dev.args.api.Mode"},
+		{"processes[0]", "env_file[1].path", "processes[0].env_file[1].path"},
+		{"processes[0].stopper", "stop.signal", "processes[0].stopper.stop.signal"},
+	}
+	for _, tt := range tests {
+		if got := joinFieldPath(tt.path, tt.name); got != tt.want {
+			t.Errorf("joinFieldPath(%q, %q) = %q, want %q", tt.path, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFormatValidationError(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		err  ValidationError
+		want string
+	}{
+		{
+			name: "message only",
+			err:  ValidationError{Message: "Invalid YAML file"},
+			want: "Invalid YAML file",
+		},
+		{
+			name: "path",
+			err:  ValidationError{Message: "tty must be a boolean", Path: "processes[0]"},
+			want: "processes[0]: tty must be a boolean",
+		},
+		{
+			name: "path and position",
+			err:  ValidationError{Message: "tty must be a boolean", Path: "processes[0]", Line: 5, Column: 5},
+			want: "processes[0] (line 5, column 5): tty must be a boolean",
+		},
+		{
+			name: "line without column",
+			err:  ValidationError{Message: "Invalid YAML file: did not find expected key", Line: 12},
+			want: "line 12: Invalid YAML file: did not find expected key",
+		},
+		{
+			name: "file",
+			err:  ValidationError{Message: "Invalid YAML file", File: "services/api.yml"},
+			want: "services/api.yml: Invalid YAML file",
+		},
+		{
+			name: "file, path and position",
+			err:  ValidationError{Message: "name must be a non-empty string", Path: "processes[1]", File: "services/api.yml", Line: 3, Column: 5},
+			want: "services/api.yml: processes[1] (line 3, column 5): name must be a non-empty string",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := formatValidationError(tt.err); got != tt.want {
+				t.Errorf("formatValidationError() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
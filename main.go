@@ -22,6 +22,13 @@ const appVersion = "3.0.1"
 func main() {
 	backend.FixPath()
 
+	// Headless mode: `ClickLaunch up|down|status|logs <config>` (or `schema`) runs without opening a window
+	if len(os.Args) > 1 && backend.IsCLICommand(os.Args[1]) {
+		os.Exit(backend.RunCLI(os.Args[1:]))
+	}
+
+	processService := backend.NewProcessService()
+
 	app := application.New(application.Options{
 		Name:        "Click Launch",
 		Description: "Desktop app for managing your local dev stack",
@@ -32,7 +39,8 @@ func main() {
 		Services: []application.Service{
 			application.NewService(backend.NewConfigService()),
 			application.NewService(backend.NewFileService()),
-			application.NewService(backend.NewProcessService()),
+			application.NewService(processService),
+			application.NewService(backend.NewControlService(processService)),
 			application.NewService(backend.NewResourceService()),
 			application.NewService(backend.NewAppService(appVersion)),
 		},
@@ -3,10 +3,11 @@
 # - VAR1 comes from .env file only
 # - VAR2 is in .env file but overridden by explicit env
 # - VAR3 comes from explicit env only
+# - VAR4 and VAR5 interpolate ${VAR1} and ${VAR3}
 
 echo "VAR1=$VAR1 (expected: from_env_file)"
 echo "VAR2=$VAR2 (expected: overridden)"
 echo "VAR3=$VAR3 (expected: from_explicit_env)"
-echo "VAR4=$VAR4 (expected: prefix_\$VAR1 — no expansion)"
-echo "VAR5=$VAR5 (expected: prefix_\$VAR3 — no expansion)"
+echo "VAR4=$VAR4 (expected: prefix_from_env_file — \${VAR1} from .env file)"
+echo "VAR5=$VAR5 (expected: prefix_from_explicit_env — \${VAR3} from explicit env)"
 sleep 1
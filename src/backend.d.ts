@@ -5,10 +5,18 @@
  */
 declare module "@backend" {
   import type {
+    LogType,
+    ProcessConfig,
+    ProcessLogSearchResult,
+    ProcessLogsResult,
     ProcessResourceData,
+    ProcessSpec,
     ProcessStartResult,
+    ProcessStdinResult,
     ProcessStopResult,
+    ProfileInfo,
     ValidationResult,
+    YamlConfig,
   } from "@/types";
 
   export const AppService: {
@@ -19,6 +27,15 @@ declare module "@backend" {
 
   export const ConfigService: {
     Validate(filePath: string): Promise<ValidationResult>;
+    GetDependents(config: YamlConfig, name: string): Promise<string[]>;
+    BuildCommand(
+      process: ProcessConfig,
+      argValues: Record<string, unknown>,
+    ): Promise<string>;
+    WatchConfig(filePath: string): Promise<void>;
+    UnwatchConfig(): Promise<void>;
+    GetSchema(): Promise<string>;
+    ListProfiles(config: YamlConfig): Promise<ProfileInfo[]>;
   };
 
   export const FileService: {
@@ -38,13 +55,45 @@ declare module "@backend" {
       command: string,
       restartConfig: Record<string, any> | null,
       env: Record<string, string>,
-      envFile: string,
+      envFiles: string[],
     ): Promise<ProcessStartResult>;
+    StartWithDependencies(
+      name: string,
+      specs: ProcessSpec[],
+    ): Promise<Record<string, ProcessStartResult>>;
+    StartByName(
+      configPath: string,
+      name: string,
+      argValues: Record<string, unknown>,
+      env: Record<string, string>,
+    ): Promise<Record<string, ProcessStartResult>>;
+    StartProfile(
+      configPath: string,
+      profile: string,
+    ): Promise<Record<string, ProcessStartResult>>;
     Stop(id: string): Promise<ProcessStopResult>;
+    StopWithDependents(
+      name: string,
+      specs: ProcessSpec[],
+    ): Promise<Record<string, ProcessStopResult>>;
     StopAll(): Promise<void>;
     IsRunning(id: string): Promise<boolean>;
+    IsReady(id: string): Promise<boolean>;
     BulkStatus(ids: string[]): Promise<Record<string, boolean>>;
+    BulkReadyStatus(ids: string[]): Promise<Record<string, boolean>>;
     GetRunningProcessPids(ids: string[]): Promise<Record<string, number>>;
+    WriteStdin(id: string, data: string): Promise<ProcessStdinResult>;
+    ResizeTerminal(id: string, cols: number, rows: number): Promise<boolean>;
+    GetLogs(
+      id: string,
+      sinceCursor: number,
+      limit: number,
+    ): Promise<ProcessLogsResult>;
+    SearchLogs(
+      id: string,
+      pattern: string,
+      types: LogType[] | null,
+    ): Promise<ProcessLogSearchResult>;
   };
 
   export const ResourceService: {
@@ -7,6 +7,8 @@ export type Settings = {
   showGrouping: boolean;
   showResourceMonitor: boolean;
   showTimestamps: boolean;
+  persistLogs: boolean;
+  restartOnConfigChange: boolean;
   resourceHistoryMinutes: number;
 };
 
@@ -61,6 +63,8 @@ export const DEFAULT_SETTINGS: Settings = {
   showGrouping: true,
   showResourceMonitor: true,
   showTimestamps: true,
+  persistLogs: false,
+  restartOnConfigChange: false,
   resourceHistoryMinutes: 15,
 };
 
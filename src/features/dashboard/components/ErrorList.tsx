@@ -48,9 +48,16 @@ export const ErrorList = () => {
                 </div>
                 <div class="list-col-grow">
                   <div>{error.message}</div>
-                  <Show when={error.path}>
+                  <Show when={error.file || error.path || error.line}>
                     <div class="text-xs uppercase font-semibold opacity-60">
-                      {error.path}
+                      {[
+                        error.file,
+                        error.path,
+                        error.line &&
+                          `Line ${error.line}${error.column ? `, column ${error.column}` : ""}`,
+                      ]
+                        .filter(Boolean)
+                        .join(" · ")}
                     </div>
                   </Show>
                 </div>
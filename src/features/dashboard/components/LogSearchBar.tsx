@@ -7,8 +7,9 @@ import {
   Search,
   Trash2,
 } from "lucide-solid";
-import { type Accessor, type Setter, Show } from "solid-js";
+import { type Accessor, For, type Setter, Show } from "solid-js";
 import type { SetStoreFunction } from "solid-js/store";
+import { LOG_LEVELS, type LogLevel } from "@/types";
 
 type LogSearchBarProps = {
   searchInputRef: (el: HTMLInputElement) => void;
@@ -23,6 +24,9 @@ type LogSearchBarProps = {
   isRegexMode: Accessor<boolean>;
   setIsRegexMode: Setter<boolean>;
   regexError: Accessor<string | null>;
+  showLevelFilter: boolean;
+  minLevel: Accessor<LogLevel | null>;
+  setMinLevel: Setter<LogLevel | null>;
   onSearchChange: (e: Event) => void;
   goToNextMatch: () => void;
   goToPrevMatch: () => void;
@@ -127,6 +131,25 @@ export const LogSearchBar = (props: LogSearchBarProps) => {
           />
           Filter mode
         </label>
+        <Show when={props.showLevelFilter}>
+          <select
+            class="select select-xs w-28"
+            value={props.minLevel() ?? ""}
+            onChange={(e) =>
+              props.setMinLevel(
+                (e.target as HTMLSelectElement).value
+                  ? ((e.target as HTMLSelectElement).value as LogLevel)
+                  : null,
+              )
+            }
+            title="Minimum log level"
+          >
+            <option value="">All levels</option>
+            <For each={LOG_LEVELS}>
+              {(level) => <option value={level}>{level}+</option>}
+            </For>
+          </select>
+        </Show>
       </div>
 
       {/* Options */}
@@ -1,5 +1,6 @@
 import { Play, RotateCw, ScrollText, Square } from "lucide-solid";
 import { createMemo, createSignal, Match, Show, Switch } from "solid-js";
+import { Modal } from "@/components/ui";
 import { useToast } from "@/hooks";
 import { useDashboardContext } from "../contexts/";
 import { isProcessActive, ProcessStatus } from "../enums";
@@ -12,11 +13,19 @@ type ProcessActionsProps = {
 const RESTART_ANIMATION_MS = 500;
 
 export const ProcessActions = (props: ProcessActionsProps) => {
-  const { getProcessStatus, startProcess, stopProcess, restartProcess } =
-    useDashboardContext();
+  const {
+    getProcessStatus,
+    startProcess,
+    stopProcess,
+    stopProcessWithDependents,
+    getActiveDependents,
+    restartProcess,
+  } = useDashboardContext();
   const toast = useToast();
   const status = () => getProcessStatus(props.processName);
   const [isRestarting, setIsRestarting] = createSignal(false);
+  const [activeDependents, setActiveDependents] = createSignal<string[]>([]);
+  let stopModalRef!: HTMLDialogElement;
 
   const canRestart = createMemo(
     () =>
@@ -34,6 +43,17 @@ export const ProcessActions = (props: ProcessActionsProps) => {
     }, RESTART_ANIMATION_MS);
   };
 
+  // Offer to stop dependents first when other active processes rely on this one
+  const handleStop = async () => {
+    const dependents = await getActiveDependents(props.processName);
+    if (dependents.length === 0) {
+      await stopProcess(props.processName);
+      return;
+    }
+    setActiveDependents(dependents);
+    stopModalRef?.showModal();
+  };
+
   return (
     <div class="flex items-center gap-2">
       <Switch>
@@ -66,7 +86,7 @@ export const ProcessActions = (props: ProcessActionsProps) => {
           <button
             type="button"
             class="btn btn-error btn-circle btn-sm"
-            onClick={() => stopProcess(props.processName)}
+            onClick={handleStop}
           >
             <Square size={16} />
           </button>
@@ -115,6 +135,18 @@ export const ProcessActions = (props: ProcessActionsProps) => {
           </button>
         )}
       </Show>
+
+      <Modal
+        ref={stopModalRef!}
+        onConfirm={() => stopProcessWithDependents(props.processName)}
+        closable={true}
+      >
+        <h1 class="text-xl font-bold">Stop {props.processName}?</h1>
+        <p>
+          These processes depend on it and will be stopped first:{" "}
+          {activeDependents().join(", ")}
+        </p>
+      </Modal>
     </div>
   );
 };
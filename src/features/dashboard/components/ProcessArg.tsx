@@ -1,11 +1,4 @@
-import {
-  createEffect,
-  createMemo,
-  createSignal,
-  For,
-  Match,
-  Switch,
-} from "solid-js";
+import { For, Match, Switch } from "solid-js";
 import type { ArgConfig } from "@/types";
 import { ArgType } from "@/types";
 import { useDashboardContext } from "../contexts/";
@@ -17,18 +10,19 @@ type ProcessArgProps = {
 };
 
 export const ProcessArg = (props: ProcessArgProps) => {
-  const {
-    name,
-    type,
-    default: defaultValue,
-    values,
-    output_prefix,
-  } = props.argConfig;
+  const { name, type, default: defaultValue, values } = props.argConfig;
 
-  const [value, setValue] = createSignal(defaultValue);
-  const { setArgValues, getProcessStatus } = useDashboardContext();
+  const { getProcessData, setArgValues, getProcessStatus } =
+    useDashboardContext();
   const status = () => getProcessStatus(props.processName);
 
+  // The store owns the value, so that values set elsewhere (e.g. by profiles) are displayed
+  const value = () =>
+    getProcessData(props.processName)?.argValues[name] ?? defaultValue;
+  const setValue = (newValue: unknown) => {
+    setArgValues(props.processName, name, newValue);
+  };
+
   const canEdit = () => !isProcessActive(status());
 
   const onSelectChange = (e: Event) => {
@@ -46,27 +40,6 @@ export const ProcessArg = (props: ProcessArgProps) => {
     setValue(target.checked);
   };
 
-  const output = createMemo<string>(() => {
-    switch (type) {
-      case ArgType.TOGGLE:
-        return values?.find((v) => v.value === value())?.output ?? "";
-      case ArgType.SELECT:
-        return values?.find((v) => v.value === value())?.output ?? "";
-      case ArgType.INPUT: {
-        let val = value() as string;
-        if (output_prefix && val !== "") {
-          val = `${output_prefix} ${val}`;
-        }
-        return val;
-      }
-    }
-  });
-
-  // Watch value changes and update command
-  createEffect(() => {
-    setArgValues(props.processName, name, output());
-  });
-
   return (
     <Switch>
       <Match when={type === ArgType.TOGGLE}>
@@ -92,7 +65,12 @@ export const ProcessArg = (props: ProcessArgProps) => {
           >
             <For each={values}>
               {(valueOption) => (
-                <option value={valueOption.value}>{valueOption.value}</option>
+                <option
+                  value={valueOption.value}
+                  selected={String(valueOption.value) === String(value())}
+                >
+                  {valueOption.value}
+                </option>
               )}
             </For>
           </select>
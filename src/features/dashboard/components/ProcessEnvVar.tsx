@@ -1,4 +1,3 @@
-import { createEffect, createSignal } from "solid-js";
 import { useDashboardContext } from "../contexts/";
 import { isProcessActive } from "../enums";
 
@@ -9,21 +8,22 @@ type ProcessEnvVarProps = {
 };
 
 export const ProcessEnvVar = (props: ProcessEnvVarProps) => {
-  const [value, setValue] = createSignal(props.defaultValue);
-  const { setEnvValue, getProcessStatus } = useDashboardContext();
+  const { getProcessData, setEnvValue, getProcessStatus } =
+    useDashboardContext();
   const status = () => getProcessStatus(props.processName);
 
+  // The store owns the value, so that values set elsewhere (e.g. by profiles) are displayed
+  const value = () =>
+    getProcessData(props.processName)?.envValues[props.envKey] ??
+    props.defaultValue;
+
   const canEdit = () => !isProcessActive(status());
 
   const onInputChange = (e: Event) => {
     const target = e.target as HTMLInputElement;
-    setValue(target.value);
+    setEnvValue(props.processName, props.envKey, target.value);
   };
 
-  createEffect(() => {
-    setEnvValue(props.processName, props.envKey, value());
-  });
-
   return (
     <div class="flex flex-row items-center gap-2">
       <span class="text-xs font-bold w-40 font-mono">{props.envKey}:</span>
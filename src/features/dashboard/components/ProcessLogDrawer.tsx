@@ -1,4 +1,5 @@
 import { FileService } from "@backend";
+import { Show } from "solid-js";
 import { createStore } from "solid-js/store";
 import { NAVBAR_HEIGHT } from "@/components/layout/constants";
 import { useSettingsContext } from "@/contexts";
@@ -12,12 +13,14 @@ import {
   useLogScroll,
   useLogSearch,
   useLogStore,
+  useTerminalSize,
 } from "../hooks";
 import { KeyboardShortcutsModal } from "./KeyboardShortcutsModal";
 import { LogSearchBar } from "./LogSearchBar";
 import { LogVirtualList } from "./LogVirtualList";
 import { ProcessDrawerHeader } from "./ProcessDrawerHeader";
 import { ProcessResources } from "./ProcessResources";
+import { ProcessStdinInput } from "./ProcessStdinInput";
 
 type ProcessLogDrawerProps = {
   processName: string;
@@ -35,6 +38,8 @@ export const ProcessLogDrawer = (props: ProcessLogDrawerProps) => {
     getProcessResources,
   } = useDashboardContext();
   const { settings } = useSettingsContext();
+  const processConfig = () =>
+    yamlConfig()?.processes.find((p) => p.name === props.processName);
   const resources = () => getProcessResources(props.processName);
   const isRunning = () => isProcessActive(getProcessStatus(props.processName));
   const toast = useToast();
@@ -81,6 +86,12 @@ export const ProcessLogDrawer = (props: ProcessLogDrawerProps) => {
     logScroll.virtualizer,
   );
 
+  const terminalSize = useTerminalSize({
+    processId: () => getProcessId(props.processName),
+    isTTY: () => processConfig()?.tty ?? false,
+    isOpen: () => props.isOpen,
+  });
+
   const rangeSelect = useLogRangeSelect({
     displayedLogs: logSearch.displayedLogs,
     onSelectionStarted: () => toast.info("Click the first log line to copy"),
@@ -164,6 +175,11 @@ export const ProcessLogDrawer = (props: ProcessLogDrawerProps) => {
           isRegexMode={logSearch.isRegexMode}
           setIsRegexMode={logSearch.setIsRegexMode}
           regexError={logSearch.regexError}
+          showLevelFilter={
+            (processConfig()?.log_format ?? "text") !== "text"
+          }
+          minLevel={logSearch.minLevel}
+          setMinLevel={logSearch.setMinLevel}
           onSearchChange={logSearch.onSearchChange}
           goToNextMatch={logSearch.goToNextMatch}
           goToPrevMatch={logSearch.goToPrevMatch}
@@ -184,7 +200,10 @@ export const ProcessLogDrawer = (props: ProcessLogDrawerProps) => {
           selectedLogId={logSearch.selectedLogId}
           isAtBottom={logScroll.isAtBottom}
           virtualizer={logScroll.virtualizer}
-          setLogsContainerRef={logScroll.setLogsContainerRef}
+          setLogsContainerRef={(el) => {
+            logScroll.setLogsContainerRef(el);
+            terminalSize.setContainerRef(el);
+          }}
           onScrollToBottom={logScroll.scrollToBottomManual}
           isRangeSelecting={rangeSelect.isSelecting}
           rangeAnchorIdx={rangeSelect.anchorIdx}
@@ -192,6 +211,13 @@ export const ProcessLogDrawer = (props: ProcessLogDrawerProps) => {
           onRowClick={rangeSelect.handleRowClick}
         />
 
+        <Show when={processConfig()?.stdin}>
+          <ProcessStdinInput
+            processId={getProcessId(props.processName)}
+            isRunning={isRunning()}
+          />
+        </Show>
+
         {/* Keyboard shortcuts hint */}
         <div class="border-t border-base-300 flex justify-end px-1">
           <button
@@ -3,10 +3,19 @@ import { createMemo, For, type JSX, Show } from "solid-js";
 import { useSettingsContext } from "@/contexts";
 import { useToast } from "@/hooks";
 import type { ProcessLogData } from "@/types";
-import { LogType } from "@/types";
+import { LogLevel, LogType } from "@/types";
 import { parseAnsiToSegments } from "@/utils/ansiToHtml";
 import { formatTimestamp } from "@/utils/formatters";
-import { stripAnsiCodes } from "@/utils/logExport";
+import { formatLogFields, stripAnsiCodes } from "@/utils/logExport";
+
+const LEVEL_CLASSES: Record<LogLevel, string> = {
+  [LogLevel.TRACE]: "text-gray-500",
+  [LogLevel.DEBUG]: "text-gray-400",
+  [LogLevel.INFO]: "text-sky-400",
+  [LogLevel.WARN]: "text-yellow-400",
+  [LogLevel.ERROR]: "text-red-400",
+  [LogLevel.FATAL]: "text-red-500 font-bold",
+};
 
 type ProcessLogRowProps = {
   log: ProcessLogData;
@@ -61,10 +70,20 @@ export const ProcessLogRow = (props: ProcessLogRowProps) => {
 
   const logOutput = () =>
     props.log.type === LogType.EXIT ? "" : props.log.output;
+  const logLevel = () =>
+    props.log.type === LogType.EXIT ? undefined : props.log.level;
+  const isSuppressed = () =>
+    props.log.type !== LogType.EXIT && !!props.log.suppressed;
+  const logFields = () =>
+    props.log.type === LogType.EXIT || !props.log.fields
+      ? ""
+      : formatLogFields(props.log.fields);
 
   // Memoize expensive ANSI parsing - only recompute when log output changes
+  // (fields follow the message on the same line, so its newline is dropped)
   const segments = createMemo(() => {
-    return parseAnsiToSegments(logOutput() || "");
+    const output = logOutput() || "";
+    return parseAnsiToSegments(logFields() ? output.trimEnd() : output);
   });
 
   // Memoize search regex - only recreate when search term changes
@@ -84,12 +103,20 @@ export const ProcessLogRow = (props: ProcessLogRowProps) => {
     <div
       class={`group relative ${
         props.isCurrentMatch ? "bg-gray-700 rounded" : ""
+      } ${
+        isSuppressed() ? "italic text-warning" : ""
       } whitespace-pre-wrap wrap-break-word`}
     >
       <button
         type="button"
         class="btn btn-ghost btn-xs absolute -left-5 top-0 opacity-0 group-hover:opacity-100 transition-opacity min-h-0 h-auto p-0.5"
-        onClick={() => copyLogLine(logOutput())}
+        onClick={() =>
+          copyLogLine(
+            logFields()
+              ? `${logOutput().trimEnd()} ${logFields()}`
+              : logOutput(),
+          )
+        }
         title="Copy log line"
       >
         <Copy size={14} />
@@ -99,6 +126,13 @@ export const ProcessLogRow = (props: ProcessLogRowProps) => {
           [{formatTimestamp(props.log.timestamp)}]{" "}
         </span>
       </Show>
+      <Show when={logLevel()}>
+        {(level) => (
+          <span class={LEVEL_CLASSES[level()]}>
+            {level().toUpperCase().padEnd(5)}{" "}
+          </span>
+        )}
+      </Show>
       <For each={segments()}>
         {(segment) => (
           <span class={segment.classes.join(" ")}>
@@ -112,6 +146,9 @@ export const ProcessLogRow = (props: ProcessLogRowProps) => {
           </span>
         )}
       </For>
+      <Show when={logFields()}>
+        <span class="text-gray-500"> {logFields()}</span>
+      </Show>
     </div>
   );
 };
@@ -49,7 +49,16 @@ export const ProcessRow = (props: ProcessRowProps) => {
 
   const toggleOptions = () => setShowOptions(!showOptions());
 
+  // Running processes with readiness checks are shown as "Not ready" until they pass
+  const isWaitingForReady = createMemo(
+    () =>
+      status() === ProcessStatus.RUNNING &&
+      !!props.process.ready_when &&
+      !processData()?.ready,
+  );
+
   const statusVariant = createMemo(() => {
+    if (isWaitingForReady()) return "badge-info";
     switch (status()) {
       case ProcessStatus.STARTING:
       case ProcessStatus.RUNNING:
@@ -66,6 +75,7 @@ export const ProcessRow = (props: ProcessRowProps) => {
 
   const statusText = createMemo(() => {
     const currentStatus = status();
+    if (isWaitingForReady()) return "Not ready";
     if (currentStatus === ProcessStatus.RESTARTING) {
       const data = processData();
       if (data) {
@@ -95,7 +105,17 @@ export const ProcessRow = (props: ProcessRowProps) => {
       <td class="align-top w-auto min-w-0 p-2!">
         <div class="flex flex-col gap-2 min-w-0">
           <div class="flex flex-col gap-0 min-w-0">
-            <div class="truncate font-bold">{props.process.name}</div>
+            <div class="flex items-center gap-2 min-w-0">
+              <div class="truncate font-bold">{props.process.name}</div>
+              <Show when={processData()?.alertCount}>
+                <div
+                  class="badge badge-error badge-sm shrink-0"
+                  title="Alerts since the logs were last opened"
+                >
+                  {processData()!.alertCount}
+                </div>
+              </Show>
+            </div>
             <div class="text-xs italic text-gray-400 whitespace-pre-wrap wrap-break-word">
               {command()}
             </div>
@@ -104,10 +124,12 @@ export const ProcessRow = (props: ProcessRowProps) => {
                 {envSummary()}
               </div>
             </Show>
-            <Show when={props.process.env_file}>
+            <Show when={props.process.env_file?.length}>
               <div class="text-xs text-base-content/60">
-                <span class="font-semibold">Env file:</span>{" "}
-                {props.process.env_file}
+                <span class="font-semibold">
+                  Env file{props.process.env_file!.length > 1 ? "s" : ""}:
+                </span>{" "}
+                {props.process.env_file!.join(", ")}
               </div>
             </Show>
             <Show when={hasOptions()}>{button()}</Show>
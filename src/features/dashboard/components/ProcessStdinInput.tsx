@@ -0,0 +1,64 @@
+import { ProcessService } from "@backend";
+import { SendHorizontal, Terminal } from "lucide-solid";
+import { createSignal } from "solid-js";
+import { useToast } from "@/hooks";
+
+type ProcessStdinInputProps = {
+  processId: string | null;
+  isRunning: boolean;
+};
+
+export const ProcessStdinInput = (props: ProcessStdinInputProps) => {
+  const [value, setValue] = createSignal("");
+  const toast = useToast();
+
+  // An empty line still sends Enter, which most prompts and watch modes react to
+  const send = async () => {
+    if (!props.processId) return;
+    const result = await ProcessService.WriteStdin(
+      props.processId,
+      `${value()}\n`,
+    );
+    if (!result.success) {
+      toast.error(result.error ?? "Failed to send input");
+      return;
+    }
+    setValue("");
+  };
+
+  const handleKeyDown = (e: KeyboardEvent) => {
+    if (e.key !== "Enter") return;
+    e.preventDefault();
+    send();
+  };
+
+  return (
+    <div class="px-4 py-2 border-t border-base-300 flex items-center gap-2">
+      <label class="input input-sm w-full font-mono">
+        <Terminal size={16} />
+        <input
+          type="text"
+          placeholder={
+            props.isRunning
+              ? "Send input to the process... (Enter: send line)"
+              : "Process is not running"
+          }
+          class="grow w-full"
+          value={value()}
+          disabled={!props.isRunning}
+          onInput={(e) => setValue(e.currentTarget.value)}
+          onKeyDown={handleKeyDown}
+        />
+      </label>
+      <button
+        type="button"
+        class="btn btn-sm btn-primary"
+        disabled={!props.isRunning}
+        onClick={send}
+        title="Send line"
+      >
+        <SendHorizontal size={16} />
+      </button>
+    </div>
+  );
+};
@@ -18,6 +18,7 @@ export const ProcessTable = (props: ProcessTableProps) => {
     hasGroups,
     isGroupCollapsed,
     getProcessStatus,
+    clearProcessAlerts,
   } = useDashboardContext();
   const { settings } = useSettingsContext();
   const [selectedProcessName, setSelectedProcessName] = createSignal<
@@ -45,6 +46,7 @@ export const ProcessTable = (props: ProcessTableProps) => {
   const openModal = (processName: string) => {
     closeResourceDrawer();
     setSelectedProcessName(processName);
+    clearProcessAlerts(processName);
     drawerCheckboxRef.checked = true;
   };
 
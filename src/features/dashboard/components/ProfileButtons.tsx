@@ -0,0 +1,31 @@
+import { Play } from "lucide-solid";
+import { For, Show } from "solid-js";
+import { useDashboardContext } from "../contexts/";
+
+export const ProfileButtons = () => {
+  const { profiles, launchProfile } = useDashboardContext();
+
+  return (
+    <Show when={profiles().length > 0}>
+      <div class="flex flex-row flex-wrap gap-1 items-center text-sm">
+        <span class="text-base-content/60">Profiles</span>
+        <For each={profiles()}>
+          {(profile) => (
+            <button
+              type="button"
+              class="btn btn-primary btn-outline btn-xs"
+              onClick={() => launchProfile(profile.name)}
+              title={
+                profile.description ??
+                `Start ${profile.processes.join(", ")}`
+              }
+            >
+              <Play size={12} />
+              {profile.name}
+            </button>
+          )}
+        </For>
+      </div>
+    </Show>
+  );
+};
@@ -1,2 +1,3 @@
 export { ErrorList } from "./ErrorList";
 export { ProcessTable } from "./ProcessTable";
+export { ProfileButtons } from "./ProfileButtons";
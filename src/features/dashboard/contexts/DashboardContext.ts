@@ -5,6 +5,7 @@ import type {
   ProcessEnv,
   ProcessId,
   ProcessResourceData,
+  ProfileInfo,
   ResourceHistoryEntry,
   ValidationResult,
   YamlConfig,
@@ -12,14 +13,17 @@ import type {
 import type { ProcessStatus } from "../enums";
 
 export type ProcessData = {
-  argValues: Record<string, string>;
+  argValues: Record<string, unknown>;
   envValues: Record<string, string>;
   status: ProcessStatus;
+  ready: boolean;
   processId: ProcessId | null;
   startTime: Date | null;
   command: string;
   retryCount: number;
   maxRetries: number;
+  // Alerts raised since the logs were last opened
+  alertCount: number;
 };
 
 export type GroupedProcesses = {
@@ -49,14 +53,20 @@ export type DashboardContextType = {
   getProcessStartTime: (processName: string) => Date | null;
   getProcessId: (processName: string) => ProcessId | null;
   getProcessArgs: (processName: string) => ArgConfig[] | undefined;
-  setArgValues: (processName: string, argName: string, value: string) => void;
+  setArgValues: (processName: string, argName: string, value: unknown) => void;
   getProcessEnv: (processName: string) => ProcessEnv | undefined;
   setEnvValue: (processName: string, key: string, value: string) => void;
   getProcessResources: (processName: string) => ProcessResourceData | undefined;
   getProcessResourceHistory: (processName: string) => ResourceHistoryEntry[];
   startProcess: (processName: string) => Promise<void>;
   stopProcess: (processName: string) => Promise<void>;
+  stopProcessWithDependents: (processName: string) => Promise<void>;
+  getActiveDependents: (processName: string) => Promise<string[]>;
   restartProcess: (processName: string) => Promise<void>;
+  clearProcessAlerts: (processName: string) => void;
+  // Profiles
+  profiles: () => ProfileInfo[];
+  launchProfile: (profileName: string) => Promise<void>;
 };
 
 export const DashboardContext = createContext<DashboardContextType | undefined>(
@@ -26,6 +26,7 @@ export const DashboardProvider = (props: DashboardProviderProps) => {
   const processes = useProcesses({
     yamlConfig: config.yamlConfig,
     rootDirectory: config.rootDirectory,
+    configDiff: config.configDiff,
   });
 
   const resources = useResources({
@@ -59,7 +60,13 @@ export const DashboardProvider = (props: DashboardProviderProps) => {
     setEnvValue: processes.setEnvValue,
     startProcess: processes.startProcess,
     stopProcess: processes.stopProcess,
+    stopProcessWithDependents: processes.stopProcessWithDependents,
+    getActiveDependents: processes.getActiveDependents,
     restartProcess: processes.restartProcess,
+    clearProcessAlerts: processes.clearProcessAlerts,
+    // Profiles
+    profiles: processes.profiles,
+    launchProfile: processes.launchProfile,
     // Grouping
     hasGroups: grouping.hasGroups,
     getGroupedProcesses: grouping.getGroupedProcesses,
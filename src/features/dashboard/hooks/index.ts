@@ -8,3 +8,4 @@ export type { LogWithId } from "./useLogStore";
 export { useLogStore } from "./useLogStore";
 export { useProcesses } from "./useProcesses";
 export { useResources } from "./useResources";
+export { useTerminalSize } from "./useTerminalSize";
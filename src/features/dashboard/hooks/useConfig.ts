@@ -1,10 +1,19 @@
 import { ConfigService } from "@backend";
-import { createEffect, createSignal } from "solid-js";
+import { Events } from "@wailsio/runtime";
+import { createEffect, createSignal, onCleanup } from "solid-js";
 import { createStore } from "solid-js/store";
 import { useAppStorageContext } from "@/contexts";
-import type { ValidationResult, YamlConfig } from "@/types";
+import { useToast } from "@/hooks";
+import type {
+  ConfigChangedData,
+  ConfigDiff,
+  ValidationResult,
+  WailsEvent,
+  YamlConfig,
+} from "@/types";
 
 export const useConfig = (selectedFile: string) => {
+  const toast = useToast();
   const [isLoading, setIsLoading] = createSignal(true);
   const [yamlData, setYamlData] = createStore<{
     yamlConfig: YamlConfig | null;
@@ -15,6 +24,8 @@ export const useConfig = (selectedFile: string) => {
     rootDirectory: null,
     errors: [],
   });
+  // Diff of the last reload of the config file, if any
+  const [configDiff, setConfigDiff] = createSignal<ConfigDiff | null>(null);
   const { registerProject } = useAppStorageContext();
 
   const parseFile = async () => {
@@ -28,6 +39,7 @@ export const useConfig = (selectedFile: string) => {
         errors: [],
       });
       registerProject(selectedFile);
+      ConfigService.WatchConfig(selectedFile);
     } else {
       setYamlData({
         yamlConfig: null,
@@ -39,17 +51,44 @@ export const useConfig = (selectedFile: string) => {
     setIsLoading(false);
   };
 
+  // Invalid edits keep the previous config, so that running processes stay manageable
+  const handleConfigChanged = (data: ConfigChangedData) => {
+    if (!data.result.isValid || !data.result.config) {
+      toast.error("Config file has errors, keeping the previous version");
+      return;
+    }
+    setYamlData({
+      yamlConfig: data.result.config,
+      rootDirectory: data.result.rootDirectory || null,
+      errors: [],
+    });
+    setConfigDiff(data.diff);
+    toast.info("Config file reloaded");
+  };
+
   createEffect(() => {
     if (selectedFile) {
       parseFile();
     }
   });
 
+  createEffect(() => {
+    const off = Events.On(
+      "config-changed",
+      (event: WailsEvent<ConfigChangedData>) => handleConfigChanged(event.data),
+    );
+    onCleanup(() => {
+      off();
+      ConfigService.UnwatchConfig();
+    });
+  });
+
   return {
     isLoading,
     yamlConfig: () => yamlData.yamlConfig,
     rootDirectory: () => yamlData.rootDirectory,
     errors: () => yamlData.errors,
+    configDiff,
     parseFile,
   };
 };
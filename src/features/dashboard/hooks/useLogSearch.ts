@@ -1,6 +1,6 @@
 import { createEffect, createMemo, createSignal, on } from "solid-js";
 import { createStore } from "solid-js/store";
-import { LogType } from "@/types";
+import { LOG_LEVELS, type LogLevel, LogType } from "@/types";
 import type { LogWithId } from "./useLogStore";
 
 const SEARCH_DELAY_MS = 500;
@@ -24,6 +24,7 @@ export const useLogSearch = ({
   const [isRegexMode, setIsRegexMode] = createSignal(false);
   const [regexError, setRegexError] = createSignal<string | null>(null);
   const [selectedLogId, setSelectedLogId] = createSignal<string | null>(null);
+  const [minLevel, setMinLevel] = createSignal<LogLevel | null>(null);
   const [searchState, setSearchState] = createStore({
     term: "",
     currentMatchIndex: -1,
@@ -34,8 +35,21 @@ export const useLogSearch = ({
     () => new Set(searchState.matchingLogIds),
   );
 
-  const displayedLogs = createMemo(() => {
+  // With a minimum level, only structured lines at or above it (and exits) are kept
+  const levelFilteredLogs = createMemo(() => {
     const logs = currentLogs();
+    const level = minLevel();
+    if (!level) return logs;
+    const minIndex = LOG_LEVELS.indexOf(level);
+    return logs.filter(
+      (log) =>
+        log.type === LogType.EXIT ||
+        (log.level !== undefined && LOG_LEVELS.indexOf(log.level) >= minIndex),
+    );
+  });
+
+  const displayedLogs = createMemo(() => {
+    const logs = levelFilteredLogs();
     if (!isFilterMode() || !searchState.term.trim()) {
       return logs;
     }
@@ -113,9 +127,9 @@ export const useLogSearch = ({
 
   // Update matching log IDs when search term, logs, or regex mode change
   createEffect(
-    on([() => searchState.term, currentLogs, isRegexMode], () => {
+    on([() => searchState.term, levelFilteredLogs, isRegexMode], () => {
       const searchTerm = searchState.term;
-      const logs = currentLogs();
+      const logs = levelFilteredLogs();
       const useRegex = isRegexMode();
 
       if (!searchTerm.trim()) {
@@ -196,6 +210,8 @@ export const useLogSearch = ({
     setIsRegexMode,
     regexError,
     selectedLogId,
+    minLevel,
+    setMinLevel,
     matchingLogIdSet,
     displayedLogs,
     onSearchChange,
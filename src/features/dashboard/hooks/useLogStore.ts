@@ -2,8 +2,7 @@ import { Events } from "@wailsio/runtime";
 import { createEffect, onCleanup } from "solid-js";
 import { createStore } from "solid-js/store";
 import type { ProcessLogData, WailsEvent, YamlConfig } from "@/types";
-import { LogType } from "@/types";
-import { isLiveUpdate } from "@/utils/ansiToHtml";
+import { mergeLogs } from "@/utils/logMerge";
 
 export type LogWithId = ProcessLogData & { id: string };
 
@@ -52,18 +51,7 @@ export const useLogStore = ({
       if (pending.length === 0) return;
 
       const current = logsByProcess[name] || [];
-      const updated = [...current];
-
-      pending.forEach((log) => {
-        const isUpdate = log.type !== LogType.EXIT && isLiveUpdate(log.output);
-
-        if (isUpdate && updated.length > 0) {
-          const lastLog = updated[updated.length - 1];
-          updated[updated.length - 1] = { ...log, id: lastLog.id };
-        } else {
-          updated.push({ ...log, id: crypto.randomUUID() });
-        }
-      });
+      const updated = mergeLogs(current, pending, () => crypto.randomUUID());
 
       const maxLogs = logBufferSize();
       if (updated.length > maxLogs) {
@@ -1,12 +1,28 @@
-import { ProcessService } from "@backend";
+import { ConfigService, ProcessService } from "@backend";
 import { Events } from "@wailsio/runtime";
-import { createEffect, createMemo, on, onCleanup } from "solid-js";
-import { createStore } from "solid-js/store";
+import {
+  createEffect,
+  createMemo,
+  createSignal,
+  on,
+  onCleanup,
+} from "solid-js";
+import { createStore, reconcile } from "solid-js/store";
+import { useSettingsContext } from "@/contexts";
 import { useToast } from "@/hooks";
 import type {
+  ConfigDiff,
+  LogsConfig,
+  ProcessAlertData,
+  ProcessConfig,
   ProcessCrashData,
   ProcessId,
+  ProcessReadyData,
   ProcessRestartData,
+  ProcessSpec,
+  ProcessStartedData,
+  ProcessUnhealthyData,
+  ProfileInfo,
   WailsEvent,
   YamlConfig,
 } from "@/types";
@@ -18,42 +34,82 @@ const POLL_STATUS_INTERVAL_MS = 1000;
 type UseProcessesParams = {
   yamlConfig: () => YamlConfig | null;
   rootDirectory: () => string | null;
+  configDiff: () => ConfigDiff | null;
 };
 
 export const useProcesses = ({
   yamlConfig,
   rootDirectory,
+  configDiff,
 }: UseProcessesParams) => {
   const toast = useToast();
+  const { settings } = useSettingsContext();
 
   const [processesData, setProcessesData] = createStore<
     Record<string, ProcessData>
   >({});
 
+  const [profiles, setProfiles] = createSignal<ProfileInfo[]>([]);
+
   let pollInterval: ReturnType<typeof setInterval> | null = null;
 
   const hasRunningProcesses = createMemo(() => {
     return Object.values(processesData).some((p) => isProcessActive(p.status));
   });
 
-  // Initialize process data when config changes
+  // Initialize process data when config changes. On reload, processes that are still
+  // in the config keep their state, and env values edited in the UI are kept.
   createEffect(
-    on(yamlConfig, (config) => {
+    on(yamlConfig, (config, previousConfig) => {
       if (!config?.processes) return;
       const initialProcessesData: Record<string, ProcessData> = {};
       config.processes.forEach((process) => {
+        const current = processesData[process.name];
+        const previousEnv =
+          previousConfig?.processes.find((p) => p.name === process.name)
+            ?.env ?? {};
+        const envValues = { ...process.env };
+        Object.keys(envValues).forEach((key) => {
+          const value = current?.envValues[key];
+          if (value !== undefined && value !== previousEnv[key]) {
+            envValues[key] = value;
+          }
+        });
         initialProcessesData[process.name] = {
           argValues: {},
-          envValues: process.env ? { ...process.env } : {},
           status: ProcessStatus.STOPPED,
+          ready: false,
           processId: null,
           startTime: null,
-          command: process.base_command,
           retryCount: 0,
+          alertCount: 0,
+          ...current,
+          envValues,
+          command: process.base_command ?? "",
           maxRetries: process.restart?.max_retries ?? 3,
         };
       });
-      setProcessesData(initialProcessesData);
+      // Processes removed from the config can no longer be managed, so they are stopped
+      Object.entries(processesData).forEach(([name, data]) => {
+        if (name in initialProcessesData) return;
+        if (data.processId && isProcessActive(data.status)) {
+          ProcessService.Stop(data.processId);
+        }
+      });
+      setProcessesData(reconcile(initialProcessesData));
+      // Apply arg defaults and argv quoting right away, even for rows whose args are never displayed
+      config.processes.forEach((process) => {
+        if (process.args?.length || process.command) {
+          refreshCommand(process.name);
+        }
+      });
+    }),
+  );
+
+  // List the profiles of the config, sorted by the backend
+  createEffect(
+    on(yamlConfig, async (config) => {
+      setProfiles(config ? await ConfigService.ListProfiles(config) : []);
     }),
   );
 
@@ -96,14 +152,72 @@ export const useProcesses = ({
 
     setProcessesData(processName, {
       status: ProcessStatus.RUNNING,
+      ready: false,
       startTime: new Date(),
       retryCount: data.retryCount,
       maxRetries: data.maxRetries,
     });
   };
 
-  // Set up crash and restart event listeners
+  // Track processes started outside this view (e.g. through the control API)
+  const handleProcessStarted = (data: ProcessStartedData) => {
+    const config = getProcessConfig(data.name);
+    const current = processesData[data.name];
+    if (!config || !current || current.processId === data.processId) return;
+    setProcessesData(data.name, {
+      processId: data.processId,
+      startTime: new Date(),
+      status: ProcessStatus.RUNNING,
+      ready: !config.ready_when,
+      retryCount: 0,
+      maxRetries: config.restart?.max_retries ?? 3,
+    });
+    startPolling();
+  };
+
+  // Handle readiness events
+  const handleProcessReady = (data: ProcessReadyData) => {
+    const processName = findProcessNameById(data.processId);
+    if (!processName) return;
+    setProcessesData(processName, "ready", true);
+  };
+
+  const handleProcessReadyTimeout = (data: ProcessReadyData) => {
+    const processName = findProcessNameById(data.processId);
+    if (!processName) return;
+    toast.error(`${processName} did not become ready`);
+  };
+
+  // Handle healthcheck failures (the crash/restart events follow once the process is killed)
+  const handleProcessUnhealthy = (data: ProcessUnhealthyData) => {
+    const processName = findProcessNameById(data.processId);
+    if (!processName) return;
+    toast.error(
+      `${processName} failed ${data.failures} healthchecks in a row, killing it`,
+    );
+  };
+
+  // Handle log alerts: counted as a badge, and notified when the alert asks for it
+  const handleProcessAlert = (data: ProcessAlertData) => {
+    const current = processesData[data.name];
+    if (!current || current.processId !== data.processId) return;
+    setProcessesData(data.name, "alertCount", current.alertCount + 1);
+    if (!data.notify) return;
+    const message = `${data.name}: ${data.line}`;
+    if (data.level === "info") {
+      toast.info(message);
+    } else {
+      toast.error(message);
+    }
+  };
+
+  // Set up start, crash, restart, readiness, health and alert event listeners
   createEffect(() => {
+    const offStarted = Events.On(
+      "process-started",
+      (event: WailsEvent<ProcessStartedData>) =>
+        handleProcessStarted(event.data),
+    );
     const offCrash = Events.On(
       "process-crash",
       (event: WailsEvent<ProcessCrashData>) => handleProcessCrash(event.data),
@@ -114,30 +228,73 @@ export const useProcesses = ({
         handleProcessRestart(event.data),
     );
 
+    const offReady = Events.On(
+      "process-ready",
+      (event: WailsEvent<ProcessReadyData>) => handleProcessReady(event.data),
+    );
+    const offReadyTimeout = Events.On(
+      "process-ready-timeout",
+      (event: WailsEvent<ProcessReadyData>) =>
+        handleProcessReadyTimeout(event.data),
+    );
+
+    const offUnhealthy = Events.On(
+      "process-unhealthy",
+      (event: WailsEvent<ProcessUnhealthyData>) =>
+        handleProcessUnhealthy(event.data),
+    );
+
+    const offAlert = Events.On(
+      "process-alert",
+      (event: WailsEvent<ProcessAlertData>) => handleProcessAlert(event.data),
+    );
+
     onCleanup(() => {
+      offStarted();
       offCrash();
       offRestart();
+      offReady();
+      offReadyTimeout();
+      offUnhealthy();
+      offAlert();
     });
   });
 
+  // Apply a config reload to running processes whose command, env or cwd changed
+  createEffect(
+    on(
+      configDiff,
+      (diff) => {
+        diff?.modified.forEach(async (change) => {
+          const data = processesData[change.name];
+          if (!change.restartRequired || !data) return;
+          if (!isProcessActive(data.status)) return;
+          if (!settings().restartOnConfigChange) {
+            toast.info(`${change.name} changed, restart it to apply`);
+            return;
+          }
+          await refreshCommand(change.name);
+          await restartProcess(change.name);
+        });
+      },
+      { defer: true },
+    ),
+  );
+
   // Helper to get process config
   const getProcessConfig = (processName: string) => {
     return yamlConfig()?.processes.find((p) => p.name === processName);
   };
 
-  // Helper to compute command
-  const computeCommand = (processName: string): string => {
+  // Rebuild the command from the current arg values (the backend owns command construction)
+  const refreshCommand = async (processName: string) => {
     const processConfig = getProcessConfig(processName);
     const data = processesData[processName];
-    if (!processConfig || !data) return "";
-
-    const outputArgs = Object.values(data.argValues);
-    let output = processConfig.base_command;
-    outputArgs.forEach((arg) => {
-      if (arg === "") return;
-      output = `${output} ${arg}`;
+    if (!processConfig || !data) return;
+    const command = await ConfigService.BuildCommand(processConfig, {
+      ...data.argValues,
     });
-    return output;
+    setProcessesData(processName, "command", command);
   };
 
   // Start or restart polling for all processes
@@ -163,7 +320,10 @@ export const useProcesses = ({
       }
 
       const processIds = activeProcesses.map((p) => p.pid);
-      const statusMap = await ProcessService.BulkStatus(processIds);
+      const [statusMap, readyMap] = await Promise.all([
+        ProcessService.BulkStatus(processIds),
+        ProcessService.BulkReadyStatus(processIds),
+      ]);
 
       activeProcesses.forEach(({ name, pid }) => {
         const currentStatus = processesData[name]?.status;
@@ -171,7 +331,10 @@ export const useProcesses = ({
 
         const isRunning = statusMap[pid];
         if (isRunning) {
-          setProcessesData(name, "status", ProcessStatus.RUNNING);
+          setProcessesData(name, {
+            status: ProcessStatus.RUNNING,
+            ready: readyMap[pid] ?? false,
+          });
         } else if (currentStatus === ProcessStatus.RUNNING) {
           setProcessesData(name, {
             status: ProcessStatus.STOPPED,
@@ -182,54 +345,96 @@ export const useProcesses = ({
     }, POLL_STATUS_INTERVAL_MS);
   };
 
+  // Resolve a path from the config relative to rootDirectory
+  const resolveRootPath = (path: string): string => {
+    if (path.startsWith("/")) return path;
+    const relativePath = path.startsWith("./") ? path.slice(2) : path;
+    return `${rootDirectory()!}/${relativePath}`;
+  };
+
   // Resolve cwd: if process has custom cwd, resolve it relative to rootDirectory
   const resolveProcessCwd = (
     processConfig: NonNullable<ReturnType<typeof getProcessConfig>>,
   ): string => {
-    const rootDir = rootDirectory()!;
-    if (!processConfig.cwd) return rootDir;
-    if (processConfig.cwd.startsWith("/")) return processConfig.cwd;
-    const relativeCwd = processConfig.cwd.startsWith("./")
-      ? processConfig.cwd.slice(2)
-      : processConfig.cwd;
-    return `${rootDir}/${relativeCwd}`;
+    if (!processConfig.cwd) return rootDirectory()!;
+    return resolveRootPath(processConfig.cwd);
+  };
+
+  // Processes without a logs setting are persisted when the app-wide setting is on
+  const resolveProcessLogs = (
+    processConfig: ProcessConfig,
+  ): LogsConfig | null => {
+    const logs =
+      processConfig.logs ?? (settings().persistLogs ? {} : undefined);
+    if (!logs) return null;
+    const fileName = processConfig.name.replace(/[^A-Za-z0-9._-]+/g, "-");
+    return {
+      ...logs,
+      file: resolveRootPath(logs.file || `logs/click-launch/${fileName}.log`),
+    };
+  };
+
+  // Build the launch spec the backend needs for dependency-aware start/stop
+  const buildProcessSpec = (processConfig: ProcessConfig): ProcessSpec => {
+    const storeEnv = processesData[processConfig.name]?.envValues;
+    return {
+      name: processConfig.name,
+      cwd: resolveProcessCwd(processConfig),
+      command: processesData[processConfig.name]?.command ?? "",
+      // argv commands are executed directly, without a shell
+      shell: processConfig.shell ?? (processConfig.command ? "none" : "sh"),
+      tty: processConfig.tty ?? false,
+      stdin: processConfig.stdin ?? false,
+      restart: processConfig.restart ? { ...processConfig.restart } : null,
+      stop: processConfig.stop ?? null,
+      logs: resolveProcessLogs(processConfig),
+      logFormat: processConfig.log_format ?? "text",
+      logRateLimit: processConfig.log_rate_limit ?? null,
+      multiline: processConfig.multiline ?? null,
+      alerts: processConfig.alerts ?? [],
+      env: storeEnv ? { ...storeEnv } : {},
+      envFiles: processConfig.env_file ?? [],
+      dependsOn: processConfig.depends_on ?? [],
+      readyWhen: processConfig.ready_when ?? null,
+      healthcheck: processConfig.healthcheck ?? null,
+    };
   };
 
+  const buildAllProcessSpecs = (): ProcessSpec[] =>
+    (yamlConfig()?.processes ?? []).map(buildProcessSpec);
+
+  // Starts the process after its dependencies (already-active ones are reused)
   const startProcess = async (processName: string) => {
     const processConfig = getProcessConfig(processName);
     if (!processConfig) return;
 
     setProcessesData(processName, "status", ProcessStatus.STARTING);
-    const command = computeCommand(processName);
-    const restartConfig = processConfig.restart
-      ? { ...processConfig.restart }
-      : null;
-    const cwd = resolveProcessCwd(processConfig);
-    const storeEnv = processesData[processName]?.envValues;
-    const env =
-      storeEnv && Object.keys(storeEnv).length > 0 ? { ...storeEnv } : {};
-    const envFile = processConfig.env_file ?? "";
-    const result = await ProcessService.Start(
-      cwd,
-      command,
-      restartConfig,
-      env,
-      envFile,
+    const results = await ProcessService.StartWithDependencies(
+      processName,
+      buildAllProcessSpecs(),
     );
 
-    if (result.success && result.processId) {
-      setProcessesData(processName, {
-        processId: result.processId,
-        startTime: new Date(),
-        status: ProcessStatus.RUNNING,
-        retryCount: 0,
-        maxRetries: processConfig.restart?.max_retries ?? 3,
-      });
-      startPolling();
-    } else {
-      setProcessesData(processName, "status", ProcessStatus.STOPPED);
-      toast.error(`Failed to start ${processName}`);
-    }
+    let hasStarted = false;
+    Object.entries(results).forEach(([name, result]) => {
+      const config = getProcessConfig(name);
+      if (!config) return;
+      if (result.success && result.processId) {
+        if (processesData[name]?.processId === result.processId) return;
+        setProcessesData(name, {
+          processId: result.processId,
+          startTime: new Date(),
+          status: ProcessStatus.RUNNING,
+          ready: !config.ready_when,
+          retryCount: 0,
+          maxRetries: config.restart?.max_retries ?? 3,
+        });
+        hasStarted = true;
+      } else {
+        setProcessesData(name, "status", ProcessStatus.STOPPED);
+        toast.error(`Failed to start ${name}`);
+      }
+    });
+    if (hasStarted) startPolling();
   };
 
   const stopProcess = async (processName: string) => {
@@ -250,11 +455,76 @@ export const useProcesses = ({
     }
   };
 
+  // Returns the active processes that depend on this one, in stop order
+  const getActiveDependents = async (processName: string) => {
+    const config = yamlConfig();
+    if (!config) return [];
+    const dependents = await ConfigService.GetDependents(config, processName);
+    return dependents.filter((name) =>
+      isProcessActive(processesData[name]?.status ?? ProcessStatus.STOPPED),
+    );
+  };
+
+  const stopProcessWithDependents = async (processName: string) => {
+    const dependents = await getActiveDependents(processName);
+    [...dependents, processName].forEach((name) => {
+      setProcessesData(name, "status", ProcessStatus.STOPPING);
+    });
+    const results = await ProcessService.StopWithDependents(
+      processName,
+      buildAllProcessSpecs(),
+    );
+
+    Object.entries(results).forEach(([name, result]) => {
+      if (result.success) {
+        setProcessesData(name, {
+          status: ProcessStatus.STOPPED,
+          startTime: null,
+        });
+      } else {
+        setProcessesData(name, "status", ProcessStatus.RUNNING);
+        toast.error(`Failed to stop ${name}`);
+      }
+    });
+  };
+
   const restartProcess = async (processName: string) => {
     await stopProcess(processName);
     await startProcess(processName);
   };
 
+  // Applies the arg values and env of a profile to its processes, then starts them (with their dependencies)
+  const launchProfile = async (profileName: string) => {
+    const profile = profiles().find((p) => p.name === profileName);
+    if (!profile) return;
+    const overridden = new Set([
+      ...Object.keys(profile.args ?? {}),
+      ...Object.keys(profile.env ?? {}),
+    ]);
+    await Promise.all(
+      [...overridden].map(async (processName) => {
+        if (!processesData[processName]) return;
+        Object.entries(profile.args?.[processName] ?? {}).forEach(
+          ([argName, value]) => {
+            setProcessesData(processName, "argValues", argName, value);
+          },
+        );
+        Object.entries(profile.env?.[processName] ?? {}).forEach(
+          ([key, value]) => {
+            setProcessesData(processName, "envValues", key, value);
+          },
+        );
+        await refreshCommand(processName);
+      }),
+    );
+    for (const processName of profile.processes) {
+      const status = processesData[processName]?.status;
+      if (status && !isProcessActive(status)) {
+        await startProcess(processName);
+      }
+    }
+  };
+
   // Context API methods
   const getProcessData = (processName: string): ProcessData | undefined => {
     return processesData[processName];
@@ -291,11 +561,15 @@ export const useProcesses = ({
   const setArgValues = (
     processName: string,
     argName: string,
-    value: string,
+    value: unknown,
   ) => {
     setProcessesData(processName, "argValues", argName, value);
-    const newCommand = computeCommand(processName);
-    setProcessesData(processName, "command", newCommand);
+    refreshCommand(processName);
+  };
+
+  const clearProcessAlerts = (processName: string) => {
+    if (!processesData[processName]) return;
+    setProcessesData(processName, "alertCount", 0);
   };
 
   return {
@@ -312,6 +586,11 @@ export const useProcesses = ({
     setEnvValue,
     startProcess,
     stopProcess,
+    stopProcessWithDependents,
+    getActiveDependents,
     restartProcess,
+    clearProcessAlerts,
+    profiles,
+    launchProfile,
   };
 };
@@ -0,0 +1,74 @@
+import { ProcessService } from "@backend";
+import { createEffect, onCleanup } from "solid-js";
+
+// Padding of the log container (p-4 on both sides)
+const CONTAINER_PADDING_PX = 32;
+const RESIZE_DEBOUNCE_MS = 150;
+const CELL_PROBE_LENGTH = 10;
+
+type UseTerminalSizeParams = {
+  processId: () => string | null;
+  isTTY: () => boolean;
+  isOpen: () => boolean;
+};
+
+// Measures one character of the log font (font-mono text-sm)
+const measureCell = (container: HTMLElement) => {
+  const probe = document.createElement("span");
+  probe.className = "font-mono text-sm invisible absolute";
+  probe.textContent = "M".repeat(CELL_PROBE_LENGTH);
+  container.appendChild(probe);
+  const { width, height } = probe.getBoundingClientRect();
+  probe.remove();
+  return { width: width / CELL_PROBE_LENGTH, height };
+};
+
+/**
+ * Keeps the terminal of a `tty: true` process the size of the log drawer,
+ * so that progress bars and line editing wrap where the logs do.
+ */
+export const useTerminalSize = ({
+  processId,
+  isTTY,
+  isOpen,
+}: UseTerminalSizeParams) => {
+  let containerRef: HTMLDivElement | undefined;
+
+  const setContainerRef = (el: HTMLDivElement) => {
+    containerRef = el;
+  };
+
+  createEffect(() => {
+    const id = processId();
+    const container = containerRef;
+    if (!isOpen() || !isTTY() || !id || !container) return;
+
+    let resizeTimer: number | null = null;
+
+    const resize = () => {
+      const cell = measureCell(container);
+      if (cell.width === 0 || cell.height === 0) return;
+      const cols = Math.floor(
+        (container.clientWidth - CONTAINER_PADDING_PX) / cell.width,
+      );
+      const rows = Math.floor(
+        (container.clientHeight - CONTAINER_PADDING_PX) / cell.height,
+      );
+      ProcessService.ResizeTerminal(id, cols, rows);
+    };
+
+    const observer = new ResizeObserver(() => {
+      if (resizeTimer !== null) clearTimeout(resizeTimer);
+      resizeTimer = window.setTimeout(resize, RESIZE_DEBOUNCE_MS);
+    });
+    observer.observe(container);
+    resize();
+
+    onCleanup(() => {
+      observer.disconnect();
+      if (resizeTimer !== null) clearTimeout(resizeTimer);
+    });
+  });
+
+  return { setContainerRef };
+};
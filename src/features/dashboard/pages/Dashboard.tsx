@@ -15,7 +15,7 @@ import { BaseLayout, HeroLayout } from "@/components/layout";
 import { GoHomeButton, LoadingRing, Modal, ScreenTitle } from "@/components/ui";
 import { useToast } from "@/hooks";
 import { routePaths } from "@/routes";
-import { ErrorList, ProcessTable } from "../components";
+import { ErrorList, ProcessTable, ProfileButtons } from "../components";
 import { DashboardProvider, useDashboardContext } from "../contexts";
 
 export type DashboardRouteKey = "dashboard";
@@ -129,6 +129,7 @@ const Dashboard = () => {
                 />
               </label>
             </div>
+            <ProfileButtons />
             <ProcessTable hideIdle={hideIdle()} />
           </BaseLayout>
         </Match>
@@ -117,6 +117,20 @@ export const SettingsModal = (props: SettingsModalProps) => {
               </SettingsRow>
             </SettingsSection>
 
+            <SettingsSection title="Processes">
+              <SettingsRow
+                label="Restart on config change"
+                tooltip="Restart running processes whose command, env or cwd changed when the config file is edited"
+              >
+                <input
+                  type="checkbox"
+                  class="toggle toggle-sm toggle-primary"
+                  checked={settings().restartOnConfigChange}
+                  onChange={handleToggle("restartOnConfigChange")}
+                />
+              </SettingsRow>
+            </SettingsSection>
+
             <SettingsSection title="Logs">
               <SettingsRow
                 label="Show timestamps"
@@ -129,6 +143,17 @@ export const SettingsModal = (props: SettingsModalProps) => {
                   onChange={handleToggle("showTimestamps")}
                 />
               </SettingsRow>
+              <SettingsRow
+                label="Save logs to files"
+                tooltip="Write the logs of every process to rotated files in logs/click-launch (processes with a logs setting always do)"
+              >
+                <input
+                  type="checkbox"
+                  class="toggle toggle-sm toggle-primary"
+                  checked={settings().persistLogs}
+                  onChange={handleToggle("persistLogs")}
+                />
+              </SettingsRow>
               <SettingsRow
                 label="Log buffer size"
                 tooltip={`How many logs are kept in memory (from ${MIN_LOG_BUFFER_SIZE.toLocaleString()} to ${MAX_LOG_BUFFER_SIZE.toLocaleString()})`}
@@ -10,3 +10,15 @@ export enum LogType {
   EXIT = "exit",
   ERROR = "error",
 }
+
+// From least to most severe
+export enum LogLevel {
+  TRACE = "trace",
+  DEBUG = "debug",
+  INFO = "info",
+  WARN = "warn",
+  ERROR = "error",
+  FATAL = "fatal",
+}
+
+export const LOG_LEVELS = Object.values(LogLevel);
@@ -1,4 +1,4 @@
-import type { ArgType, LogType } from "./enums";
+import type { ArgType, LogLevel, LogType } from "./enums";
 
 export type WailsEvent<T> = { data: T };
 
@@ -11,28 +11,120 @@ export type RestartConfig = {
   reset_after_ms?: number; // Default: 30000
 };
 
+export type StopConfig = {
+  signal?: StopSignal; // Default: SIGTERM
+  timeout_ms?: number; // Default: 10000
+  command?: string;
+};
+
+export type LogsConfig = {
+  file?: string; // Default: logs/click-launch/<name>.log
+  max_size_mb?: number; // Default: 10
+  max_files?: number; // Default: 5
+};
+
+export type MultilineConfig = {
+  start_pattern?: string; // Default: indented lines continue the previous one
+  timeout_ms?: number; // Default: 200
+};
+
+export type AlertConfig = {
+  pattern: string;
+  level?: AlertLevel; // Default: error
+  notify?: boolean; // Default: false
+};
+
+export type AlertLevel = "info" | "warn" | "error";
+
+export type StopSignal =
+  | "SIGINT"
+  | "SIGTERM"
+  | "SIGQUIT"
+  | "SIGHUP"
+  | "SIGKILL"
+  | "SIGUSR1"
+  | "SIGUSR2";
+
+export type ReadyConfig = {
+  tcp?: number;
+  http?: string;
+  log_match?: string;
+  command?: string;
+  timeout_ms?: number; // Default: 60000
+  interval_ms?: number; // Default: 500
+};
+
+export type HealthConfig = {
+  tcp?: number;
+  http?: string;
+  command?: string;
+  interval_ms?: number; // Default: 10000
+  timeout_ms?: number; // Default: 2000
+  failure_threshold?: number; // Default: 3
+};
+
 export type ValidationResult = {
   isValid: boolean;
   config: YamlConfig | null;
   rootDirectory?: string;
+  includedFiles?: string[];
   errors: {
     message: string;
     path?: string;
+    file?: string;
+    line?: number;
+    column?: number;
   }[];
 };
 
 export type ValidationError = ValidationResult["errors"][0];
 
+export type ProcessDiff = {
+  name: string;
+  fields: string[]; // YAML keys, e.g. "base_command"
+  restartRequired: boolean;
+};
+
+export type ConfigDiff = {
+  added: string[];
+  removed: string[];
+  modified: ProcessDiff[];
+};
+
+export type ConfigChangedData = {
+  result: ValidationResult;
+  diff: ConfigDiff; // Empty when the new config is invalid
+};
+
 export type YamlConfig = {
   project_name: string;
+  include?: string[];
+  env_file?: string[]; // Already prepended to the env files of every process
+  vars?: Record<string, string>; // As written, already interpolated in the processes
+  profiles?: Record<string, ProfileConfig>;
+  // `defaults` and `templates` are already applied to the processes by the backend
   processes: {
     name: string;
-    base_command: string;
+    extends?: string; // Template the process inherits from
+    base_command?: string;
+    command?: string[];
+    shell?: ProcessShell;
+    tty?: boolean;
+    stdin?: boolean;
     group?: string;
     cwd?: string;
     env?: ProcessEnv;
-    env_file?: string;
+    env_file?: string[]; // Always a list (optional files end with "?")
     restart?: RestartConfig;
+    stop?: StopConfig;
+    logs?: LogsConfig;
+    log_format?: LogFormat;
+    log_rate_limit?: number; // Default: 1000 lines per second
+    multiline?: MultilineConfig;
+    alerts?: AlertConfig[];
+    depends_on?: string[];
+    ready_when?: ReadyConfig;
+    healthcheck?: HealthConfig;
     args?: {
       type: ArgType;
       name: string;
@@ -47,11 +139,46 @@ export type YamlConfig = {
 };
 
 export type ProcessConfig = YamlConfig["processes"][0];
+
+// Args and env are keyed by process name
+export type ProfileConfig = {
+  description?: string;
+  processes: string[];
+  args?: Record<string, Record<string, unknown>>;
+  env?: Record<string, ProcessEnv>;
+};
+
+export type ProfileInfo = ProfileConfig & { name: string };
 export type ArgConfig = NonNullable<ProcessConfig["args"]>[0];
 export type ArgValue = NonNullable<ArgConfig["values"]>[0];
 
 export type ProcessId = string;
 
+export type ProcessShell = "sh" | "bash" | "zsh" | "none";
+
+export type LogFormat = "json" | "logfmt" | "text";
+
+export type ProcessSpec = {
+  name: string;
+  cwd: string;
+  command: string;
+  shell?: ProcessShell;
+  tty?: boolean;
+  stdin?: boolean;
+  restart?: RestartConfig | null;
+  stop?: StopConfig | null;
+  logs?: LogsConfig | null;
+  logFormat?: LogFormat;
+  logRateLimit?: number | null;
+  multiline?: MultilineConfig | null;
+  alerts?: AlertConfig[];
+  env?: ProcessEnv;
+  envFiles?: string[]; // Loaded in order, "?" marks optional files
+  dependsOn?: string[];
+  readyWhen?: ReadyConfig | null;
+  healthcheck?: HealthConfig | null;
+};
+
 export type ProcessStartResult = {
   success: boolean;
   processId?: ProcessId;
@@ -68,13 +195,28 @@ export type ProcessStopResult = {
   error?: string;
 };
 
+export type ProcessStdinResult = {
+  success: boolean;
+  error?: string;
+};
+
 export type ProcessLogData = {
   processId: ProcessId;
+  cursor: number;
   timestamp: string;
 } & (
   | {
       type: Exclude<LogType, LogType.EXIT>;
       output: string;
+      // Set for structured lines (log_format), where output is the message
+      level?: LogLevel;
+      fields?: Record<string, unknown>;
+      // Set for a line still missing its newline (prompt, progress bar)
+      partial?: boolean;
+      // Set when this entry replaces the previous one (partial line or \r overwrite)
+      replace?: boolean;
+      // Set on the entry reporting lines dropped by the log rate limit
+      suppressed?: number;
     }
   | {
       type: LogType.EXIT;
@@ -83,6 +225,17 @@ export type ProcessLogData = {
     }
 );
 
+export type ProcessLogsResult = {
+  logs: ProcessLogData[];
+  cursor: number;
+  hasMore: boolean;
+};
+
+export type ProcessLogSearchResult = {
+  logs: ProcessLogData[];
+  error?: string;
+};
+
 export type BulkProcessStatusResult = Record<ProcessId, boolean>;
 
 export type ProcessResourceData = {
@@ -105,10 +258,38 @@ export type ProcessRestartData = {
   timestamp: string;
 };
 
+export type ProcessStartedData = {
+  processId: ProcessId;
+  name: string;
+  timestamp: string;
+};
+
+export type ProcessReadyData = {
+  processId: ProcessId;
+  timestamp: string;
+};
+
+export type ProcessUnhealthyData = {
+  processId: ProcessId;
+  failures: number;
+  timestamp: string;
+};
+
+export type ProcessAlertData = {
+  processId: ProcessId;
+  name: string;
+  pattern: string;
+  level: AlertLevel;
+  notify: boolean;
+  line: string;
+  timestamp: string;
+};
+
 export type ProcessCrashData = {
   processId: ProcessId;
   exitCode: number | null;
   signal: string | null;
   willRestart: boolean;
+  unhealthy?: boolean;
   timestamp: string;
 };
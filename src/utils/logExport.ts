@@ -8,6 +8,17 @@ const ANSI_REGEX = /\x1b\[[0-9;]*[A-Za-z]/g;
 export const stripAnsiCodes = (text: string): string =>
   text.replace(ANSI_REGEX, "");
 
+// Renders structured log fields as sorted logfmt pairs, like the backend log files
+export const formatLogFields = (fields: Record<string, unknown>): string =>
+  Object.keys(fields)
+    .sort()
+    .map((key) => {
+      const raw = fields[key];
+      const value = typeof raw === "string" ? raw : JSON.stringify(raw);
+      return `${key}=${value === "" || /[\s"=]/.test(value) ? JSON.stringify(value) : value}`;
+    })
+    .join(" ");
+
 const formatTimestamp = (iso: string): string => {
   const date = new Date(iso);
   const pad = (n: number) => n.toString().padStart(2, "0");
@@ -27,7 +38,9 @@ export const formatLogsAsText = (
         log.code !== null ? `code ${log.code}` : `signal ${log.signal}`;
       return `[${ts}] [exit] Process exited with ${detail}`;
     }
-    return `[${ts}] [${log.type}] ${stripAnsiCodes(log.output).trimEnd()}`;
+    const level = log.level ? `${log.level.toUpperCase()} ` : "";
+    const fields = log.fields ? ` ${formatLogFields(log.fields)}` : "";
+    return `[${ts}] [${log.type}] ${level}${stripAnsiCodes(log.output).trimEnd()}${fields}`;
   });
 
   return `${header + lines.join("\n")}\n`;
@@ -0,0 +1,81 @@
+import { describe, expect, it } from "vitest";
+import type { ProcessLogData } from "@/types";
+import { LogType } from "@/types";
+import { mergeLogs } from "./logMerge";
+
+const log = (
+  type: LogType.STDOUT | LogType.STDERR,
+  output: string,
+  flags: { partial?: boolean; replace?: boolean } = {},
+): ProcessLogData => ({
+  processId: "api",
+  cursor: 0,
+  timestamp: "",
+  type,
+  output,
+  ...flags,
+});
+
+const outputs = (logs: ProcessLogData[]) =>
+  logs.map((entry) => (entry.type === LogType.EXIT ? "" : entry.output));
+
+let nextId = 0;
+const newId = () => String(nextId++);
+
+describe("mergeLogs", () => {
+  it("replaces the preview of the same stream when stdout and stderr interleave", () => {
+    const merged = mergeLogs(
+      [],
+      [
+        log(LogType.STDOUT, "progress 10%\n", { partial: true }),
+        log(LogType.STDERR, "warning\n"),
+        log(LogType.STDOUT, "progress 50%\n", { partial: true, replace: true }),
+        log(LogType.STDOUT, "progress 100%\n", { replace: true }),
+      ],
+      newId,
+    );
+    expect(outputs(merged)).toEqual(["progress 100%\n", "warning\n"]);
+  });
+
+  it("keeps the id of the replaced preview", () => {
+    const first = mergeLogs(
+      [],
+      [log(LogType.STDOUT, "loading\n", { partial: true })],
+      newId,
+    );
+    const merged = mergeLogs(
+      first,
+      [log(LogType.STDOUT, "loaded\n", { replace: true })],
+      newId,
+    );
+    expect(merged).toHaveLength(1);
+    expect(merged[0].id).toBe(first[0].id);
+  });
+
+  it("appends a replace entry whose preview was never received", () => {
+    const merged = mergeLogs(
+      [],
+      [
+        log(LogType.STDOUT, "previous line\n"),
+        log(LogType.STDOUT, "progress 100%\n", { replace: true }),
+      ],
+      newId,
+    );
+    expect(outputs(merged)).toEqual(["previous line\n", "progress 100%\n"]);
+  });
+
+  it("only replaces previews of the same process", () => {
+    const merged = mergeLogs(
+      [],
+      [
+        log(LogType.STDOUT, "loading\n", { partial: true }),
+        {
+          ...log(LogType.STDOUT, "done\n", { replace: true }),
+          processId: "api-restarted",
+        },
+      ],
+      newId,
+    );
+    expect(outputs(merged)).toEqual(["loading\n", "done\n"]);
+  });
+});
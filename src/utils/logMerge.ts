@@ -0,0 +1,47 @@
+import type { ProcessLogData } from "@/types";
+import { LogType } from "@/types";
+import { isLiveUpdate } from "./ansiToHtml";
+
+type IdentifiedLog = ProcessLogData & { id: string };
+
+// Index of the entry a replace entry replaces: the latest entry of the same process and stream, if it
+// is a preview. -1 when that preview was never received (e.g. dropped by the log rate limit).
+export const findReplacedPreview = (
+  logs: IdentifiedLog[],
+  log: ProcessLogData,
+): number => {
+  for (let i = logs.length - 1; i >= 0; i--) {
+    const entry = logs[i];
+    if (entry.processId !== log.processId || entry.type !== log.type) continue;
+    return entry.type !== LogType.EXIT && entry.partial ? i : -1;
+  }
+  return -1;
+};
+
+// Appends new log entries to a list, applying the entries that update a previous one in place
+export const mergeLogs = (
+  logs: IdentifiedLog[],
+  pending: ProcessLogData[],
+  newId: () => string,
+): IdentifiedLog[] => {
+  const updated = [...logs];
+  pending.forEach((log) => {
+    if (log.type !== LogType.EXIT && log.replace) {
+      const index = findReplacedPreview(updated, log);
+      if (index >= 0) {
+        updated[index] = { ...log, id: updated[index].id };
+        return;
+      }
+    } else if (
+      log.type !== LogType.EXIT &&
+      isLiveUpdate(log.output) &&
+      updated.length > 0
+    ) {
+      const lastLog = updated[updated.length - 1];
+      updated[updated.length - 1] = { ...log, id: lastLog.id };
+      return;
+    }
+    updated.push({ ...log, id: newId() });
+  });
+  return updated;
+};